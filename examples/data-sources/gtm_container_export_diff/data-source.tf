@@ -0,0 +1,7 @@
+data "gtm_container_export_diff" "migration" {
+  export_json = file("${path.module}/GTM-XXXXXX_v12.json")
+}
+
+output "tags_to_create" {
+  value = [for change in data.gtm_container_export_diff.migration.create : change.name if change.kind == "tag"]
+}
@@ -0,0 +1,5 @@
+data "gtm_containers" "all" {}
+
+output "container_ids" {
+  value = { for container in data.gtm_containers.all.containers : container.public_id => container.container_id }
+}
@@ -0,0 +1,5 @@
+data "gtm_environments" "all" {}
+
+output "environment_ids" {
+  value = { for environment in data.gtm_environments.all.environments : environment.name => environment.environment_id }
+}
@@ -0,0 +1,5 @@
+data "gtm_latest_version" "current" {}
+
+output "latest_version_id" {
+  value = data.gtm_latest_version.current.id
+}
@@ -0,0 +1,7 @@
+data "gtm_tag" "purchase" {
+  name = "Purchase"
+}
+
+output "purchase_tag_json" {
+  value = data.gtm_tag.purchase.json
+}
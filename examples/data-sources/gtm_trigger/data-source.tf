@@ -0,0 +1,7 @@
+data "gtm_trigger" "checkout" {
+  name = "Checkout"
+}
+
+output "checkout_trigger_json" {
+  value = data.gtm_trigger.checkout.json
+}
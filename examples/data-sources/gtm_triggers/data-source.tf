@@ -0,0 +1,5 @@
+data "gtm_triggers" "all" {}
+
+output "trigger_ids" {
+  value = { for trigger in data.gtm_triggers.all.triggers : trigger.name => trigger.id }
+}
@@ -0,0 +1,16 @@
+data "gtm_variable" "page_url" {
+  name = "Page URL"
+}
+
+resource "gtm_tag" "page_view" {
+  name = "Page view"
+  type = "html"
+
+  parameter = [
+    {
+      key   = "html"
+      type  = "template"
+      value = "<script>console.log(${data.gtm_variable.page_url.reference});</script>"
+    }
+  ]
+}
@@ -0,0 +1,5 @@
+data "gtm_variables" "all" {}
+
+output "variable_ids" {
+  value = { for variable in data.gtm_variables.all.variables : variable.name => variable.id }
+}
@@ -0,0 +1,12 @@
+data "gtm_workspace_compiler_status" "current" {}
+
+resource "gtm_version" "release" {
+  name = "Release"
+
+  lifecycle {
+    precondition {
+      condition     = data.gtm_workspace_compiler_status.current.compiles
+      error_message = "The workspace does not compile."
+    }
+  }
+}
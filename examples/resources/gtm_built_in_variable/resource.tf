@@ -0,0 +1,3 @@
+resource "gtm_built_in_variable" "click_text" {
+  type = "clickText"
+}
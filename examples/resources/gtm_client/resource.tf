@@ -0,0 +1,20 @@
+resource "gtm_client" "ga4" {
+  name     = "GA4"
+  type     = "gaaw_client"
+  priority = 10
+
+  parameter = [
+    {
+      key   = "activateDefaultPaths"
+      type  = "boolean"
+      value = "false"
+    },
+    {
+      key  = "customPaths"
+      type = "list"
+      list = [
+        { type = "template", value = "/collect" },
+      ]
+    },
+  ]
+}
@@ -0,0 +1,4 @@
+resource "gtm_container_config" "server" {
+  tagging_server_urls = ["https://sgtm.example.com"]
+  notes               = "Server container managed by Terraform"
+}
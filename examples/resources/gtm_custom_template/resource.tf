@@ -0,0 +1,11 @@
+resource "gtm_custom_template" "example" {
+  name          = "Example Template"
+  template_data = file("${path.module}/template.tpl")
+
+  gallery_reference = {
+    host       = "github.com"
+    owner      = "example"
+    repository = "gtm-template"
+    version    = "1.0.0"
+  }
+}
@@ -0,0 +1,13 @@
+resource "gtm_environment" "staging" {
+  name         = "Staging"
+  description  = "Managed by Terraform"
+  url          = "https://staging.example.com"
+  enable_debug = true
+}
+
+data "gtm_latest_version" "current" {}
+
+resource "gtm_environment" "qa" {
+  name                 = "QA"
+  container_version_id = data.gtm_latest_version.current.id
+}
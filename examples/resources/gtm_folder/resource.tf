@@ -0,0 +1,5 @@
+resource "gtm_folder" "analytics" {
+  name    = "Analytics"
+  notes   = "Managed by Terraform"
+  tag_ids = [gtm_tag.test_tag_1.id]
+}
@@ -0,0 +1,31 @@
+resource "gtm_gtag_config" "ga4" {
+  type = "googtag"
+
+  parameter = [
+    {
+      key   = "tagId"
+      type  = "template"
+      value = "G-XXXXXXXXXX"
+    },
+    {
+      key  = "configSettingsTable"
+      type = "list"
+      list = [
+        {
+          type = "map"
+          map = [
+            { key = "parameter", type = "template", value = "send_page_view" },
+            { key = "parameterValue", type = "template", value = "false" },
+          ]
+        },
+        {
+          type = "map"
+          map = [
+            { key = "parameter", type = "template", value = "cookie_domain" },
+            { key = "parameterValue", type = "template", value = "auto" },
+          ]
+        },
+      ]
+    },
+  ]
+}
@@ -0,0 +1,6 @@
+resource "gtm_version" "release" {
+  name        = "Release 42"
+  description = "Created by CI"
+
+  depends_on = [gtm_tag.page_view]
+}
@@ -4,14 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
+	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
 	"google.golang.org/api/googleapi"
+	"google.golang.org/api/impersonate"
 	"google.golang.org/api/option"
 	"google.golang.org/api/tagmanager/v2"
+	htransport "google.golang.org/api/transport/http"
 )
 
 // Environment variable names for client configuration
@@ -24,6 +31,33 @@ const (
 	EnvRateLimit       = "GTM_RATE_LIMIT"       // requests per second
 	EnvRateBurst       = "GTM_RATE_BURST"       // burst capacity
 	EnvThrottleEnabled = "GTM_THROTTLE_ENABLED" // enable/disable throttling
+	EnvMaxRetryAfter   = "GTM_MAX_RETRY_AFTER"  // seconds
+	EnvRateJitter      = "GTM_RATE_JITTER"      // milliseconds
+
+	EnvMaxIdleConns        = "GTM_MAX_IDLE_CONNS"
+	EnvMaxIdleConnsPerHost = "GTM_MAX_IDLE_CONNS_PER_HOST"
+	EnvIdleConnTimeout     = "GTM_IDLE_CONN_TIMEOUT" // seconds
+
+	EnvRequestTimeout   = "GTM_REQUEST_TIMEOUT"    // seconds
+	EnvMaxRetryDuration = "GTM_MAX_RETRY_DURATION" // seconds
+)
+
+// DefaultMaxRetryAfter is the longest Retry-After wait honored when MaxRetryAfter is
+// not set.
+const DefaultMaxRetryAfter = 5 * time.Minute
+
+// timeoutRetryStep is the wait before the first retry of an attempt that exceeded
+// RequestTimeout, which grows by the same step with every retry. It is short, since
+// the API answered no request that would ask to slow down.
+const timeoutRetryStep = 100 * time.Millisecond
+
+// Connection pool defaults used when the corresponding ClientOptions are not set. All
+// requests go to the same host, so it may keep as many idle connections as the default
+// rate limiter burst instead of the two of http.DefaultTransport.
+const (
+	DefaultMaxIdleConns        = 100
+	DefaultMaxIdleConnsPerHost = 20
+	DefaultIdleConnTimeout     = 90 * time.Second
 )
 
 // RateLimiter implements a token bucket rate limiter
@@ -32,17 +66,38 @@ type RateLimiter struct {
 	capacity   float64
 	refillRate float64
 	lastRefill time.Time
+	jitter     time.Duration
+	random     func() float64
 	mutex      sync.Mutex
 }
 
+// RateLimiterOption configures optional behavior of a RateLimiter.
+type RateLimiterOption func(*RateLimiter)
+
+// WithRefillJitter adds a random delay of up to jitter to every wait for a refilled
+// token, so that clients started together spread their requests instead of hitting
+// the API in lockstep once their bursts are consumed.
+func WithRefillJitter(jitter time.Duration) RateLimiterOption {
+	return func(rl *RateLimiter) {
+		rl.jitter = jitter
+	}
+}
+
 // NewRateLimiter creates a new rate limiter
-func NewRateLimiter(rate float64, burst int) *RateLimiter {
-	return &RateLimiter{
+func NewRateLimiter(rate float64, burst int, opts ...RateLimiterOption) *RateLimiter {
+	rl := &RateLimiter{
 		tokens:     float64(burst),
 		capacity:   float64(burst),
 		refillRate: rate,
 		lastRefill: time.Now(),
+		random:     rand.Float64,
+	}
+
+	for _, opt := range opts {
+		opt(rl)
 	}
+
+	return rl
 }
 
 // Allow checks if a request can proceed
@@ -67,20 +122,26 @@ func (rl *RateLimiter) Allow() bool {
 // Wait blocks until a token is available
 func (rl *RateLimiter) Wait() {
 	for !rl.Allow() {
-		// Calculate how long to wait for the next token
-		rl.mutex.Lock()
-		waitTime := time.Duration(1000/rl.refillRate) * time.Millisecond
-		rl.mutex.Unlock()
-
-		// Wait at least 10ms, but no more than 1 second
-		if waitTime < 10*time.Millisecond {
-			waitTime = 10 * time.Millisecond
-		} else if waitTime > 1*time.Second {
-			waitTime = 1 * time.Second
-		}
+		time.Sleep(rl.refillWait())
+	}
+}
 
-		time.Sleep(waitTime)
+// refillWait returns how long to wait for the next token, including the refill jitter.
+func (rl *RateLimiter) refillWait() time.Duration {
+	// Calculate how long to wait for the next token
+	rl.mutex.Lock()
+	waitTime := time.Duration(1000/rl.refillRate) * time.Millisecond
+	jitter := time.Duration(float64(rl.jitter) * rl.random())
+	rl.mutex.Unlock()
+
+	// Wait at least 10ms, but no more than 1 second
+	if waitTime < 10*time.Millisecond {
+		waitTime = 10 * time.Millisecond
+	} else if waitTime > 1*time.Second {
+		waitTime = 1 * time.Second
 	}
+
+	return waitTime + jitter
 }
 
 // min returns the minimum of two float64 values
@@ -95,19 +156,74 @@ type ClientOptions struct {
 	CredentialFile  string
 	AccountId       string
 	ContainerId     string
-	RetryLimit      int
+	RetryLimit      int     // retries on 429 before giving up, 0 disables retries
 	RateLimit       float64 // requests per second
 	RateBurst       int     // burst capacity
 	ThrottleEnabled bool    // enable/disable throttling
+
+	// RateJitter is the longest random delay added to each wait for a refilled token,
+	// which spreads the requests of clients started together. Zero disables it.
+	RateJitter time.Duration
+
+	// ContainerPublicId is the public ID of the container, e.g. GTM-XXXXXX. It is set
+	// when the container is configured by its public ID and read lazily otherwise.
+	ContainerPublicId string
+
+	// MaxRetryAfter caps the wait requested by the Retry-After header of a rate-limited
+	// response, so that a misbehaving proxy cannot stall a run for hours. Zero uses
+	// DefaultMaxRetryAfter.
+	MaxRetryAfter time.Duration
+
+	// AutoResolveConflicts retries an update once with the latest fingerprint when it
+	// fails with a conflict. The retry overwrites concurrent edits of the entity.
+	AutoResolveConflicts bool
+
+	// ValidateAccess reads the container when the client is created, so that missing
+	// credentials or permissions are reported before the first resource operation.
+	ValidateAccess bool
+
+	// ConfirmDeletes polls a deleted tag, trigger or variable until GTM stops serving
+	// it, so that a deletion is only reported once it is visible to later reads.
+	ConfirmDeletes bool
+
+	// Scopes are the OAuth scopes requested for the credentials. The scopes of the
+	// tagmanager package are requested when empty.
+	Scopes []string
+
+	// CredentialsJSON are the contents of a credential file, which take precedence over
+	// CredentialFile. See CredentialSource for the precedence of all sources.
+	CredentialsJSON string
+
+	// ImpersonateServiceAccount is the email of a service account impersonated with the
+	// application default credentials when no credentials are configured.
+	ImpersonateServiceAccount string
+
+	// MaxIdleConns, MaxIdleConnsPerHost and IdleConnTimeout tune the connection pool of
+	// the HTTP transport, so that parallel requests reuse connections. Zero uses
+	// DefaultMaxIdleConns, DefaultMaxIdleConnsPerHost and DefaultIdleConnTimeout.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+
+	// RequestTimeout bounds a single attempt of a request. An attempt that exceeds it is
+	// retried like a rate-limited one, except for creates, which GTM may have applied
+	// despite the timeout. Zero lets attempts run without a timeout.
+	RequestTimeout time.Duration
+
+	// MaxRetryDuration bounds all the attempts of a request, including the waits
+	// between them. No retry is made that would end after it, even when RetryLimit
+	// allows more. Zero only bounds the retries by RetryLimit.
+	MaxRetryDuration time.Duration
 }
 
 // NewClientOptionsFromEnv creates ClientOptions from environment variables
 func NewClientOptionsFromEnv() *ClientOptions {
-	retryLimit := 10 // Default retry limit
-	if os.Getenv(EnvRetryLimit) != "" {
-		// Ignoring error handling for simplicity, will use default on error
-		if retryLimitVal, err := fmt.Sscanf(os.Getenv(EnvRetryLimit), "%d", &retryLimit); err != nil || retryLimitVal <= 0 {
-			retryLimit = 10
+	// Default retry limit. Zero disables retries, so only negative or
+	// unparseable values fall back to the default.
+	retryLimit := 10
+	if retryLimitEnv := os.Getenv(EnvRetryLimit); retryLimitEnv != "" {
+		if val, err := strconv.Atoi(retryLimitEnv); err == nil && val >= 0 {
+			retryLimit = val
 		}
 	}
 
@@ -133,14 +249,68 @@ func NewClientOptionsFromEnv() *ClientOptions {
 		}
 	}
 
+	var rateJitter time.Duration
+	if rateJitterEnv := os.Getenv(EnvRateJitter); rateJitterEnv != "" {
+		if val, err := strconv.Atoi(rateJitterEnv); err == nil && val >= 0 {
+			rateJitter = time.Duration(val) * time.Millisecond
+		}
+	}
+
+	maxRetryAfter := DefaultMaxRetryAfter
+	if maxRetryAfterEnv := os.Getenv(EnvMaxRetryAfter); maxRetryAfterEnv != "" {
+		if val, err := strconv.Atoi(maxRetryAfterEnv); err == nil && val >= 0 {
+			maxRetryAfter = time.Duration(val) * time.Second
+		}
+	}
+
+	var maxIdleConns, maxIdleConnsPerHost int
+	if maxIdleConnsEnv := os.Getenv(EnvMaxIdleConns); maxIdleConnsEnv != "" {
+		if val, err := strconv.Atoi(maxIdleConnsEnv); err == nil && val > 0 {
+			maxIdleConns = val
+		}
+	}
+
+	if maxIdleConnsPerHostEnv := os.Getenv(EnvMaxIdleConnsPerHost); maxIdleConnsPerHostEnv != "" {
+		if val, err := strconv.Atoi(maxIdleConnsPerHostEnv); err == nil && val > 0 {
+			maxIdleConnsPerHost = val
+		}
+	}
+
+	var idleConnTimeout time.Duration
+	if idleConnTimeoutEnv := os.Getenv(EnvIdleConnTimeout); idleConnTimeoutEnv != "" {
+		if val, err := strconv.Atoi(idleConnTimeoutEnv); err == nil && val > 0 {
+			idleConnTimeout = time.Duration(val) * time.Second
+		}
+	}
+
+	var requestTimeout, maxRetryDuration time.Duration
+	if requestTimeoutEnv := os.Getenv(EnvRequestTimeout); requestTimeoutEnv != "" {
+		if val, err := strconv.Atoi(requestTimeoutEnv); err == nil && val > 0 {
+			requestTimeout = time.Duration(val) * time.Second
+		}
+	}
+
+	if maxRetryDurationEnv := os.Getenv(EnvMaxRetryDuration); maxRetryDurationEnv != "" {
+		if val, err := strconv.Atoi(maxRetryDurationEnv); err == nil && val > 0 {
+			maxRetryDuration = time.Duration(val) * time.Second
+		}
+	}
+
 	return &ClientOptions{
-		CredentialFile:  os.Getenv(EnvCredentialFile),
-		AccountId:       os.Getenv(EnvAccountId),
-		ContainerId:     os.Getenv(EnvContainerId),
-		RetryLimit:      retryLimit,
-		RateLimit:       rateLimit,
-		RateBurst:       rateBurst,
-		ThrottleEnabled: throttleEnabled,
+		CredentialFile:      os.Getenv(EnvCredentialFile),
+		AccountId:           os.Getenv(EnvAccountId),
+		ContainerId:         os.Getenv(EnvContainerId),
+		RetryLimit:          retryLimit,
+		RateLimit:           rateLimit,
+		RateBurst:           rateBurst,
+		ThrottleEnabled:     throttleEnabled,
+		RateJitter:          rateJitter,
+		MaxRetryAfter:       maxRetryAfter,
+		MaxIdleConns:        maxIdleConns,
+		MaxIdleConnsPerHost: maxIdleConnsPerHost,
+		IdleConnTimeout:     idleConnTimeout,
+		RequestTimeout:      requestTimeout,
+		MaxRetryDuration:    maxRetryDuration,
 	}
 }
 
@@ -149,26 +319,236 @@ type Client struct {
 
 	Options     *ClientOptions
 	rateLimiter *RateLimiter
+	container   *containerCache
+	logger      RequestLogger
+
+	// noTimeoutRetry turns off the retry of attempts that exceeded RequestTimeout.
+	noTimeoutRetry bool
+}
+
+// containerCache holds the container, which is read at most once per client for the
+// details that do not change, like its features and public id.
+type containerCache struct {
+	mutex     sync.Mutex
+	container *tagmanager.Container
+}
+
+// Credential sources, in the order of precedence applied when several are configured.
+const (
+	CredentialSourceJSON          = "credentials_json"
+	CredentialSourceFile          = "credential_file"
+	CredentialSourceImpersonation = "impersonation"
+	CredentialSourceDefault       = "application_default"
+)
+
+// CredentialSource returns the source the client takes its credentials from. Inline
+// JSON credentials win over a credential file, which wins over impersonating a service
+// account with the application default credentials, and the application default
+// credentials are used when nothing is configured.
+func (o *ClientOptions) CredentialSource() string {
+	switch {
+	case o.CredentialsJSON != "":
+		return CredentialSourceJSON
+	case o.CredentialFile != "":
+		return CredentialSourceFile
+	case o.ImpersonateServiceAccount != "":
+		return CredentialSourceImpersonation
+	default:
+		return CredentialSourceDefault
+	}
+}
+
+// defaultScopes are the scopes requested when ClientOptions.Scopes is empty.
+var defaultScopes = []string{
+	tagmanager.TagmanagerDeleteContainersScope,
+	tagmanager.TagmanagerEditContainersScope,
+	tagmanager.TagmanagerEditContainerversionsScope,
+	tagmanager.TagmanagerManageAccountsScope,
+	tagmanager.TagmanagerManageUsersScope,
+	tagmanager.TagmanagerPublishScope,
+	tagmanager.TagmanagerReadonlyScope,
+}
+
+// credentialOptions returns the client options authenticating with the credentials of
+// CredentialSource.
+func credentialOptions(ctx context.Context, opts *ClientOptions) ([]option.ClientOption, error) {
+	var scopes = opts.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultScopes
+	}
+
+	switch opts.CredentialSource() {
+	case CredentialSourceJSON:
+		return []option.ClientOption{option.WithCredentialsJSON([]byte(opts.CredentialsJSON)), option.WithScopes(scopes...)}, nil
+	case CredentialSourceFile:
+		return []option.ClientOption{option.WithCredentialsFile(opts.CredentialFile), option.WithScopes(scopes...)}, nil
+	case CredentialSourceImpersonation:
+		tokenSource, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+			TargetPrincipal: opts.ImpersonateServiceAccount,
+			Scopes:          scopes,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("impersonate %s: %w", opts.ImpersonateServiceAccount, err)
+		}
+		return []option.ClientOption{option.WithTokenSource(tokenSource)}, nil
+	default:
+		return []option.ClientOption{option.WithScopes(scopes...)}, nil
+	}
+}
+
+// newBaseTransport returns the transport the authenticated transport of the client
+// sends its requests with, a copy of http.DefaultTransport with the connection pool
+// configured by opts.
+func newBaseTransport(opts *ClientOptions) *http.Transport {
+	var transport = http.DefaultTransport.(*http.Transport).Clone()
+
+	transport.MaxIdleConns = DefaultMaxIdleConns
+	if opts.MaxIdleConns > 0 {
+		transport.MaxIdleConns = opts.MaxIdleConns
+	}
+
+	transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	if opts.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	}
+
+	transport.IdleConnTimeout = DefaultIdleConnTimeout
+	if opts.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = opts.IdleConnTimeout
+	}
+
+	return transport
 }
 
 func NewClient(opts *ClientOptions) (*Client, error) {
 	var ctx = context.Background()
 
-	srv, err := tagmanager.NewService(ctx, option.WithCredentialsFile(opts.CredentialFile))
+	clientOptions, err := credentialOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := htransport.NewTransport(ctx, newBaseTransport(opts), clientOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	srv, err := tagmanager.NewService(ctx, option.WithHTTPClient(&http.Client{Transport: transport, Timeout: opts.RequestTimeout}))
 	if err != nil {
 		return nil, err
 	}
 
 	var rateLimiter *RateLimiter
 	if opts.ThrottleEnabled {
-		rateLimiter = NewRateLimiter(opts.RateLimit, opts.RateBurst)
+		rateLimiter = NewRateLimiter(opts.RateLimit, opts.RateBurst, WithRefillJitter(opts.RateJitter))
 	}
 
-	return &Client{
+	client := &Client{
 		Service:     srv,
 		Options:     opts,
 		rateLimiter: rateLimiter,
-	}, nil
+		container:   &containerCache{},
+	}
+
+	// Accept the public GTM-XXXXXX form of the container ID.
+	if strings.HasPrefix(opts.ContainerId, "GTM-") {
+		container, err := client.resolveContainerPublicId(opts.ContainerId)
+		if err != nil {
+			return nil, err
+		}
+		opts.ContainerPublicId = container.PublicId
+		opts.ContainerId = container.ContainerId
+		client.container.container = container
+	}
+
+	if opts.ValidateAccess {
+		if err := client.ValidateAccess(); err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+// WithRetryLimit returns a client sharing the service and rate limiter of c that
+// retries rate-limited requests up to limit times.
+func (c *Client) WithRetryLimit(limit int) *Client {
+	options := *c.Options
+	options.RetryLimit = limit
+
+	return &Client{
+		Service:     c.Service,
+		Options:     &options,
+		rateLimiter: c.rateLimiter,
+		container:   c.container,
+		logger:      c.logger,
+	}
+}
+
+// RequestLog describes an attempt of an API request. The attempts of a request share
+// its RequestId; Attempt counts the retries of a rate-limited or timed-out request
+// from 0. Retries are only reported this way, the client writes nothing to stdout.
+type RequestLog struct {
+	RequestId string
+	Attempt   int
+	Duration  time.Duration
+	Err       error
+}
+
+// RequestLogger receives a RequestLog for every attempt of an API request.
+type RequestLogger func(entry RequestLog)
+
+// WithRequestLogger returns a client that passes every attempt of its API requests to
+// logger, e.g. to correlate the requests of a failed apply.
+func (c *Client) WithRequestLogger(logger RequestLogger) *Client {
+	client := *c
+	client.logger = logger
+
+	return &client
+}
+
+// withoutTimeoutRetry returns a client that does not retry attempts that exceeded
+// RequestTimeout, for requests that are not idempotent. A create that timed out on the
+// client may still have succeeded in GTM, and sending it again would make a duplicate
+// entity that no Terraform state tracks.
+func (c *Client) withoutTimeoutRetry() *Client {
+	client := *c
+	client.noTimeoutRetry = true
+
+	return &client
+}
+
+// logRequest passes an attempt of a request started at start to the logger, if any.
+func (c *Client) logRequest(requestId string, attempt int, start time.Time, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	c.logger(RequestLog{RequestId: requestId, Attempt: attempt, Duration: time.Since(start), Err: err})
+}
+
+// ValidateAccess performs a lightweight read of the configured container to check
+// that the credentials are valid and grant access to it.
+func (c *Client) ValidateAccess() error {
+	_, err := c.getContainerWithRetry(c.Accounts.Containers.Get(c.containerPath()).Do)
+
+	if errTyped, ok := err.(*googleapi.Error); ok {
+		if isInsufficientScopes(errTyped) {
+			return insufficientScopesError(errTyped)
+		}
+
+		switch errTyped.Code {
+		case 401:
+			return fmt.Errorf("the credentials were rejected by GTM: %s", errTyped.Message)
+		case 403:
+			return fmt.Errorf("the credentials have no access to container %s of account %s, add the service account as a user of the container: %s",
+				c.Options.ContainerId, c.Options.AccountId, errTyped.Message)
+		case 404:
+			return fmt.Errorf("container %s of account %s does not exist: %s", c.Options.ContainerId, c.Options.AccountId, errTyped.Message)
+		}
+	}
+
+	return err
 }
 
 // NewClientFromEnv creates a new client using environment variables
@@ -176,6 +556,10 @@ func NewClientFromEnv() (*Client, error) {
 	return NewClient(NewClientOptionsFromEnv())
 }
 
+func (c *Client) accountPath() string {
+	return "accounts/" + c.Options.AccountId
+}
+
 func (c *Client) containerPath() string {
 	opts := c.Options
 	return "accounts/" + opts.AccountId + "/containers/" + opts.ContainerId
@@ -183,8 +567,129 @@ func (c *Client) containerPath() string {
 
 var ErrNotExist = errors.New("not exist")
 
+// ErrInsufficientScopes is returned when the access token of the credentials was not
+// granted a scope the request needs, as opposed to the account lacking permissions.
+var ErrInsufficientScopes = errors.New("insufficient authentication scopes")
+
+// isInsufficientScopes reports whether err is the 403 GTM returns for an access
+// token missing a scope, which is told apart from other 403s by its reason.
+func isInsufficientScopes(err *googleapi.Error) bool {
+	if err.Code != 403 {
+		return false
+	}
+
+	for _, item := range err.Errors {
+		if item.Reason == "insufficientPermissions" && strings.Contains(strings.ToLower(item.Message), "insufficient authentication scopes") {
+			return true
+		}
+	}
+
+	return strings.Contains(err.Error(), "ACCESS_TOKEN_SCOPE_INSUFFICIENT") ||
+		strings.Contains(strings.ToLower(err.Message), "insufficient authentication scopes")
+}
+
+func insufficientScopesError(err *googleapi.Error) error {
+	return fmt.Errorf("%w: add https://www.googleapis.com/auth/tagmanager.edit.containers to the scopes attribute of the provider, "+
+		"along with tagmanager.publish or the tagmanager.manage.* scopes when publishing or managing users: %s", ErrInsufficientScopes, err.Message)
+}
+
+// ErrDeleteNotConfirmed is returned when GTM still serves an entity after deleting it
+// and ConfirmDeletes is set.
+var ErrDeleteNotConfirmed = errors.New("deletion not confirmed")
+
+// Polling of deleted entities when ConfirmDeletes is set.
+var (
+	deleteConfirmAttempts = 10
+	deleteConfirmInterval = 500 * time.Millisecond
+)
+
+// confirmDeleted polls get, which reads a deleted entity, until it returns
+// ErrNotExist. GTM is eventually consistent and may serve an entity for a moment after
+// deleting it.
+func (c *Client) confirmDeleted(get func() error) error {
+	if !c.Options.ConfirmDeletes {
+		return nil
+	}
+
+	for attempt := 0; attempt < deleteConfirmAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(deleteConfirmInterval)
+		}
+
+		if err := get(); err == ErrNotExist {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w: still found after %d reads", ErrDeleteNotConfirmed, deleteConfirmAttempts)
+}
+
+// ErrWorkspaceLimitReached is returned when a workspace cannot be created because the
+// container already has the maximum number of workspaces.
+var ErrWorkspaceLimitReached = errors.New("workspace limit reached")
+
 func (c *Client) CreateWorkspace(ws *tagmanager.Workspace) (*tagmanager.Workspace, error) {
-	return c.getWorkspaceWithRetry(c.Accounts.Containers.Workspaces.Create(c.containerPath(), ws).Do)
+	workspace, err := c.withoutTimeoutRetry().getWorkspaceWithRetry(c.Accounts.Containers.Workspaces.Create(c.containerPath(), ws).Do)
+	if errTyped, ok := err.(*googleapi.Error); ok && isWorkspaceLimitError(errTyped) {
+		return nil, fmt.Errorf("%w: %s", ErrWorkspaceLimitReached, errTyped.Message)
+	} else {
+		return workspace, err
+	}
+}
+
+// isWorkspaceLimitError reports whether a failed workspace creation was rejected for the
+// workspace limit of the container. GTM reports it as a conflict, like other conflicts
+// such as a duplicate workspace name, so it is told apart by its message.
+func isWorkspaceLimitError(err *googleapi.Error) bool {
+	if err.Code != 409 {
+		return false
+	}
+
+	message := strings.ToLower(err.Message)
+	return strings.Contains(message, "workspace") &&
+		(strings.Contains(message, "limit") || strings.Contains(message, "maximum"))
+}
+
+// ErrLatestVersionNotRestored is returned along with the created workspace when
+// CreateWorkspaceFromVersion could not set the previous latest version back.
+var ErrLatestVersionNotRestored = errors.New("latest container version not restored")
+
+// CreateWorkspaceFromVersion creates a workspace based on the given container version.
+// GTM bases new workspaces on the latest container version, so that version is set as
+// the latest one for the create, and the previous latest version is set back after it,
+// also when the create fails. The live version of the container is left untouched.
+// A failure to set the previous latest version back is reported with
+// ErrLatestVersionNotRestored, along with the workspace when it was created.
+func (c *Client) CreateWorkspaceFromVersion(versionId string, ws *tagmanager.Workspace) (*tagmanager.Workspace, error) {
+	latest, err := c.LatestVersionHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	if latest.ContainerVersionId == versionId {
+		return c.CreateWorkspace(ws)
+	}
+
+	if err := c.setLatestVersion(versionId); err != nil {
+		return nil, err
+	}
+
+	workspace, err := c.CreateWorkspace(ws)
+
+	if restoreErr := c.setLatestVersion(latest.ContainerVersionId); restoreErr != nil {
+		restoreErr = fmt.Errorf("%w: version %s is still the latest version instead of %s: %s",
+			ErrLatestVersionNotRestored, versionId, latest.ContainerVersionId, restoreErr)
+		return workspace, errors.Join(err, restoreErr)
+	}
+
+	return workspace, err
+}
+
+func (c *Client) setLatestVersion(versionId string) error {
+	_, err := c.getContainerVersionWithRetry(c.Accounts.Containers.Versions.SetLatest(c.containerPath() + "/versions/" + versionId).Do)
+	return err
 }
 
 func (c *Client) ListWorkspaces() ([]*tagmanager.Workspace, error) {
@@ -205,6 +710,16 @@ func (c *Client) Workspace(id string) (*tagmanager.Workspace, error) {
 	}
 }
 
+// WorkspaceStatus reads the changes and merge conflicts pending in a workspace.
+func (c *Client) WorkspaceStatus(id string) (*tagmanager.GetWorkspaceStatusResponse, error) {
+	status, err := c.getWorkspaceStatusWithRetry(c.Accounts.Containers.Workspaces.GetStatus(c.containerPath() + "/workspaces/" + id).Do)
+	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 404 {
+		return nil, ErrNotExist
+	} else {
+		return status, err
+	}
+}
+
 func (c *Client) UpdateWorkspaces(id string, ws *tagmanager.Workspace) (*tagmanager.Workspace, error) {
 	return c.getWorkspaceWithRetry(c.Accounts.Containers.Workspaces.Update(c.containerPath()+"/workspaces/"+id, ws).Do)
 }
@@ -213,157 +728,1014 @@ func (c *Client) DeleteWorkspace(id string) error {
 	return c.executeWithRetry(c.Accounts.Containers.Workspaces.Delete(c.containerPath() + "/workspaces/" + id).Do)
 }
 
-func (c *Client) workspacePath(id string) string {
-	return c.containerPath() + "/workspaces/" + id
+// SyncWorkspace merges the changes of the latest container version into the workspace.
+// Changes that conflict with the ones made in the workspace are left unresolved and
+// returned as merge conflicts.
+func (c *Client) SyncWorkspace(id string) (*tagmanager.SyncWorkspaceResponse, error) {
+	return c.getSyncWorkspaceWithRetry(c.Accounts.Containers.Workspaces.Sync(c.containerPath() + "/workspaces/" + id).Do)
 }
 
-// throttle applies rate limiting if enabled
-func (c *Client) throttle() {
-	if c.rateLimiter != nil {
-		c.rateLimiter.Wait()
-	}
+// ResolveConflict resolves a merge conflict reported by SyncWorkspace by updating the
+// conflicting entity of the workspace to entity, typically the entity in the workspace
+// or in the base version of the conflict.
+func (c *Client) ResolveConflict(workspaceId string, entity *tagmanager.Entity) error {
+	return c.executeWithRetry(c.Accounts.Containers.Workspaces.ResolveConflict(c.workspacePath(workspaceId), entity).Do)
 }
 
-func (c *Client) CreateTag(workspaceId string, tag *tagmanager.Tag) (*tagmanager.Tag, error) {
+func (c *Client) ListContainers() ([]*tagmanager.Container, error) {
+	resp, err := c.getContainerListWithRetry(c.Accounts.Containers.List(c.accountPath()).Do)
+	if err != nil {
+		return nil, err
+	} else {
+		return resp.Container, nil
+	}
+}
 
-	return c.getTagWithRetry(c.Accounts.Containers.Workspaces.Tags.Create(c.workspacePath(workspaceId), tag).Do)
+// resolutions deduplicates concurrent resolutions of the same container public ID or
+// workspace name, e.g. by provider configurations sharing a container, into a single
+// API call. For workspaces this also keeps two of them from creating the same missing
+// workspace twice.
+var resolutions singleflight.Group
+
+// resolutionKey identifies a resolution described by parts together with the
+// credentials, account and container it is made with, so that only identical
+// resolutions share a result.
+func (c *Client) resolutionKey(parts ...string) string {
+	var credentials = []string{c.Options.CredentialsJSON, c.Options.CredentialFile, c.Options.ImpersonateServiceAccount}
+	return strings.Join(append(append(credentials, c.Options.AccountId, c.Options.ContainerId), parts...), "\x00")
 }
 
-func (c *Client) ListTags(workspaceId string) ([]*tagmanager.Tag, error) {
-	resp, err := c.getTagListWithRetry(c.Accounts.Containers.Workspaces.Tags.List(c.workspacePath(workspaceId)).Do)
+// resolveContainerPublicId looks up the container with a public ID such as GTM-XXXXXX.
+func (c *Client) resolveContainerPublicId(publicId string) (*tagmanager.Container, error) {
+	container, err, _ := resolutions.Do(c.resolutionKey("container", publicId), func() (any, error) {
+		return c.findContainerByPublicId(publicId)
+	})
 	if err != nil {
 		return nil, err
-	} else {
-		return resp.Tag, nil
 	}
+
+	return container.(*tagmanager.Container), nil
 }
 
-func (c *Client) Tag(workspaceId string, tagId string) (*tagmanager.Tag, error) {
-	tag, err := c.getTagWithRetry(c.Accounts.Containers.Workspaces.Tags.Get(c.workspacePath(workspaceId) + "/tags/" + tagId).Do)
+func (c *Client) findContainerByPublicId(publicId string) (*tagmanager.Container, error) {
+	containers, err := c.ListContainers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, container := range containers {
+		if container.PublicId == publicId {
+			return container, nil
+		}
+	}
+
+	return nil, fmt.Errorf("container %s not found in account %s", publicId, c.Options.AccountId)
+}
 
+func (c *Client) Container() (*tagmanager.Container, error) {
+	container, err := c.getContainerWithRetry(c.Accounts.Containers.Get(c.containerPath()).Do)
 	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 404 {
 		return nil, ErrNotExist
 	} else {
-		return tag, err
+		return container, err
 	}
 }
 
-func (c *Client) UpdateTag(workspaceId string, tagId string, tag *tagmanager.Tag) (*tagmanager.Tag, error) {
-	return c.getTagWithRetry(c.Accounts.Containers.Workspaces.Tags.Update(c.workspacePath(workspaceId)+"/tags/"+tagId, tag).Do)
+// cachedContainer returns the container, which is read once and then served from the
+// client.
+func (c *Client) cachedContainer() (*tagmanager.Container, error) {
+	if c.container == nil {
+		c.container = &containerCache{}
+	}
+
+	c.container.mutex.Lock()
+	defer c.container.mutex.Unlock()
+
+	if c.container.container != nil {
+		return c.container.container, nil
+	}
+
+	container, err := c.Container()
+	if err != nil {
+		return nil, err
+	}
+
+	c.container.container = container
+	return container, nil
 }
 
-func (c *Client) DeleteTag(workspaceId string, tagId string) error {
-	return c.executeWithRetry(c.Accounts.Containers.Workspaces.Tags.Delete(c.workspacePath(workspaceId) + "/tags/" + tagId).Do)
+// ContainerFeatures returns the features of the container, e.g. whether it supports
+// clients and zones, which only server containers do. The features are read once and
+// then served from the client.
+func (c *Client) ContainerFeatures() (*tagmanager.ContainerFeatures, error) {
+	container, err := c.cachedContainer()
+	if err != nil {
+		return nil, err
+	}
+
+	if container.Features == nil {
+		return &tagmanager.ContainerFeatures{}, nil
+	}
+
+	return container.Features, nil
 }
 
-func (c *Client) CreateVariable(workspaceId string, variable *tagmanager.Variable) (*tagmanager.Variable, error) {
-	return c.getVariableWithRetry(c.Accounts.Containers.Workspaces.Variables.Create(c.workspacePath(workspaceId), variable).Do)
+// ContainerPublicId returns the public id of the container, e.g. GTM-XXXXXX, which is
+// read once and then served from the client.
+func (c *Client) ContainerPublicId() (string, error) {
+	if c.Options.ContainerPublicId != "" {
+		return c.Options.ContainerPublicId, nil
+	}
+
+	container, err := c.cachedContainer()
+	if err != nil {
+		return "", err
+	}
+
+	return container.PublicId, nil
 }
 
-func (c *Client) ListVariables(workspaceId string) ([]*tagmanager.Variable, error) {
-	resp, err := c.getVariableListWithRetry(c.Accounts.Containers.Workspaces.Variables.List(c.workspacePath(workspaceId)).Do)
+func (c *Client) UpdateContainer(container *tagmanager.Container) (*tagmanager.Container, error) {
+	return c.getContainerWithRetry(c.Accounts.Containers.Update(c.containerPath(), container).Do)
+}
+
+// LinkDestination links the destination, e.g. a GA4 measurement ID, to the container.
+// A destination linked to another container is moved to this one.
+func (c *Client) LinkDestination(destinationId string) (*tagmanager.Destination, error) {
+	return c.getDestinationWithRetry(c.Accounts.Containers.Destinations.Link(c.containerPath()).DestinationId(destinationId).Do)
+}
+
+func (c *Client) ListDestinations() ([]*tagmanager.Destination, error) {
+	resp, err := c.getDestinationListWithRetry(c.Accounts.Containers.Destinations.List(c.containerPath()).Do)
 	if err != nil {
 		return nil, err
 	} else {
-		return resp.Variable, nil
+		return resp.Destination, nil
 	}
 }
 
-func (c *Client) Variable(workspaceId string, variableId string) (*tagmanager.Variable, error) {
-	variable, err := c.getVariableWithRetry(c.Accounts.Containers.Workspaces.Variables.Get(c.workspacePath(workspaceId) + "/variables/" + variableId).Do)
-
+func (c *Client) Destination(destinationLinkId string) (*tagmanager.Destination, error) {
+	destination, err := c.getDestinationWithRetry(c.Accounts.Containers.Destinations.Get(c.containerPath() + "/destinations/" + destinationLinkId).Do)
 	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 404 {
 		return nil, ErrNotExist
 	} else {
-		return variable, err
+		return destination, err
 	}
 }
 
-func (c *Client) UpdateVariable(workspaceId string, variableId string, variable *tagmanager.Variable) (*tagmanager.Variable, error) {
-	return c.getVariableWithRetry(c.Accounts.Containers.Workspaces.Variables.Update(c.workspacePath(workspaceId)+"/variables/"+variableId, variable).Do)
-}
-
-func (c *Client) DeleteVariable(workspaceId string, variableId string) error {
-	return c.executeWithRetry(c.Accounts.Containers.Workspaces.Variables.Delete(c.workspacePath(workspaceId) + "/variables/" + variableId).Do)
-}
-
-func (c *Client) CreateTrigger(workspaceId string, trigger *tagmanager.Trigger) (*tagmanager.Trigger, error) {
-	return c.getTriggerWithRetry(c.Accounts.Containers.Workspaces.Triggers.Create(c.workspacePath(workspaceId), trigger).Do)
+func (c *Client) CreateEnvironment(environment *tagmanager.Environment) (*tagmanager.Environment, error) {
+	return c.withoutTimeoutRetry().getEnvironmentWithRetry(c.Accounts.Containers.Environments.Create(c.containerPath(), environment).Do)
 }
 
-func (c *Client) ListTriggers(workspaceId string) ([]*tagmanager.Trigger, error) {
-	resp, err := c.getTriggerListWithRetry(c.Accounts.Containers.Workspaces.Triggers.List(c.workspacePath(workspaceId)).Do)
+func (c *Client) ListEnvironments() ([]*tagmanager.Environment, error) {
+	resp, err := c.getEnvironmentListWithRetry(c.Accounts.Containers.Environments.List(c.containerPath()).Do)
 	if err != nil {
 		return nil, err
 	} else {
-		return resp.Trigger, nil
+		return resp.Environment, nil
 	}
 }
 
-func (c *Client) Trigger(workspaceId string, triggerId string) (*tagmanager.Trigger, error) {
-	trigger, err := c.getTriggerWithRetry(c.Accounts.Containers.Workspaces.Triggers.Get(c.workspacePath(workspaceId) + "/triggers/" + triggerId).Do)
-
+func (c *Client) Environment(id string) (*tagmanager.Environment, error) {
+	environment, err := c.getEnvironmentWithRetry(c.Accounts.Containers.Environments.Get(c.containerPath() + "/environments/" + id).Do)
 	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 404 {
 		return nil, ErrNotExist
 	} else {
-		return trigger, err
+		return environment, err
 	}
 }
 
-func (c *Client) UpdateTrigger(workspaceId string, triggerId string, trigger *tagmanager.Trigger) (*tagmanager.Trigger, error) {
-	return c.getTriggerWithRetry(c.Accounts.Containers.Workspaces.Triggers.Update(c.workspacePath(workspaceId)+"/triggers/"+triggerId, trigger).Do)
+func (c *Client) UpdateEnvironment(id string, environment *tagmanager.Environment) (*tagmanager.Environment, error) {
+	return c.getEnvironmentWithRetry(c.Accounts.Containers.Environments.Update(c.containerPath()+"/environments/"+id, environment).Do)
 }
 
-func (c *Client) DeleteTrigger(workspaceId string, triggerId string) error {
-	return c.executeWithRetry(c.Accounts.Containers.Workspaces.Triggers.Delete(c.workspacePath(workspaceId) + "/triggers/" + triggerId).Do)
+func (c *Client) DeleteEnvironment(id string) error {
+	return c.executeWithRetry(c.Accounts.Containers.Environments.Delete(c.containerPath() + "/environments/" + id).Do)
 }
 
-func (c *Client) executeWithRetry(query func(opts ...googleapi.CallOption) error) error {
-	retryCount := 0
+// LatestVersionHeader returns the header of the most recently created container version.
+func (c *Client) LatestVersionHeader() (*tagmanager.ContainerVersionHeader, error) {
+	header, err := c.getVersionHeaderWithRetry(c.Accounts.Containers.VersionHeaders.Latest(c.containerPath()).Do)
+	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 404 {
+		return nil, ErrNotExist
+	} else {
+		return header, err
+	}
+}
 
-	for {
-		// Apply throttling before making the request
-		c.throttle()
+// CreateVersion creates a container version from the changes of the workspace. A
+// workspace that does not compile is reported by the CompilerError of the response
+// rather than by an error.
+func (c *Client) CreateVersion(workspaceId string, options *tagmanager.CreateContainerVersionRequestVersionOptions) (*tagmanager.CreateContainerVersionResponse, error) {
+	return c.withoutTimeoutRetry().getCreateVersionWithRetry(c.Accounts.Containers.Workspaces.CreateVersion(c.workspacePath(workspaceId), options).Do)
+}
 
+// WorkspaceCompilerStatus is the outcome of compiling a workspace without creating a
+// version.
+type WorkspaceCompilerStatus struct {
+	CompilerError bool
+	MergeConflict bool
+	SyncError     bool
+}
+
+// Compiles reports whether a version could be created from the workspace.
+func (s *WorkspaceCompilerStatus) Compiles() bool {
+	return !s.CompilerError && !s.MergeConflict && !s.SyncError
+}
+
+// GetWorkspaceCompilerStatus compiles the workspace with a quick preview, which
+// creates no version, so that a workspace that does not compile can be caught before
+// CreateVersion.
+func (c *Client) GetWorkspaceCompilerStatus(workspaceId string) (*WorkspaceCompilerStatus, error) {
+	resp, err := c.getQuickPreviewWithRetry(c.Accounts.Containers.Workspaces.QuickPreview(c.workspacePath(workspaceId)).Do)
+	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 404 {
+		return nil, ErrNotExist
+	} else if err != nil {
+		return nil, err
+	}
+
+	var status = &WorkspaceCompilerStatus{CompilerError: resp.CompilerError}
+	if resp.SyncStatus != nil {
+		status.MergeConflict = resp.SyncStatus.MergeConflict
+		status.SyncError = resp.SyncStatus.SyncError
+	}
+
+	return status, nil
+}
+
+func (c *Client) Version(id string) (*tagmanager.ContainerVersion, error) {
+	version, err := c.getContainerVersionWithRetry(c.Accounts.Containers.Versions.Get(c.containerPath() + "/versions/" + id).Do)
+	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 404 {
+		return nil, ErrNotExist
+	} else {
+		return version, err
+	}
+}
+
+func (c *Client) UpdateVersion(id string, version *tagmanager.ContainerVersion) (*tagmanager.ContainerVersion, error) {
+	return c.getContainerVersionWithRetry(c.Accounts.Containers.Versions.Update(c.containerPath()+"/versions/"+id, version).Do)
+}
+
+func (c *Client) DeleteVersion(id string) error {
+	return c.executeWithRetry(c.Accounts.Containers.Versions.Delete(c.containerPath() + "/versions/" + id).Do)
+}
+
+func (c *Client) workspacePath(id string) string {
+	return c.containerPath() + "/workspaces/" + id
+}
+
+// backoff returns how long to wait before retrying a rate-limited request. The wait
+// requested by a Retry-After header is honored up to MaxRetryAfter, fallback is used
+// when the response has none.
+func (c *Client) backoff(err *googleapi.Error, fallback time.Duration) time.Duration {
+	retryAfter := err.Header.Get("Retry-After")
+	if retryAfter == "" {
+		return fallback
+	}
+
+	var wait time.Duration
+	if seconds, parseErr := strconv.Atoi(retryAfter); parseErr == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, parseErr := http.ParseTime(retryAfter); parseErr == nil {
+		wait = time.Until(date)
+	} else {
+		return fallback
+	}
+
+	ceiling := c.Options.MaxRetryAfter
+	if ceiling <= 0 {
+		ceiling = DefaultMaxRetryAfter
+	}
+
+	if wait > ceiling {
+		return ceiling
+	} else if wait < 0 {
+		return 0
+	}
+
+	return wait
+}
+
+// retryDelay reports whether a request whose attempt failed with err is retried, and
+// how long to wait before retrying it. Rate-limited attempts are retried after their
+// backoff, which is step times the number of the retry without a Retry-After header.
+// Attempts that exceeded RequestTimeout are retried after a short wait, except on a
+// client without timeout retries. No retry is
+// made beyond RetryLimit, or that would start after MaxRetryDuration from firstAttempt.
+func (c *Client) retryDelay(err error, retryCount int, firstAttempt time.Time, step time.Duration) (time.Duration, bool) {
+	if err == nil || retryCount >= c.Options.RetryLimit {
+		return 0, false
+	}
+
+	var wait time.Duration
+	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 429 {
+		wait = c.backoff(errTyped, step*time.Duration(retryCount+1))
+	} else if c.timedOut(err) && !c.noTimeoutRetry {
+		wait = timeoutRetryStep * time.Duration(retryCount+1)
+	} else {
+		return 0, false
+	}
+
+	if c.Options.MaxRetryDuration > 0 && time.Since(firstAttempt)+wait > c.Options.MaxRetryDuration {
+		return 0, false
+	}
+
+	return wait, true
+}
+
+// timedOut reports whether err is an attempt that exceeded RequestTimeout.
+func (c *Client) timedOut(err error) bool {
+	var timeout interface{ Timeout() bool }
+
+	return c.Options.RequestTimeout > 0 && errors.As(err, &timeout) && timeout.Timeout()
+}
+
+// retryError returns the error of a request whose last attempt failed with err after
+// retryCount retries.
+func (c *Client) retryError(err error, retryCount int) error {
+	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 429 {
+		return fmt.Errorf("rate limit exceeded after %d retries", retryCount)
+	} else if c.timedOut(err) {
+		return fmt.Errorf("request timed out after %d retries: %w", retryCount, err)
+	}
+
+	return err
+}
+
+// throttle applies rate limiting if enabled
+func (c *Client) throttle() {
+	if c.rateLimiter != nil {
+		c.rateLimiter.Wait()
+	}
+}
+
+func (c *Client) CreateTag(workspaceId string, tag *tagmanager.Tag) (*tagmanager.Tag, error) {
+
+	return c.withoutTimeoutRetry().getTagWithRetry(c.Accounts.Containers.Workspaces.Tags.Create(c.workspacePath(workspaceId), tag).Do)
+}
+
+func (c *Client) ListTags(workspaceId string) ([]*tagmanager.Tag, error) {
+	resp, err := c.getTagListWithRetry(c.Accounts.Containers.Workspaces.Tags.List(c.workspacePath(workspaceId)).Do)
+	if err != nil {
+		return nil, err
+	} else {
+		return resp.Tag, nil
+	}
+}
+
+// Tag reads a tag. When fields are given, only those fields of the tag are
+// requested, e.g. "name,type".
+func (c *Client) Tag(workspaceId string, tagId string, fields ...googleapi.Field) (*tagmanager.Tag, error) {
+	call := c.Accounts.Containers.Workspaces.Tags.Get(c.workspacePath(workspaceId) + "/tags/" + tagId)
+	if len(fields) > 0 {
+		call.Fields(fields...)
+	}
+
+	tag, err := c.getTagWithRetry(call.Do)
+
+	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 404 {
+		return nil, ErrNotExist
+	} else {
+		return tag, err
+	}
+}
+
+func (c *Client) UpdateTag(workspaceId string, tagId string, tag *tagmanager.Tag) (*tagmanager.Tag, error) {
+	path := c.workspacePath(workspaceId) + "/tags/" + tagId
+
+	resp, err := c.getTagWithRetry(c.Accounts.Containers.Workspaces.Tags.Update(path, tag).Do)
+	if c.shouldResolveConflict(err) {
+		latest, err := c.Tag(workspaceId, tagId)
+		if err != nil {
+			return nil, err
+		}
+		return c.getTagWithRetry(c.Accounts.Containers.Workspaces.Tags.Update(path, tag).Fingerprint(latest.Fingerprint).Do)
+	}
+
+	return resp, err
+}
+
+func (c *Client) DeleteTag(workspaceId string, tagId string) error {
+	return c.executeWithRetry(c.Accounts.Containers.Workspaces.Tags.Delete(c.workspacePath(workspaceId) + "/tags/" + tagId).Do)
+}
+
+func (c *Client) CreateVariable(workspaceId string, variable *tagmanager.Variable) (*tagmanager.Variable, error) {
+	return c.withoutTimeoutRetry().getVariableWithRetry(c.Accounts.Containers.Workspaces.Variables.Create(c.workspacePath(workspaceId), variable).Do)
+}
+
+func (c *Client) ListVariables(workspaceId string) ([]*tagmanager.Variable, error) {
+	resp, err := c.getVariableListWithRetry(c.Accounts.Containers.Workspaces.Variables.List(c.workspacePath(workspaceId)).Do)
+	if err != nil {
+		return nil, err
+	} else {
+		return resp.Variable, nil
+	}
+}
+
+// Variable reads a variable. When fields are given, only those fields of the variable are
+// requested, e.g. "name,type".
+func (c *Client) Variable(workspaceId string, variableId string, fields ...googleapi.Field) (*tagmanager.Variable, error) {
+	call := c.Accounts.Containers.Workspaces.Variables.Get(c.workspacePath(workspaceId) + "/variables/" + variableId)
+	if len(fields) > 0 {
+		call.Fields(fields...)
+	}
+
+	variable, err := c.getVariableWithRetry(call.Do)
+
+	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 404 {
+		return nil, ErrNotExist
+	} else {
+		return variable, err
+	}
+}
+
+func (c *Client) UpdateVariable(workspaceId string, variableId string, variable *tagmanager.Variable) (*tagmanager.Variable, error) {
+	path := c.workspacePath(workspaceId) + "/variables/" + variableId
+
+	resp, err := c.getVariableWithRetry(c.Accounts.Containers.Workspaces.Variables.Update(path, variable).Do)
+	if c.shouldResolveConflict(err) {
+		latest, err := c.Variable(workspaceId, variableId)
+		if err != nil {
+			return nil, err
+		}
+		return c.getVariableWithRetry(c.Accounts.Containers.Workspaces.Variables.Update(path, variable).Fingerprint(latest.Fingerprint).Do)
+	}
+
+	return resp, err
+}
+
+func (c *Client) DeleteVariable(workspaceId string, variableId string) error {
+	return c.executeWithRetry(c.Accounts.Containers.Workspaces.Variables.Delete(c.workspacePath(workspaceId) + "/variables/" + variableId).Do)
+}
+
+func (c *Client) CreateTrigger(workspaceId string, trigger *tagmanager.Trigger) (*tagmanager.Trigger, error) {
+	return c.withoutTimeoutRetry().getTriggerWithRetry(c.Accounts.Containers.Workspaces.Triggers.Create(c.workspacePath(workspaceId), trigger).Do)
+}
+
+func (c *Client) ListTriggers(workspaceId string) ([]*tagmanager.Trigger, error) {
+	resp, err := c.getTriggerListWithRetry(c.Accounts.Containers.Workspaces.Triggers.List(c.workspacePath(workspaceId)).Do)
+	if err != nil {
+		return nil, err
+	} else {
+		return resp.Trigger, nil
+	}
+}
+
+// Trigger reads a trigger. When fields are given, only those fields of the trigger are
+// requested, e.g. "name,type".
+func (c *Client) Trigger(workspaceId string, triggerId string, fields ...googleapi.Field) (*tagmanager.Trigger, error) {
+	call := c.Accounts.Containers.Workspaces.Triggers.Get(c.workspacePath(workspaceId) + "/triggers/" + triggerId)
+	if len(fields) > 0 {
+		call.Fields(fields...)
+	}
+
+	trigger, err := c.getTriggerWithRetry(call.Do)
+
+	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 404 {
+		return nil, ErrNotExist
+	} else {
+		return trigger, err
+	}
+}
+
+func (c *Client) UpdateTrigger(workspaceId string, triggerId string, trigger *tagmanager.Trigger) (*tagmanager.Trigger, error) {
+	path := c.workspacePath(workspaceId) + "/triggers/" + triggerId
+
+	resp, err := c.getTriggerWithRetry(c.Accounts.Containers.Workspaces.Triggers.Update(path, trigger).Do)
+	if c.shouldResolveConflict(err) {
+		latest, err := c.Trigger(workspaceId, triggerId)
+		if err != nil {
+			return nil, err
+		}
+		return c.getTriggerWithRetry(c.Accounts.Containers.Workspaces.Triggers.Update(path, trigger).Fingerprint(latest.Fingerprint).Do)
+	}
+
+	return resp, err
+}
+
+func (c *Client) DeleteTrigger(workspaceId string, triggerId string) error {
+	return c.executeWithRetry(c.Accounts.Containers.Workspaces.Triggers.Delete(c.workspacePath(workspaceId) + "/triggers/" + triggerId).Do)
+}
+
+func (c *Client) CreateTemplate(workspaceId string, template *tagmanager.CustomTemplate) (*tagmanager.CustomTemplate, error) {
+	return c.withoutTimeoutRetry().getTemplateWithRetry(c.Accounts.Containers.Workspaces.Templates.Create(c.workspacePath(workspaceId), template).Do)
+}
+
+func (c *Client) ListTemplates(workspaceId string) ([]*tagmanager.CustomTemplate, error) {
+	resp, err := c.getTemplateListWithRetry(c.Accounts.Containers.Workspaces.Templates.List(c.workspacePath(workspaceId)).Do)
+	if err != nil {
+		return nil, err
+	} else {
+		return resp.Template, nil
+	}
+}
+
+func (c *Client) Template(workspaceId string, templateId string) (*tagmanager.CustomTemplate, error) {
+	template, err := c.getTemplateWithRetry(c.Accounts.Containers.Workspaces.Templates.Get(c.workspacePath(workspaceId) + "/templates/" + templateId).Do)
+
+	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 404 {
+		return nil, ErrNotExist
+	} else {
+		return template, err
+	}
+}
+
+func (c *Client) UpdateTemplate(workspaceId string, templateId string, template *tagmanager.CustomTemplate) (*tagmanager.CustomTemplate, error) {
+	path := c.workspacePath(workspaceId) + "/templates/" + templateId
+
+	resp, err := c.getTemplateWithRetry(c.Accounts.Containers.Workspaces.Templates.Update(path, template).Do)
+	if c.shouldResolveConflict(err) {
+		latest, err := c.Template(workspaceId, templateId)
+		if err != nil {
+			return nil, err
+		}
+		return c.getTemplateWithRetry(c.Accounts.Containers.Workspaces.Templates.Update(path, template).Fingerprint(latest.Fingerprint).Do)
+	}
+
+	return resp, err
+}
+
+func (c *Client) DeleteTemplate(workspaceId string, templateId string) error {
+	return c.executeWithRetry(c.Accounts.Containers.Workspaces.Templates.Delete(c.workspacePath(workspaceId) + "/templates/" + templateId).Do)
+}
+
+func (c *Client) CreateGtagConfig(workspaceId string, gtagConfig *tagmanager.GtagConfig) (*tagmanager.GtagConfig, error) {
+	return c.withoutTimeoutRetry().getGtagConfigWithRetry(c.Accounts.Containers.Workspaces.GtagConfig.Create(c.workspacePath(workspaceId), gtagConfig).Do)
+}
+
+func (c *Client) GtagConfig(workspaceId string, gtagConfigId string) (*tagmanager.GtagConfig, error) {
+	gtagConfig, err := c.getGtagConfigWithRetry(c.Accounts.Containers.Workspaces.GtagConfig.Get(c.workspacePath(workspaceId) + "/gtag_config/" + gtagConfigId).Do)
+
+	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 404 {
+		return nil, ErrNotExist
+	} else {
+		return gtagConfig, err
+	}
+}
+
+func (c *Client) UpdateGtagConfig(workspaceId string, gtagConfigId string, gtagConfig *tagmanager.GtagConfig) (*tagmanager.GtagConfig, error) {
+	path := c.workspacePath(workspaceId) + "/gtag_config/" + gtagConfigId
+
+	resp, err := c.getGtagConfigWithRetry(c.Accounts.Containers.Workspaces.GtagConfig.Update(path, gtagConfig).Do)
+	if c.shouldResolveConflict(err) {
+		latest, err := c.GtagConfig(workspaceId, gtagConfigId)
+		if err != nil {
+			return nil, err
+		}
+		return c.getGtagConfigWithRetry(c.Accounts.Containers.Workspaces.GtagConfig.Update(path, gtagConfig).Fingerprint(latest.Fingerprint).Do)
+	}
+
+	return resp, err
+}
+
+func (c *Client) DeleteGtagConfig(workspaceId string, gtagConfigId string) error {
+	return c.executeWithRetry(c.Accounts.Containers.Workspaces.GtagConfig.Delete(c.workspacePath(workspaceId) + "/gtag_config/" + gtagConfigId).Do)
+}
+
+func (c *Client) CreateServerClient(workspaceId string, client *tagmanager.Client) (*tagmanager.Client, error) {
+	return c.withoutTimeoutRetry().getServerClientWithRetry(c.Accounts.Containers.Workspaces.Clients.Create(c.workspacePath(workspaceId), client).Do)
+}
+
+// ServerClient reads a client of a server container.
+func (c *Client) ServerClient(workspaceId string, clientId string) (*tagmanager.Client, error) {
+	client, err := c.getServerClientWithRetry(c.Accounts.Containers.Workspaces.Clients.Get(c.workspacePath(workspaceId) + "/clients/" + clientId).Do)
+
+	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 404 {
+		return nil, ErrNotExist
+	} else {
+		return client, err
+	}
+}
+
+func (c *Client) UpdateServerClient(workspaceId string, clientId string, client *tagmanager.Client) (*tagmanager.Client, error) {
+	path := c.workspacePath(workspaceId) + "/clients/" + clientId
+
+	resp, err := c.getServerClientWithRetry(c.Accounts.Containers.Workspaces.Clients.Update(path, client).Do)
+	if c.shouldResolveConflict(err) {
+		latest, err := c.ServerClient(workspaceId, clientId)
+		if err != nil {
+			return nil, err
+		}
+		return c.getServerClientWithRetry(c.Accounts.Containers.Workspaces.Clients.Update(path, client).Fingerprint(latest.Fingerprint).Do)
+	}
+
+	return resp, err
+}
+
+func (c *Client) DeleteServerClient(workspaceId string, clientId string) error {
+	return c.executeWithRetry(c.Accounts.Containers.Workspaces.Clients.Delete(c.workspacePath(workspaceId) + "/clients/" + clientId).Do)
+}
+
+func (c *Client) CreateFolder(workspaceId string, folder *tagmanager.Folder) (*tagmanager.Folder, error) {
+	return c.withoutTimeoutRetry().getFolderWithRetry(c.Accounts.Containers.Workspaces.Folders.Create(c.workspacePath(workspaceId), folder).Do)
+}
+
+func (c *Client) ListFolders(workspaceId string) ([]*tagmanager.Folder, error) {
+	resp, err := c.getFolderListWithRetry(c.Accounts.Containers.Workspaces.Folders.List(c.workspacePath(workspaceId)).Do)
+	if err != nil {
+		return nil, err
+	} else {
+		return resp.Folder, nil
+	}
+}
+
+func (c *Client) Folder(workspaceId string, folderId string) (*tagmanager.Folder, error) {
+	folder, err := c.getFolderWithRetry(c.Accounts.Containers.Workspaces.Folders.Get(c.workspacePath(workspaceId) + "/folders/" + folderId).Do)
+
+	if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 404 {
+		return nil, ErrNotExist
+	} else {
+		return folder, err
+	}
+}
+
+func (c *Client) UpdateFolder(workspaceId string, folderId string, folder *tagmanager.Folder) (*tagmanager.Folder, error) {
+	return c.getFolderWithRetry(c.Accounts.Containers.Workspaces.Folders.Update(c.workspacePath(workspaceId)+"/folders/"+folderId, folder).Do)
+}
+
+func (c *Client) DeleteFolder(workspaceId string, folderId string) error {
+	return c.executeWithRetry(c.Accounts.Containers.Workspaces.Folders.Delete(c.workspacePath(workspaceId) + "/folders/" + folderId).Do)
+}
+
+// FolderEntities lists the tags, triggers and variables in the folder.
+func (c *Client) FolderEntities(workspaceId string, folderId string) (*tagmanager.FolderEntities, error) {
+	return c.getFolderEntitiesWithRetry(c.Accounts.Containers.Workspaces.Folders.Entities(c.workspacePath(workspaceId) + "/folders/" + folderId).Do)
+}
+
+// RootFolderId is the folder ID that moves entities out of their current folder.
+const RootFolderId = "0"
+
+// MoveEntitiesToFolder moves the given entities into the folder, or out of their
+// current folder when folderId is RootFolderId.
+func (c *Client) MoveEntitiesToFolder(workspaceId string, folderId string, tagIds []string, triggerIds []string, variableIds []string) error {
+	if len(tagIds) == 0 && len(triggerIds) == 0 && len(variableIds) == 0 {
+		return nil
+	}
+
+	call := c.Accounts.Containers.Workspaces.Folders.MoveEntitiesToFolder(c.workspacePath(workspaceId)+"/folders/"+folderId, &tagmanager.Folder{})
+	if len(tagIds) > 0 {
+		call = call.TagId(tagIds...)
+	}
+	if len(triggerIds) > 0 {
+		call = call.TriggerId(triggerIds...)
+	}
+	if len(variableIds) > 0 {
+		call = call.VariableId(variableIds...)
+	}
+
+	return c.executeWithRetry(call.Do)
+}
+
+// EnableBuiltInVariable enables the built-in variable of the given type, e.g. pageUrl.
+func (c *Client) EnableBuiltInVariable(workspaceId string, variableType string) (*tagmanager.BuiltInVariable, error) {
+	resp, err := c.getBuiltInVariableCreateWithRetry(c.Accounts.Containers.Workspaces.BuiltInVariables.Create(c.workspacePath(workspaceId)).Type(variableType).Do)
+	if err != nil {
+		return nil, err
+	} else if len(resp.BuiltInVariable) == 0 {
+		return nil, fmt.Errorf("built-in variable %s was not enabled", variableType)
+	} else {
+		return resp.BuiltInVariable[0], nil
+	}
+}
+
+func (c *Client) ListBuiltInVariables(workspaceId string) ([]*tagmanager.BuiltInVariable, error) {
+	resp, err := c.getBuiltInVariableListWithRetry(c.Accounts.Containers.Workspaces.BuiltInVariables.List(c.workspacePath(workspaceId)).Do)
+	if err != nil {
+		return nil, err
+	} else {
+		return resp.BuiltInVariable, nil
+	}
+}
+
+func (c *Client) DisableBuiltInVariable(workspaceId string, variableType string) error {
+	return c.executeWithRetry(c.Accounts.Containers.Workspaces.BuiltInVariables.Delete(c.workspacePath(workspaceId) + "/built_in_variables").Type(variableType).Do)
+}
+
+// shouldResolveConflict reports whether a failed update should be retried with the
+// latest fingerprint of the entity.
+func (c *Client) shouldResolveConflict(err error) bool {
+	errTyped, ok := err.(*googleapi.Error)
+	return ok && errTyped.Code == 409 && c.Options.AutoResolveConflicts
+}
+
+func (c *Client) executeWithRetry(query func(opts ...googleapi.CallOption) error) error {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		// Apply throttling before making the request
+		c.throttle()
+
+		start := time.Now()
 		err := query()
-		if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 429 {
-			if retryCount < c.Options.RetryLimit {
-				retryCount++
-				backoffDuration := time.Duration(retryCount) * time.Second
-				fmt.Printf("Rate limit exceeded. Retrying in %s...\n", backoffDuration)
-				time.Sleep(backoffDuration)
-				continue
-			} else {
-				return fmt.Errorf("rate limit exceeded after %d retries", c.Options.RetryLimit)
-			}
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return c.retryError(err, retryCount)
+		} else {
+			return nil
+		}
+	}
+}
+
+// Helper methods for different return types
+func (c *Client) getWorkspaceWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.Workspace, error)) (*tagmanager.Workspace, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getSyncWorkspaceWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.SyncWorkspaceResponse, error)) (*tagmanager.SyncWorkspaceResponse, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getWorkspaceStatusWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.GetWorkspaceStatusResponse, error)) (*tagmanager.GetWorkspaceStatusResponse, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
 		} else if err != nil {
-			return err
+			return nil, c.retryError(err, retryCount)
 		} else {
-			return nil
+			return resp, nil
 		}
 	}
 }
 
-// Helper methods for different return types
-func (c *Client) getWorkspaceWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.Workspace, error)) (*tagmanager.Workspace, error) {
+func (c *Client) getContainerListWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.ListContainersResponse, error)) (*tagmanager.ListContainersResponse, error) {
 	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
 
 	for {
 		c.throttle()
 
+		start := time.Now()
 		resp, err := query()
-		if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 429 {
-			if retryCount < c.Options.RetryLimit {
-				retryCount++
-				backoffDuration := 20 * time.Second * time.Duration(retryCount)
-				fmt.Printf("Rate limit exceeded. Retrying in %s...\n", backoffDuration)
-				time.Sleep(backoffDuration)
-				continue
-			} else {
-				return nil, fmt.Errorf("rate limit exceeded after %d retries", c.Options.RetryLimit)
-			}
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
 		} else if err != nil {
-			return nil, err
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getContainerWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.Container, error)) (*tagmanager.Container, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getDestinationWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.Destination, error)) (*tagmanager.Destination, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getDestinationListWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.ListDestinationsResponse, error)) (*tagmanager.ListDestinationsResponse, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getEnvironmentWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.Environment, error)) (*tagmanager.Environment, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getEnvironmentListWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.ListEnvironmentsResponse, error)) (*tagmanager.ListEnvironmentsResponse, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getContainerVersionWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.ContainerVersion, error)) (*tagmanager.ContainerVersion, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getCreateVersionWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.CreateContainerVersionResponse, error)) (*tagmanager.CreateContainerVersionResponse, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getQuickPreviewWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.QuickPreviewResponse, error)) (*tagmanager.QuickPreviewResponse, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getVersionHeaderWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.ContainerVersionHeader, error)) (*tagmanager.ContainerVersionHeader, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
 		} else {
 			return resp, nil
 		}
@@ -372,23 +1744,21 @@ func (c *Client) getWorkspaceWithRetry(query func(opts ...googleapi.CallOption)
 
 func (c *Client) getWorkspaceListWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.ListWorkspacesResponse, error)) (*tagmanager.ListWorkspacesResponse, error) {
 	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
 
 	for {
 		c.throttle()
 
+		start := time.Now()
 		resp, err := query()
-		if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 429 {
-			if retryCount < c.Options.RetryLimit {
-				retryCount++
-				backoffDuration := 20 * time.Second * time.Duration(retryCount)
-				fmt.Printf("Rate limit exceeded. Retrying in %s...\n", backoffDuration)
-				time.Sleep(backoffDuration)
-				continue
-			} else {
-				return nil, fmt.Errorf("rate limit exceeded after %d retries", c.Options.RetryLimit)
-			}
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
 		} else if err != nil {
-			return nil, err
+			return nil, c.retryError(err, retryCount)
 		} else {
 			return resp, nil
 		}
@@ -397,23 +1767,21 @@ func (c *Client) getWorkspaceListWithRetry(query func(opts ...googleapi.CallOpti
 
 func (c *Client) getTagWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.Tag, error)) (*tagmanager.Tag, error) {
 	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
 
 	for {
 		c.throttle()
 
+		start := time.Now()
 		resp, err := query()
-		if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 429 {
-			if retryCount < c.Options.RetryLimit {
-				retryCount++
-				backoffDuration := 20 * time.Second * time.Duration(retryCount)
-				fmt.Printf("Rate limit exceeded. Retrying in %s...\n", backoffDuration)
-				time.Sleep(backoffDuration)
-				continue
-			} else {
-				return nil, fmt.Errorf("rate limit exceeded after %d retries", c.Options.RetryLimit)
-			}
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
 		} else if err != nil {
-			return nil, err
+			return nil, c.retryError(err, retryCount)
 		} else {
 			return resp, nil
 		}
@@ -422,23 +1790,21 @@ func (c *Client) getTagWithRetry(query func(opts ...googleapi.CallOption) (*tagm
 
 func (c *Client) getTagListWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.ListTagsResponse, error)) (*tagmanager.ListTagsResponse, error) {
 	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
 
 	for {
 		c.throttle()
 
+		start := time.Now()
 		resp, err := query()
-		if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 429 {
-			if retryCount < c.Options.RetryLimit {
-				retryCount++
-				backoffDuration := 20 * time.Second * time.Duration(retryCount)
-				fmt.Printf("Rate limit exceeded. Retrying in %s...\n", backoffDuration)
-				time.Sleep(backoffDuration)
-				continue
-			} else {
-				return nil, fmt.Errorf("rate limit exceeded after %d retries", c.Options.RetryLimit)
-			}
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
 		} else if err != nil {
-			return nil, err
+			return nil, c.retryError(err, retryCount)
 		} else {
 			return resp, nil
 		}
@@ -447,23 +1813,21 @@ func (c *Client) getTagListWithRetry(query func(opts ...googleapi.CallOption) (*
 
 func (c *Client) getVariableWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.Variable, error)) (*tagmanager.Variable, error) {
 	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
 
 	for {
 		c.throttle()
 
+		start := time.Now()
 		resp, err := query()
-		if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 429 {
-			if retryCount < c.Options.RetryLimit {
-				retryCount++
-				backoffDuration := 20 * time.Second * time.Duration(retryCount)
-				fmt.Printf("Rate limit exceeded. Retrying in %s...\n", backoffDuration)
-				time.Sleep(backoffDuration)
-				continue
-			} else {
-				return nil, fmt.Errorf("rate limit exceeded after %d retries", c.Options.RetryLimit)
-			}
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
 		} else if err != nil {
-			return nil, err
+			return nil, c.retryError(err, retryCount)
 		} else {
 			return resp, nil
 		}
@@ -472,23 +1836,21 @@ func (c *Client) getVariableWithRetry(query func(opts ...googleapi.CallOption) (
 
 func (c *Client) getVariableListWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.ListVariablesResponse, error)) (*tagmanager.ListVariablesResponse, error) {
 	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
 
 	for {
 		c.throttle()
 
+		start := time.Now()
 		resp, err := query()
-		if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 429 {
-			if retryCount < c.Options.RetryLimit {
-				retryCount++
-				backoffDuration := 20 * time.Second * time.Duration(retryCount)
-				fmt.Printf("Rate limit exceeded. Retrying in %s...\n", backoffDuration)
-				time.Sleep(backoffDuration)
-				continue
-			} else {
-				return nil, fmt.Errorf("rate limit exceeded after %d retries", c.Options.RetryLimit)
-			}
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
 		} else if err != nil {
-			return nil, err
+			return nil, c.retryError(err, retryCount)
 		} else {
 			return resp, nil
 		}
@@ -497,23 +1859,21 @@ func (c *Client) getVariableListWithRetry(query func(opts ...googleapi.CallOptio
 
 func (c *Client) getTriggerWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.Trigger, error)) (*tagmanager.Trigger, error) {
 	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
 
 	for {
 		c.throttle()
 
+		start := time.Now()
 		resp, err := query()
-		if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 429 {
-			if retryCount < c.Options.RetryLimit {
-				retryCount++
-				backoffDuration := 20 * time.Second * time.Duration(retryCount)
-				fmt.Printf("Rate limit exceeded. Retrying in %s...\n", backoffDuration)
-				time.Sleep(backoffDuration)
-				continue
-			} else {
-				return nil, fmt.Errorf("rate limit exceeded after %d retries", c.Options.RetryLimit)
-			}
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
 		} else if err != nil {
-			return nil, err
+			return nil, c.retryError(err, retryCount)
 		} else {
 			return resp, nil
 		}
@@ -522,23 +1882,228 @@ func (c *Client) getTriggerWithRetry(query func(opts ...googleapi.CallOption) (*
 
 func (c *Client) getTriggerListWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.ListTriggersResponse, error)) (*tagmanager.ListTriggersResponse, error) {
 	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
 
 	for {
 		c.throttle()
 
+		start := time.Now()
 		resp, err := query()
-		if errTyped, ok := err.(*googleapi.Error); ok && errTyped.Code == 429 {
-			if retryCount < c.Options.RetryLimit {
-				retryCount++
-				backoffDuration := 20 * time.Second * time.Duration(retryCount)
-				fmt.Printf("Rate limit exceeded. Retrying in %s...\n", backoffDuration)
-				time.Sleep(backoffDuration)
-				continue
-			} else {
-				return nil, fmt.Errorf("rate limit exceeded after %d retries", c.Options.RetryLimit)
-			}
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
 		} else if err != nil {
-			return nil, err
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getTemplateWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.CustomTemplate, error)) (*tagmanager.CustomTemplate, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getGtagConfigWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.GtagConfig, error)) (*tagmanager.GtagConfig, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getServerClientWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.Client, error)) (*tagmanager.Client, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getTemplateListWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.ListTemplatesResponse, error)) (*tagmanager.ListTemplatesResponse, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getBuiltInVariableCreateWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.CreateBuiltInVariableResponse, error)) (*tagmanager.CreateBuiltInVariableResponse, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getBuiltInVariableListWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.ListEnabledBuiltInVariablesResponse, error)) (*tagmanager.ListEnabledBuiltInVariablesResponse, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getFolderWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.Folder, error)) (*tagmanager.Folder, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getFolderListWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.ListFoldersResponse, error)) (*tagmanager.ListFoldersResponse, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
+		} else {
+			return resp, nil
+		}
+	}
+}
+
+func (c *Client) getFolderEntitiesWithRetry(query func(opts ...googleapi.CallOption) (*tagmanager.FolderEntities, error)) (*tagmanager.FolderEntities, error) {
+	retryCount := 0
+	requestId := uuid.NewString()
+	firstAttempt := time.Now()
+
+	for {
+		c.throttle()
+
+		start := time.Now()
+		resp, err := query()
+		c.logRequest(requestId, retryCount, start, err)
+		if backoffDuration, retry := c.retryDelay(err, retryCount, firstAttempt, 20*time.Second); retry {
+			retryCount++
+			time.Sleep(backoffDuration)
+			continue
+		} else if err != nil {
+			return nil, c.retryError(err, retryCount)
 		} else {
 			return resp, nil
 		}
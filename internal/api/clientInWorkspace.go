@@ -1,8 +1,14 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/tagmanager/v2"
 )
 
@@ -10,8 +16,27 @@ type ClientInWorkspaceOptions struct {
 	*ClientOptions
 	WorkspaceName string
 	WorkspaceId   string
+	NamePrefix    string // prefix the provider adds to the names of managed entities
+	ManagedByNote string // notes the provider sets on managed entities created without notes
+
+	// DefaultNotes is a template for the notes the provider sets on managed entities
+	// created without notes, with {resource_type} and {timestamp} placeholders. It
+	// takes precedence over ManagedByNote.
+	DefaultNotes string
+
+	// DefaultFiringTriggerId are the firing triggers the provider sets on tags that
+	// configure none.
+	DefaultFiringTriggerId []string
+
+	// RequireExistingWorkspace fails with ErrWorkspaceNotFound instead of creating the
+	// workspace when no workspace has the configured name.
+	RequireExistingWorkspace bool
 }
 
+// ErrWorkspaceNotFound is returned when the configured workspace does not exist and
+// creating it is disabled.
+var ErrWorkspaceNotFound = errors.New("workspace not found")
+
 // NewClientInWorkspaceOptionsFromEnv creates ClientInWorkspaceOptions from environment variables
 func NewClientInWorkspaceOptionsFromEnv() *ClientInWorkspaceOptions {
 	return &ClientInWorkspaceOptions{
@@ -31,40 +56,122 @@ func NewClientInWorkspaceFromEnv() (*ClientInWorkspace, error) {
 	return NewClientInWorkspace(NewClientInWorkspaceOptionsFromEnv())
 }
 
+// NewClientInWorkspace creates a client operating in the named workspace, creating the
+// workspace when it does not exist unless RequireExistingWorkspace is set. Without a
+// workspace name the client only supports account and container level calls.
 func NewClientInWorkspace(options *ClientInWorkspaceOptions) (*ClientInWorkspace, error) {
 	client, err := NewClient(options.ClientOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	workspaces, err := client.ListWorkspaces()
+	if options.WorkspaceName == "" {
+		return &ClientInWorkspace{
+			Client:  client,
+			Options: options,
+		}, nil
+	}
+
+	workspaceId, err := client.workspaceId(options.WorkspaceName, options.RequireExistingWorkspace)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, workspace := range workspaces {
-		if workspace.Name == options.WorkspaceName {
-			options.WorkspaceId = workspace.WorkspaceId
+	options.WorkspaceId = workspaceId
+	return &ClientInWorkspace{
+		Client:  client,
+		Options: options,
+	}, nil
+}
+
+// workspaceId returns the id of the named workspace, creating the workspace when it
+// does not exist unless requireExisting is set. Concurrent calls for the same workspace
+// share one resolution. Its requests are throttled and retried when rate-limited like
+// every other request, so that a 429 while the provider is configured does not fail it.
+func (c *Client) workspaceId(name string, requireExisting bool) (string, error) {
+	id, err, _ := resolutions.Do(c.resolutionKey("workspace", name, strconv.FormatBool(requireExisting)), func() (any, error) {
+		return c.findOrCreateWorkspace(name, requireExisting)
+	})
+	if err != nil {
+		return "", err
+	}
 
-			return &ClientInWorkspace{
-				Client:  client,
-				Options: options,
-			}, nil
+	return id.(string), nil
+}
+
+func (c *Client) findOrCreateWorkspace(name string, requireExisting bool) (string, error) {
+	workspaces, err := c.ListWorkspaces()
+	if errTyped, ok := err.(*googleapi.Error); ok && isInsufficientScopes(errTyped) {
+		return "", insufficientScopesError(errTyped)
+	} else if err != nil {
+		return "", err
+	}
+
+	var names = make([]string, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		if workspace.Name == name {
+			return workspace.WorkspaceId, nil
 		}
+		names = append(names, strconv.Quote(workspace.Name))
 	}
 
-	workspace, err := client.CreateWorkspace(&tagmanager.Workspace{Name: options.WorkspaceName})
+	if requireExisting {
+		return "", fmt.Errorf("%w: %q is not a workspace of container %s, existing workspaces are %s",
+			ErrWorkspaceNotFound, name, c.Options.ContainerId, strings.Join(names, ", "))
+	}
+
+	workspace, err := c.CreateWorkspace(&tagmanager.Workspace{Name: name})
 	if err != nil {
-		return nil, err
-	} else {
-		options.WorkspaceId = workspace.WorkspaceId
-		return &ClientInWorkspace{
-			Client:  client,
-			Options: options,
-		}, nil
+		return "", err
+	}
+
+	return workspace.WorkspaceId, nil
+}
+
+// WithRetryLimit returns a client in the same workspace that retries rate-limited
+// requests up to limit times.
+func (c *ClientInWorkspace) WithRetryLimit(limit int) *ClientInWorkspace {
+	client := c.Client.WithRetryLimit(limit)
+
+	options := *c.Options
+	options.ClientOptions = client.Options
+
+	return &ClientInWorkspace{
+		Client:  client,
+		Options: &options,
+	}
+}
+
+// WithRequestLogger returns a client in the same workspace that passes every attempt
+// of its API requests to logger.
+func (c *ClientInWorkspace) WithRequestLogger(logger RequestLogger) *ClientInWorkspace {
+	return &ClientInWorkspace{
+		Client:  c.Client.WithRequestLogger(logger),
+		Options: c.Options,
 	}
 }
 
+// HasWorkspace reports whether the client was configured with a workspace.
+func (c *ClientInWorkspace) HasWorkspace() bool {
+	return c.Options.WorkspaceId != ""
+}
+
+// WorkspaceStatus reads the changes and merge conflicts pending in the workspace.
+func (c *ClientInWorkspace) WorkspaceStatus() (*tagmanager.GetWorkspaceStatusResponse, error) {
+	return c.Client.WorkspaceStatus(c.Options.WorkspaceId)
+}
+
+// EntityPath returns the full GTM path of an entity of the workspace, e.g.
+// accounts/1/containers/2/workspaces/3/tags/4.
+func (c *ClientInWorkspace) EntityPath(collection string, id string) string {
+	return c.workspacePath(c.Options.WorkspaceId) + "/" + collection + "/" + id
+}
+
+// ResolveConflict resolves a merge conflict of the workspace like Client.ResolveConflict.
+func (c *ClientInWorkspace) ResolveConflict(entity *tagmanager.Entity) error {
+	return c.Client.ResolveConflict(c.Options.WorkspaceId, entity)
+}
+
 // Tag CRUD
 
 func (c *ClientInWorkspace) CreateTag(tag *tagmanager.Tag) (*tagmanager.Tag, error) {
@@ -75,8 +182,8 @@ func (c *ClientInWorkspace) ListTags() ([]*tagmanager.Tag, error) {
 	return c.Client.ListTags(c.Options.WorkspaceId)
 }
 
-func (c *ClientInWorkspace) Tag(tagId string) (*tagmanager.Tag, error) {
-	return c.Client.Tag(c.Options.WorkspaceId, tagId)
+func (c *ClientInWorkspace) Tag(tagId string, fields ...googleapi.Field) (*tagmanager.Tag, error) {
+	return c.Client.Tag(c.Options.WorkspaceId, tagId, fields...)
 }
 
 func (c *ClientInWorkspace) UpdateTag(tagId string, tag *tagmanager.Tag) (*tagmanager.Tag, error) {
@@ -84,7 +191,14 @@ func (c *ClientInWorkspace) UpdateTag(tagId string, tag *tagmanager.Tag) (*tagma
 }
 
 func (c *ClientInWorkspace) DeleteTag(tagId string) error {
-	return c.Client.DeleteTag(c.Options.WorkspaceId, tagId)
+	if err := c.Client.DeleteTag(c.Options.WorkspaceId, tagId); err != nil {
+		return err
+	}
+
+	return c.confirmDeleted(func() error {
+		_, err := c.Tag(tagId)
+		return err
+	})
 }
 
 // Variable CRUD
@@ -97,8 +211,8 @@ func (c *ClientInWorkspace) ListVariables() ([]*tagmanager.Variable, error) {
 	return c.Client.ListVariables(c.Options.WorkspaceId)
 }
 
-func (c *ClientInWorkspace) Variable(variableId string) (*tagmanager.Variable, error) {
-	return c.Client.Variable(c.Options.WorkspaceId, variableId)
+func (c *ClientInWorkspace) Variable(variableId string, fields ...googleapi.Field) (*tagmanager.Variable, error) {
+	return c.Client.Variable(c.Options.WorkspaceId, variableId, fields...)
 }
 
 func (c *ClientInWorkspace) UpdateVariable(variableId string, variable *tagmanager.Variable) (*tagmanager.Variable, error) {
@@ -106,7 +220,14 @@ func (c *ClientInWorkspace) UpdateVariable(variableId string, variable *tagmanag
 }
 
 func (c *ClientInWorkspace) DeleteVariable(variableId string) error {
-	return c.Client.DeleteVariable(c.Options.WorkspaceId, variableId)
+	if err := c.Client.DeleteVariable(c.Options.WorkspaceId, variableId); err != nil {
+		return err
+	}
+
+	return c.confirmDeleted(func() error {
+		_, err := c.Variable(variableId)
+		return err
+	})
 }
 
 // Trigger CRUD
@@ -119,8 +240,8 @@ func (c *ClientInWorkspace) ListTriggers() ([]*tagmanager.Trigger, error) {
 	return c.Client.ListTriggers(c.Options.WorkspaceId)
 }
 
-func (c *ClientInWorkspace) Trigger(triggerId string) (*tagmanager.Trigger, error) {
-	return c.Client.Trigger(c.Options.WorkspaceId, triggerId)
+func (c *ClientInWorkspace) Trigger(triggerId string, fields ...googleapi.Field) (*tagmanager.Trigger, error) {
+	return c.Client.Trigger(c.Options.WorkspaceId, triggerId, fields...)
 }
 
 func (c *ClientInWorkspace) UpdateTrigger(triggerId string, trigger *tagmanager.Trigger) (*tagmanager.Trigger, error) {
@@ -128,5 +249,262 @@ func (c *ClientInWorkspace) UpdateTrigger(triggerId string, trigger *tagmanager.
 }
 
 func (c *ClientInWorkspace) DeleteTrigger(triggerId string) error {
-	return c.Client.DeleteTrigger(c.Options.WorkspaceId, triggerId)
+	if err := c.Client.DeleteTrigger(c.Options.WorkspaceId, triggerId); err != nil {
+		return err
+	}
+
+	return c.confirmDeleted(func() error {
+		_, err := c.Trigger(triggerId)
+		return err
+	})
+}
+
+// Version
+
+func (c *ClientInWorkspace) CreateVersion(options *tagmanager.CreateContainerVersionRequestVersionOptions) (*tagmanager.CreateContainerVersionResponse, error) {
+	return c.Client.CreateVersion(c.Options.WorkspaceId, options)
+}
+
+func (c *ClientInWorkspace) GetWorkspaceCompilerStatus() (*WorkspaceCompilerStatus, error) {
+	return c.Client.GetWorkspaceCompilerStatus(c.Options.WorkspaceId)
+}
+
+// Template CRUD
+
+func (c *ClientInWorkspace) CreateTemplate(template *tagmanager.CustomTemplate) (*tagmanager.CustomTemplate, error) {
+	return c.Client.CreateTemplate(c.Options.WorkspaceId, template)
+}
+
+func (c *ClientInWorkspace) ListTemplates() ([]*tagmanager.CustomTemplate, error) {
+	return c.Client.ListTemplates(c.Options.WorkspaceId)
+}
+
+func (c *ClientInWorkspace) Template(templateId string) (*tagmanager.CustomTemplate, error) {
+	return c.Client.Template(c.Options.WorkspaceId, templateId)
+}
+
+func (c *ClientInWorkspace) UpdateTemplate(templateId string, template *tagmanager.CustomTemplate) (*tagmanager.CustomTemplate, error) {
+	return c.Client.UpdateTemplate(c.Options.WorkspaceId, templateId, template)
+}
+
+func (c *ClientInWorkspace) DeleteTemplate(templateId string) error {
+	return c.Client.DeleteTemplate(c.Options.WorkspaceId, templateId)
+}
+
+// Gtag config CRUD
+
+func (c *ClientInWorkspace) CreateGtagConfig(gtagConfig *tagmanager.GtagConfig) (*tagmanager.GtagConfig, error) {
+	return c.Client.CreateGtagConfig(c.Options.WorkspaceId, gtagConfig)
+}
+
+func (c *ClientInWorkspace) GtagConfig(gtagConfigId string) (*tagmanager.GtagConfig, error) {
+	return c.Client.GtagConfig(c.Options.WorkspaceId, gtagConfigId)
+}
+
+func (c *ClientInWorkspace) UpdateGtagConfig(gtagConfigId string, gtagConfig *tagmanager.GtagConfig) (*tagmanager.GtagConfig, error) {
+	return c.Client.UpdateGtagConfig(c.Options.WorkspaceId, gtagConfigId, gtagConfig)
+}
+
+func (c *ClientInWorkspace) DeleteGtagConfig(gtagConfigId string) error {
+	return c.Client.DeleteGtagConfig(c.Options.WorkspaceId, gtagConfigId)
+}
+
+// Server client CRUD
+
+func (c *ClientInWorkspace) CreateServerClient(client *tagmanager.Client) (*tagmanager.Client, error) {
+	return c.Client.CreateServerClient(c.Options.WorkspaceId, client)
+}
+
+func (c *ClientInWorkspace) ServerClient(clientId string) (*tagmanager.Client, error) {
+	return c.Client.ServerClient(c.Options.WorkspaceId, clientId)
+}
+
+func (c *ClientInWorkspace) UpdateServerClient(clientId string, client *tagmanager.Client) (*tagmanager.Client, error) {
+	return c.Client.UpdateServerClient(c.Options.WorkspaceId, clientId, client)
+}
+
+func (c *ClientInWorkspace) DeleteServerClient(clientId string) error {
+	return c.Client.DeleteServerClient(c.Options.WorkspaceId, clientId)
+}
+
+// Folder CRUD
+
+func (c *ClientInWorkspace) CreateFolder(folder *tagmanager.Folder) (*tagmanager.Folder, error) {
+	return c.Client.CreateFolder(c.Options.WorkspaceId, folder)
+}
+
+func (c *ClientInWorkspace) ListFolders() ([]*tagmanager.Folder, error) {
+	return c.Client.ListFolders(c.Options.WorkspaceId)
+}
+
+func (c *ClientInWorkspace) Folder(folderId string) (*tagmanager.Folder, error) {
+	return c.Client.Folder(c.Options.WorkspaceId, folderId)
+}
+
+func (c *ClientInWorkspace) UpdateFolder(folderId string, folder *tagmanager.Folder) (*tagmanager.Folder, error) {
+	return c.Client.UpdateFolder(c.Options.WorkspaceId, folderId, folder)
+}
+
+func (c *ClientInWorkspace) DeleteFolder(folderId string) error {
+	return c.Client.DeleteFolder(c.Options.WorkspaceId, folderId)
+}
+
+func (c *ClientInWorkspace) FolderEntities(folderId string) (*tagmanager.FolderEntities, error) {
+	return c.Client.FolderEntities(c.Options.WorkspaceId, folderId)
+}
+
+func (c *ClientInWorkspace) MoveEntitiesToFolder(folderId string, tagIds []string, triggerIds []string, variableIds []string) error {
+	return c.Client.MoveEntitiesToFolder(c.Options.WorkspaceId, folderId, tagIds, triggerIds, variableIds)
+}
+
+// Built-in variables
+
+func (c *ClientInWorkspace) EnableBuiltInVariable(variableType string) (*tagmanager.BuiltInVariable, error) {
+	return c.Client.EnableBuiltInVariable(c.Options.WorkspaceId, variableType)
+}
+
+func (c *ClientInWorkspace) ListBuiltInVariables() ([]*tagmanager.BuiltInVariable, error) {
+	return c.Client.ListBuiltInVariables(c.Options.WorkspaceId)
+}
+
+func (c *ClientInWorkspace) DisableBuiltInVariable(variableType string) error {
+	return c.Client.DisableBuiltInVariable(c.Options.WorkspaceId, variableType)
+}
+
+// deleteConcurrency bounds the number of deletes in flight during DeleteAllInWorkspace.
+// Every call still goes through the client rate limiter.
+const deleteConcurrency = 4
+
+// DeleteAllInWorkspace removes every tag, trigger and variable of the workspace.
+// Tags go first since they reference triggers, and triggers may reference variables.
+func (c *ClientInWorkspace) DeleteAllInWorkspace() error {
+	tags, err := c.ListTags()
+	if err != nil {
+		return err
+	}
+
+	var tagIds []string
+	for _, tag := range tags {
+		tagIds = append(tagIds, tag.TagId)
+	}
+	if err := deleteConcurrently(tagIds, c.DeleteTag); err != nil {
+		return err
+	}
+
+	triggers, err := c.ListTriggers()
+	if err != nil {
+		return err
+	}
+
+	var triggerIds []string
+	for _, trigger := range triggers {
+		triggerIds = append(triggerIds, trigger.TriggerId)
+	}
+	if err := deleteConcurrently(triggerIds, c.DeleteTrigger); err != nil {
+		return err
+	}
+
+	variables, err := c.ListVariables()
+	if err != nil {
+		return err
+	}
+
+	var variableIds []string
+	for _, variable := range variables {
+		variableIds = append(variableIds, variable.VariableId)
+	}
+	return deleteConcurrently(variableIds, c.DeleteVariable)
+}
+
+func deleteConcurrently(ids []string, del func(id string) error) error {
+	var mutex sync.Mutex
+	var errs []error
+
+	forEachConcurrently(len(ids), deleteConcurrency, func(i int) {
+		if err := del(ids[i]); err != nil {
+			mutex.Lock()
+			errs = append(errs, err)
+			mutex.Unlock()
+		}
+	})
+
+	return errors.Join(errs...)
+}
+
+// forEachConcurrently calls do with each index below n, with at most limit calls in
+// flight, and returns once every call has returned.
+func forEachConcurrently(n int, limit int, do func(i int)) {
+	var wg sync.WaitGroup
+
+	sem := make(chan struct{}, limit)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			do(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// createConcurrency bounds the number of creates in flight during a batch create.
+const createConcurrency = 4
+
+// TagResult is the outcome of creating one tag of a batch.
+type TagResult struct {
+	Tag *tagmanager.Tag
+	Err error
+}
+
+// TriggerResult is the outcome of creating one trigger of a batch.
+type TriggerResult struct {
+	Trigger *tagmanager.Trigger
+	Err     error
+}
+
+// VariableResult is the outcome of creating one variable of a batch.
+type VariableResult struct {
+	Variable *tagmanager.Variable
+	Err      error
+}
+
+// CreateTags creates the tags concurrently under the rate limiter of the client and
+// returns the result of each tag in the order of tags. A failed create does not stop
+// the others.
+func (c *ClientInWorkspace) CreateTags(tags []*tagmanager.Tag) []TagResult {
+	results := make([]TagResult, len(tags))
+
+	forEachConcurrently(len(tags), createConcurrency, func(i int) {
+		results[i].Tag, results[i].Err = c.CreateTag(tags[i])
+	})
+
+	return results
+}
+
+// CreateTriggers creates the triggers concurrently like CreateTags.
+func (c *ClientInWorkspace) CreateTriggers(triggers []*tagmanager.Trigger) []TriggerResult {
+	results := make([]TriggerResult, len(triggers))
+
+	forEachConcurrently(len(triggers), createConcurrency, func(i int) {
+		results[i].Trigger, results[i].Err = c.CreateTrigger(triggers[i])
+	})
+
+	return results
+}
+
+// CreateVariables creates the variables concurrently like CreateTags.
+func (c *ClientInWorkspace) CreateVariables(variables []*tagmanager.Variable) []VariableResult {
+	results := make([]VariableResult, len(variables))
+
+	forEachConcurrently(len(variables), createConcurrency, func(i int) {
+		results[i].Variable, results[i].Err = c.CreateVariable(variables[i])
+	})
+
+	return results
 }
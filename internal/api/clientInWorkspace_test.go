@@ -1,10 +1,18 @@
 package api
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/api/option"
 	"google.golang.org/api/tagmanager/v2"
 )
 
@@ -597,3 +605,246 @@ func (suite *ClientInWorkspaceTestSuite) TestTriggerDelete() {
 func TestClientInWorkspace(t *testing.T) {
 	suite.Run(t, new(ClientInWorkspaceTestSuite))
 }
+
+func TestClientInWorkspaceDeleteAll(t *testing.T) {
+	options := setupTestClientInWorkspaceOptions()
+	options.WorkspaceName = testName("test-delete-all")
+
+	client, err := NewClientInWorkspace(options)
+	if err != nil {
+		t.Fatalf("Failed to create client in workspace: %v", err)
+	}
+	defer client.DeleteWorkspace(client.Options.WorkspaceId)
+
+	for i := 0; i < 3; i++ {
+		trigger, err := client.CreateTrigger(&tagmanager.Trigger{
+			Name: fmt.Sprintf("%s-%d", testName("test-delete-all-trigger"), i),
+			Type: "pageview",
+		})
+		assert.NoError(t, err)
+
+		_, err = client.CreateTag(&tagmanager.Tag{
+			Name:            fmt.Sprintf("%s-%d", testName("test-delete-all-tag"), i),
+			Type:            "html",
+			Parameter:       []*tagmanager.Parameter{{Key: "html", Type: "template", Value: "<p>test</p>"}},
+			FiringTriggerId: []string{trigger.TriggerId},
+		})
+		assert.NoError(t, err)
+
+		_, err = client.CreateVariable(&tagmanager.Variable{
+			Name:      fmt.Sprintf("%s-%d", testName("test-delete-all-variable"), i),
+			Type:      "v",
+			Parameter: []*tagmanager.Parameter{{Key: "name", Type: "template", Value: "test"}},
+		})
+		assert.NoError(t, err)
+	}
+
+	err = client.DeleteAllInWorkspace()
+	assert.NoError(t, err)
+
+	tags, err := client.ListTags()
+	assert.NoError(t, err)
+	assert.Empty(t, tags)
+
+	triggers, err := client.ListTriggers()
+	assert.NoError(t, err)
+	assert.Empty(t, triggers)
+
+	variables, err := client.ListVariables()
+	assert.NoError(t, err)
+	assert.Empty(t, variables)
+}
+
+func TestCreateTags(t *testing.T) {
+	const count = 20
+	const latency = 50 * time.Millisecond
+
+	var created atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasSuffix(r.URL.Path, "/accounts/1/containers/2/workspaces/3/tags"), r.URL.Path)
+		time.Sleep(latency)
+
+		id := created.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"tagId": "%d", "name": "tag-%d"}`, id, id)))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &ClientInWorkspace{
+		Client:  &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}},
+		Options: &ClientInWorkspaceOptions{WorkspaceId: "3"},
+	}
+
+	var tags []*tagmanager.Tag
+	for i := 0; i < count; i++ {
+		tags = append(tags, &tagmanager.Tag{Name: fmt.Sprintf("tag-%d", i), Type: "html"})
+	}
+
+	start := time.Now()
+	results := client.CreateTags(tags)
+	elapsed := time.Since(start)
+
+	assert.Len(t, results, count)
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+		assert.NotEmpty(t, result.Tag.TagId)
+	}
+	assert.Less(t, elapsed, count*latency)
+}
+
+func TestWorkspaceIdRequireExisting(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"workspace": [{"workspaceId": "3", "name": "Default Workspace"}, {"workspaceId": "4", "name": "staging"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"workspaceId": "5", "name": "typo"}`))
+		}
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+	id, err := client.workspaceId("staging", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "4", id)
+
+	_, err = client.workspaceId("typo", true)
+	assert.ErrorIs(t, err, ErrWorkspaceNotFound)
+	assert.ErrorContains(t, err, `existing workspaces are "Default Workspace", "staging"`)
+	assert.NotContains(t, requests, http.MethodPost)
+
+	id, err = client.workspaceId("typo", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "5", id)
+	assert.Contains(t, requests, http.MethodPost)
+}
+
+func TestWorkspaceIdRetriesRateLimit(t *testing.T) {
+	var lists atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if lists.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error": {"code": 429, "message": "Quota exceeded"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"workspace": [{"workspaceId": "4", "name": "rate-limited"}]}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{
+		Service:     srv,
+		Options:     &ClientOptions{AccountId: "1", ContainerId: "2", RetryLimit: 1},
+		rateLimiter: NewRateLimiter(100, 1),
+	}
+
+	id, err := client.workspaceId("rate-limited", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "4", id)
+	assert.Equal(t, int32(2), lists.Load())
+
+	// Without retries the rate-limited resolution fails the client
+	lists.Store(0)
+	client.Options.RetryLimit = 0
+
+	_, err = client.workspaceId("rate-limited", true)
+	assert.ErrorContains(t, err, "rate limit exceeded")
+}
+
+func TestWorkspaceIdConcurrent(t *testing.T) {
+	var lists, creates atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			lists.Add(1)
+			_, _ = w.Write([]byte(`{"workspace": [{"workspaceId": "3", "name": "Default Workspace"}]}`))
+		default:
+			creates.Add(1)
+			_, _ = w.Write([]byte(`{"workspaceId": "5", "name": "concurrent"}`))
+		}
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	const resolvers = 10
+	var ids = make(chan string, resolvers)
+	for i := 0; i < resolvers; i++ {
+		go func() {
+			client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+			id, err := client.workspaceId("concurrent", false)
+			assert.NoError(t, err)
+			ids <- id
+		}()
+	}
+
+	// Let every resolver join the resolution that is waiting for the server
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < resolvers; i++ {
+		assert.Equal(t, "5", <-ids)
+	}
+	assert.Equal(t, int32(1), lists.Load())
+	assert.Equal(t, int32(1), creates.Load())
+}
+
+func TestDeleteTagConfirmDeletes(t *testing.T) {
+	deleteConfirmInterval = time.Millisecond
+	defer func() { deleteConfirmInterval = 500 * time.Millisecond }()
+
+	var reads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case reads < 2:
+			// GTM still serves the tag for a moment after deleting it.
+			reads++
+			_, _ = w.Write([]byte(`{"tagId": "5"}`))
+		default:
+			reads++
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": 404, "message": "Not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &ClientInWorkspace{
+		Client:  &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2", ConfirmDeletes: true}},
+		Options: &ClientInWorkspaceOptions{WorkspaceId: "3"},
+	}
+
+	assert.NoError(t, client.DeleteTag("5"))
+	assert.Equal(t, 3, reads)
+
+	reads = -deleteConfirmAttempts
+	assert.ErrorIs(t, client.DeleteTag("5"), ErrDeleteNotConfirmed)
+
+	reads = 0
+	client.Client.Options.ConfirmDeletes = false
+	assert.NoError(t, client.DeleteTag("5"))
+	assert.Equal(t, 0, reads)
+}
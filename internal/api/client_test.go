@@ -1,10 +1,20 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
 	"google.golang.org/api/tagmanager/v2"
 )
 
@@ -236,3 +246,806 @@ func TestClientTriggerCRUD(t *testing.T) {
 	err = client.DeleteTrigger(ws.WorkspaceId, trigger.TriggerId)
 	assert.NoError(t, err)
 }
+
+func TestRetryLimitFromEnv(t *testing.T) {
+	t.Setenv(EnvRetryLimit, "0")
+	assert.Equal(t, 0, NewClientOptionsFromEnv().RetryLimit)
+
+	t.Setenv(EnvRetryLimit, "3")
+	assert.Equal(t, 3, NewClientOptionsFromEnv().RetryLimit)
+
+	t.Setenv(EnvRetryLimit, "-1")
+	assert.Equal(t, 10, NewClientOptionsFromEnv().RetryLimit)
+
+	t.Setenv(EnvRetryLimit, "many")
+	assert.Equal(t, 10, NewClientOptionsFromEnv().RetryLimit)
+}
+
+func TestRateLimiterRefillJitter(t *testing.T) {
+	// 50 requests per second refill a token every 20ms
+	limiter := NewRateLimiter(50, 1)
+	assert.Equal(t, 20*time.Millisecond, limiter.refillWait())
+
+	limiter = NewRateLimiter(50, 1, WithRefillJitter(10*time.Millisecond))
+	var waits = map[time.Duration]bool{}
+	for i := 0; i < 100; i++ {
+		wait := limiter.refillWait()
+		assert.GreaterOrEqual(t, wait, 20*time.Millisecond)
+		assert.Less(t, wait, 30*time.Millisecond)
+		waits[wait] = true
+	}
+	assert.Greater(t, len(waits), 1, "expected the refill waits to vary")
+
+	// Once the burst is consumed, Wait takes at least the refill interval
+	assert.True(t, limiter.Allow())
+	start := time.Now()
+	limiter.Wait()
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+func TestRateJitterFromEnv(t *testing.T) {
+	assert.Equal(t, time.Duration(0), NewClientOptionsFromEnv().RateJitter)
+
+	t.Setenv(EnvRateJitter, "250")
+	assert.Equal(t, 250*time.Millisecond, NewClientOptionsFromEnv().RateJitter)
+
+	t.Setenv(EnvRateJitter, "-1")
+	assert.Equal(t, time.Duration(0), NewClientOptionsFromEnv().RateJitter)
+}
+
+func TestCredentialSource(t *testing.T) {
+	all := ClientOptions{CredentialsJSON: "{}", CredentialFile: "key.json", ImpersonateServiceAccount: "sa@project.iam.gserviceaccount.com"}
+	assert.Equal(t, CredentialSourceJSON, all.CredentialSource())
+
+	all.CredentialsJSON = ""
+	assert.Equal(t, CredentialSourceFile, all.CredentialSource())
+
+	all.CredentialFile = ""
+	assert.Equal(t, CredentialSourceImpersonation, all.CredentialSource())
+
+	all.ImpersonateServiceAccount = ""
+	assert.Equal(t, CredentialSourceDefault, all.CredentialSource())
+}
+
+func TestCredentialsJSONWinsOverFile(t *testing.T) {
+	credentials := `{"type": "authorized_user", "client_id": "id", "client_secret": "secret", "refresh_token": "token"}`
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	_, err := NewClient(&ClientOptions{CredentialFile: missing})
+	assert.Error(t, err)
+
+	// The missing credential file would fail the client if it were used
+	client, err := NewClient(&ClientOptions{CredentialsJSON: credentials, CredentialFile: missing})
+	assert.NoError(t, err)
+	assert.NotNil(t, client.Service)
+}
+
+func TestBaseTransport(t *testing.T) {
+	transport := newBaseTransport(&ClientOptions{MaxIdleConns: 50, MaxIdleConnsPerHost: 10, IdleConnTimeout: 30 * time.Second})
+	assert.Equal(t, 50, transport.MaxIdleConns)
+	assert.Equal(t, 10, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 30*time.Second, transport.IdleConnTimeout)
+
+	// Unset options fall back to the defaults, not to those of http.DefaultTransport
+	transport = newBaseTransport(&ClientOptions{})
+	assert.Equal(t, DefaultMaxIdleConns, transport.MaxIdleConns)
+	assert.Equal(t, DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, DefaultIdleConnTimeout, transport.IdleConnTimeout)
+	assert.Equal(t, 0, http.DefaultTransport.(*http.Transport).MaxIdleConnsPerHost)
+}
+
+func TestRequestLogger(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error": {"code": 429, "message": "Quota exceeded"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"tagId": "4"}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	var entries []RequestLog
+	client := (&Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2", RetryLimit: 1}}).
+		WithRequestLogger(func(entry RequestLog) { entries = append(entries, entry) })
+
+	_, err = client.CreateTag("3", &tagmanager.Tag{Name: "tag"})
+	assert.NoError(t, err)
+
+	// The retry of the rate-limited attempt shares its request id
+	assert.Len(t, entries, 2)
+	assert.NotEmpty(t, entries[0].RequestId)
+	assert.Equal(t, entries[0].RequestId, entries[1].RequestId)
+	assert.Equal(t, []int{0, 1}, []int{entries[0].Attempt, entries[1].Attempt})
+	assert.Error(t, entries[0].Err)
+	assert.NoError(t, entries[1].Err)
+
+	_, err = client.CreateTag("3", &tagmanager.Tag{Name: "tag"})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.NotEqual(t, entries[0].RequestId, entries[2].RequestId)
+}
+
+func TestRetryLimitZeroDisablesRetries(t *testing.T) {
+	client := &Client{Options: &ClientOptions{RetryLimit: 0}}
+
+	calls := 0
+	err := client.executeWithRetry(func(opts ...googleapi.CallOption) error {
+		calls++
+		return &googleapi.Error{Code: 429}
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+// newTimeoutTestClient returns a client of server whose attempts time out after
+// requestTimeout, like the HTTP client of NewClient.
+func newTimeoutTestClient(t *testing.T, server *httptest.Server, options *ClientOptions) *Client {
+	httpClient := &http.Client{Timeout: options.RequestTimeout}
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication(), option.WithHTTPClient(httpClient))
+	assert.NoError(t, err)
+
+	return &Client{Service: srv, Options: options}
+}
+
+// hangingHandler answers after the first hanging requests time out.
+func hangingHandler(calls *int, hanging int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Reading the body lets the server notice the client giving up on a POST
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		*calls++
+		if *calls <= hanging {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"tagId": "4"}`))
+	}
+}
+
+func TestRequestTimeoutRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(hangingHandler(&calls, 3))
+	defer server.Close()
+
+	client := newTimeoutTestClient(t, server, &ClientOptions{
+		AccountId:        "1",
+		ContainerId:      "2",
+		RetryLimit:       5,
+		RequestTimeout:   50 * time.Millisecond,
+		MaxRetryDuration: 5 * time.Second,
+	})
+
+	// Every attempt times out quickly, but the budget leaves room for the retries
+	tag, err := client.Tag("3", "4")
+	assert.NoError(t, err)
+	assert.Equal(t, "4", tag.TagId)
+	assert.Equal(t, 4, calls)
+}
+
+func TestCreateTimeoutNotRetried(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(hangingHandler(&calls, 1))
+	defer server.Close()
+
+	client := newTimeoutTestClient(t, server, &ClientOptions{
+		AccountId:        "1",
+		ContainerId:      "2",
+		RetryLimit:       5,
+		RequestTimeout:   50 * time.Millisecond,
+		MaxRetryDuration: 5 * time.Second,
+	})
+
+	// The create may have succeeded in GTM, so sending it again could duplicate the tag
+	tag, err := client.CreateTag("3", &tagmanager.Tag{Name: "tag"})
+	assert.Nil(t, tag)
+	assert.ErrorContains(t, err, "request timed out after")
+	assert.Equal(t, 1, calls)
+
+	// Reads on the same client still retry timeouts
+	tag, err = client.Tag("3", "4")
+	assert.NoError(t, err)
+	assert.Equal(t, "4", tag.TagId)
+}
+
+func TestMaxRetryDurationStopsRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(hangingHandler(&calls, 100))
+	defer server.Close()
+
+	client := newTimeoutTestClient(t, server, &ClientOptions{
+		AccountId:        "1",
+		ContainerId:      "2",
+		RetryLimit:       100,
+		RequestTimeout:   50 * time.Millisecond,
+		MaxRetryDuration: 500 * time.Millisecond,
+	})
+
+	start := time.Now()
+	_, err := client.Tag("3", "4")
+
+	// The retries stop once the next one would end after MaxRetryDuration, long before
+	// the retry limit
+	assert.ErrorContains(t, err, "request timed out after")
+	assert.Less(t, calls, 5)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestTimeoutNotRetriedWithoutRequestTimeout(t *testing.T) {
+	client := &Client{Options: &ClientOptions{RetryLimit: 3}}
+
+	calls := 0
+	err := client.executeWithRetry(func(opts ...googleapi.CallOption) error {
+		calls++
+		return context.DeadlineExceeded
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, calls)
+}
+
+func TestWithRetryLimit(t *testing.T) {
+	client := &ClientInWorkspace{
+		Client:  &Client{Options: &ClientOptions{AccountId: "1", ContainerId: "2", RetryLimit: 3}},
+		Options: &ClientInWorkspaceOptions{WorkspaceId: "4"},
+	}
+
+	override := client.WithRetryLimit(20)
+
+	assert.Equal(t, 20, override.Client.Options.RetryLimit)
+	assert.Equal(t, 3, client.Client.Options.RetryLimit)
+	assert.Equal(t, "2", override.Client.Options.ContainerId)
+	assert.Equal(t, "4", override.Options.WorkspaceId)
+}
+
+func TestLatestVersionHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tagmanager/v2/accounts/1/containers/2/version_headers:latest", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"containerVersionId": "42", "name": "Release 42"}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+	header, err := client.LatestVersionHeader()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", header.ContainerVersionId)
+	assert.Equal(t, "Release 42", header.Name)
+}
+
+// newCreateWorkspaceFromVersionServer serves version 5 as the latest version, records
+// the requests, and fails the workspace create or the restore of version 5 on demand.
+func newCreateWorkspaceFromVersionServer(requests *[]string, failCreate bool, failRestore bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/tagmanager/v2/accounts/1/containers/2/version_headers:latest":
+			_, _ = w.Write([]byte(`{"containerVersionId": "5"}`))
+		case r.URL.Path == "/tagmanager/v2/accounts/1/containers/2/versions/5:set_latest" && failRestore,
+			r.URL.Path == "/tagmanager/v2/accounts/1/containers/2/workspaces" && failCreate:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"code": 400, "message": "Bad request"}}`))
+		case strings.HasSuffix(r.URL.Path, ":set_latest"):
+			_, _ = w.Write([]byte(`{"containerVersionId": "7"}`))
+		default:
+			_, _ = w.Write([]byte(`{"workspaceId": "3", "name": "hotfix"}`))
+		}
+	}))
+}
+
+func TestCreateWorkspaceFromVersion(t *testing.T) {
+	expected := []string{
+		"GET /tagmanager/v2/accounts/1/containers/2/version_headers:latest",
+		"POST /tagmanager/v2/accounts/1/containers/2/versions/7:set_latest",
+		"POST /tagmanager/v2/accounts/1/containers/2/workspaces",
+		"POST /tagmanager/v2/accounts/1/containers/2/versions/5:set_latest",
+	}
+
+	for _, failCreate := range []bool{false, true} {
+		var requests []string
+		server := newCreateWorkspaceFromVersionServer(&requests, failCreate, false)
+
+		srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+		assert.NoError(t, err)
+
+		client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+		// The previous latest version is set back, also when the create fails
+		ws, err := client.CreateWorkspaceFromVersion("7", &tagmanager.Workspace{Name: "hotfix"})
+		server.Close()
+		if failCreate {
+			assert.Nil(t, ws)
+			assert.Error(t, err)
+			assert.NotErrorIs(t, err, ErrLatestVersionNotRestored)
+		} else {
+			assert.NoError(t, err)
+			assert.Equal(t, "3", ws.WorkspaceId)
+		}
+		assert.Equal(t, expected, requests)
+	}
+}
+
+func TestCreateWorkspaceFromVersionRestoreFailed(t *testing.T) {
+	var requests []string
+	server := newCreateWorkspaceFromVersionServer(&requests, false, true)
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+	// The workspace is returned along with the error, so that it is not orphaned
+	ws, err := client.CreateWorkspaceFromVersion("7", &tagmanager.Workspace{Name: "hotfix"})
+	assert.ErrorIs(t, err, ErrLatestVersionNotRestored)
+	assert.Equal(t, "3", ws.WorkspaceId)
+}
+
+func TestCreateWorkspaceFromLatestVersion(t *testing.T) {
+	var requests []string
+	server := newCreateWorkspaceFromVersionServer(&requests, false, false)
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+	// Basing the workspace on the latest version needs no change of the latest version
+	ws, err := client.CreateWorkspaceFromVersion("5", &tagmanager.Workspace{Name: "hotfix"})
+	assert.NoError(t, err)
+	assert.Equal(t, "3", ws.WorkspaceId)
+	assert.Equal(t, []string{
+		"GET /tagmanager/v2/accounts/1/containers/2/version_headers:latest",
+		"POST /tagmanager/v2/accounts/1/containers/2/workspaces",
+	}, requests)
+}
+
+func TestCreateWorkspaceLimitReached(t *testing.T) {
+	for _, message := range []string{"Workspace limit reached", "Maximum number of workspaces reached for this container."} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			_, _ = fmt.Fprintf(w, `{"error": {"code": 409, "message": %q}}`, message)
+		}))
+
+		srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+		assert.NoError(t, err)
+
+		client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+		ws, err := client.CreateWorkspace(&tagmanager.Workspace{Name: "one-too-many"})
+		server.Close()
+		assert.Nil(t, ws)
+		assert.ErrorIs(t, err, ErrWorkspaceLimitReached)
+	}
+}
+
+func TestCreateWorkspaceOtherConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error": {"code": 409, "message": "Found entity with duplicate name."}}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+	ws, err := client.CreateWorkspace(&tagmanager.Workspace{Name: "hotfix"})
+	assert.Nil(t, ws)
+	assert.NotErrorIs(t, err, ErrWorkspaceLimitReached)
+
+	var apiErr *googleapi.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Code)
+}
+
+func TestSyncWorkspace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.True(t, strings.HasSuffix(r.URL.Path, "/accounts/1/containers/2/workspaces/3:sync"), r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"syncStatus": {"mergeConflict": true},
+			"mergeConflict": [{
+				"entityInWorkspace": {"changeStatus": "updated", "tag": {"tagId": "4", "name": "purchase"}},
+				"entityInBaseVersion": {"changeStatus": "updated", "tag": {"tagId": "4", "name": "purchase"}}
+			}]
+		}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+	resp, err := client.SyncWorkspace("3")
+	assert.NoError(t, err)
+	assert.True(t, resp.SyncStatus.MergeConflict)
+	assert.Len(t, resp.MergeConflict, 1)
+	assert.Equal(t, "purchase", resp.MergeConflict[0].EntityInWorkspace.Tag.Name)
+}
+
+func TestResolveConflict(t *testing.T) {
+	var resolved tagmanager.Entity
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.True(t, strings.HasSuffix(r.URL.Path, "/accounts/1/containers/2/workspaces/3:resolve_conflict"), r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&resolved))
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &ClientInWorkspace{
+		Client:  &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}},
+		Options: &ClientInWorkspaceOptions{WorkspaceId: "3"},
+	}
+
+	err = client.ResolveConflict(&tagmanager.Entity{ChangeStatus: "updated", Tag: &tagmanager.Tag{TagId: "4", Name: "purchase"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "updated", resolved.ChangeStatus)
+	assert.Equal(t, "purchase", resolved.Tag.Name)
+}
+
+func TestCreateVersionCompilerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.True(t, strings.HasSuffix(r.URL.Path, "/accounts/1/containers/2/workspaces/3:create_version"), r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"compilerError": true, "syncStatus": {}}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+	resp, err := client.CreateVersion("3", &tagmanager.CreateContainerVersionRequestVersionOptions{Name: "broken"})
+	assert.NoError(t, err)
+	assert.True(t, resp.CompilerError)
+	assert.Nil(t, resp.ContainerVersion)
+}
+
+func TestGetWorkspaceCompilerStatus(t *testing.T) {
+	var response string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.True(t, strings.HasSuffix(r.URL.Path, "/accounts/1/containers/2/workspaces/3:quick_preview"), r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &ClientInWorkspace{
+		Client:  &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}},
+		Options: &ClientInWorkspaceOptions{WorkspaceId: "3"},
+	}
+
+	response = `{"containerVersion": {"containerVersionId": "0"}, "syncStatus": {}}`
+	status, err := client.GetWorkspaceCompilerStatus()
+	assert.NoError(t, err)
+	assert.True(t, status.Compiles())
+
+	response = `{"compilerError": true, "syncStatus": {}}`
+	status, err = client.GetWorkspaceCompilerStatus()
+	assert.NoError(t, err)
+	assert.True(t, status.CompilerError)
+	assert.False(t, status.Compiles())
+
+	response = `{"syncStatus": {"mergeConflict": true}}`
+	status, err = client.GetWorkspaceCompilerStatus()
+	assert.NoError(t, err)
+	assert.True(t, status.MergeConflict)
+	assert.False(t, status.Compiles())
+}
+
+func TestValidateAccessPermissionDenied(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error": {"code": 403, "message": "The caller does not have permission"}}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+	err = client.ValidateAccess()
+	assert.ErrorContains(t, err, "no access to container 2 of account 1")
+	assert.ErrorContains(t, err, "The caller does not have permission")
+}
+
+func TestValidateAccessInsufficientScopes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error": {"code": 403, "message": "Request had insufficient authentication scopes.",
+			"errors": [{"reason": "insufficientPermissions", "message": "Insufficient Permission"}],
+			"details": [{"@type": "type.googleapis.com/google.rpc.ErrorInfo", "reason": "ACCESS_TOKEN_SCOPE_INSUFFICIENT"}]}}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+	err = client.ValidateAccess()
+	assert.ErrorIs(t, err, ErrInsufficientScopes)
+	assert.ErrorContains(t, err, "tagmanager.edit.containers")
+	assert.NotContains(t, err.Error(), "no access to container")
+}
+
+func TestValidateAccess(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"containerId": "2"}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+	assert.NoError(t, client.ValidateAccess())
+	assert.Equal(t, []string{"GET /tagmanager/v2/accounts/1/containers/2"}, requests)
+}
+
+// newConflictTestServer fails the first update with a fingerprint conflict and
+// records the method and fingerprint of every request.
+func newConflictTestServer(requests *[]string) *httptest.Server {
+	conflicted := false
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.Method+" "+r.URL.Query().Get("fingerprint"))
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"tagId": "5", "fingerprint": "2"}`))
+		case !conflicted:
+			conflicted = true
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error": {"code": 409, "message": "fingerprint mismatch"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"tagId": "5", "name": "updated", "fingerprint": "3"}`))
+		}
+	}))
+}
+
+func TestUpdateTagResolvesConflict(t *testing.T) {
+	var requests []string
+	server := newConflictTestServer(&requests)
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2", AutoResolveConflicts: true}}
+
+	tag, err := client.UpdateTag("3", "5", &tagmanager.Tag{Name: "updated"})
+	assert.NoError(t, err)
+	assert.Equal(t, "updated", tag.Name)
+	assert.Equal(t, []string{"PUT ", "GET ", "PUT 2"}, requests)
+}
+
+func TestUpdateTagConflictWithoutAutoResolve(t *testing.T) {
+	var requests []string
+	server := newConflictTestServer(&requests)
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+	_, err = client.UpdateTag("3", "5", &tagmanager.Tag{Name: "updated"})
+	assert.Error(t, err)
+	assert.Len(t, requests, 1)
+}
+
+func TestListEnvironments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tagmanager/v2/accounts/1/containers/2/environments", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"environment": [
+			{"environmentId": "1", "name": "Live", "type": "live"},
+			{"environmentId": "2", "name": "Latest", "type": "latest"},
+			{"environmentId": "5", "name": "Staging", "type": "user", "url": "https://staging.example.com"}
+		]}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+	environments, err := client.ListEnvironments()
+	assert.NoError(t, err)
+	assert.Len(t, environments, 3)
+	assert.Equal(t, "5", environments[2].EnvironmentId)
+	assert.Equal(t, "https://staging.example.com", environments[2].Url)
+}
+
+func TestContainerFeatures(t *testing.T) {
+	var reads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reads++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"containerId": "2", "features": {"supportTags": true, "supportClients": false}}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}, container: &containerCache{}}
+
+	features, err := client.ContainerFeatures()
+	assert.NoError(t, err)
+	assert.True(t, features.SupportTags)
+	assert.False(t, features.SupportClients)
+
+	_, err = client.WithRetryLimit(0).ContainerFeatures()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reads)
+}
+
+func TestContainerPublicId(t *testing.T) {
+	var reads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reads++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"containerId": "2", "publicId": "GTM-ABC123", "features": {"supportTags": true}}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}, container: &containerCache{}}
+
+	publicId, err := client.ContainerPublicId()
+	assert.NoError(t, err)
+	assert.Equal(t, "GTM-ABC123", publicId)
+
+	_, err = client.WithRetryLimit(0).ContainerFeatures()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reads)
+}
+
+func TestBackoffCapsRetryAfter(t *testing.T) {
+	client := &Client{Options: &ClientOptions{MaxRetryAfter: 30 * time.Second}}
+	rateLimited := func(retryAfter string) *googleapi.Error {
+		err := &googleapi.Error{Code: 429, Header: http.Header{}}
+		if retryAfter != "" {
+			err.Header.Set("Retry-After", retryAfter)
+		}
+		return err
+	}
+
+	assert.Equal(t, 30*time.Second, client.backoff(rateLimited("99999"), time.Second))
+	assert.Equal(t, 7*time.Second, client.backoff(rateLimited("7"), time.Second))
+	assert.Equal(t, time.Second, client.backoff(rateLimited(""), time.Second))
+	assert.Equal(t, time.Second, client.backoff(rateLimited("soon"), time.Second))
+
+	client.Options.MaxRetryAfter = 0
+	assert.Equal(t, DefaultMaxRetryAfter, client.backoff(rateLimited("99999"), time.Second))
+}
+
+func TestRetryAfterCeiling(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if requests == 1 {
+			w.Header().Set("Retry-After", "99999")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error": {"code": 429, "message": "Too many requests"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"containerId": "2"}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2", RetryLimit: 1, MaxRetryAfter: 10 * time.Millisecond}}
+
+	start := time.Now()
+	_, err = client.Container()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestGetWithFieldsMask(t *testing.T) {
+	var fields []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields = append(fields, r.URL.Query().Get("fields"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name": "entity"}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+	_, err = client.Tag("3", "4", "tagId,name")
+	assert.NoError(t, err)
+	_, err = client.Trigger("3", "5", "triggerId", "name")
+	assert.NoError(t, err)
+	_, err = client.Variable("3", "6")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"tagId,name", "triggerId,name", ""}, fields)
+}
+
+// Test that fields added to API responses after the client library was generated are
+// ignored instead of failing the decoding of the entity
+func TestUnknownResponseFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"tagId": "4",
+			"name": "tag",
+			"type": "html",
+			"consentMode": {"required": true, "signals": ["ad_storage"]},
+			"parameter": [{"key": "html", "type": "template", "value": "<p></p>", "origin": "template"}],
+			"revision": 7
+		}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	client := &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}}
+
+	tag, err := client.Tag("3", "4")
+	assert.NoError(t, err)
+	assert.Equal(t, "4", tag.TagId)
+	assert.Equal(t, "<p></p>", tag.Parameter[0].Value)
+}
@@ -1,7 +1,11 @@
 package api
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"testing"
 	"time"
 )
 
@@ -43,3 +47,27 @@ var (
 func testName(prefix string) string {
 	return prefix + "-" + time.Now().Format("20060102-150405")
 }
+
+// TestCoordinatorOnlyInTests checks that the coordinator, which sleeps between API
+// calls, is never referenced by production code, which relies on the RateLimiter.
+func TestCoordinatorOnlyInTests(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		source, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if strings.Contains(string(source), "TestCoordinator") {
+			t.Fatalf("%s references the test coordinator", file)
+		}
+	}
+}
@@ -0,0 +1,407 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// WorkspaceContents are the tags, triggers and variables of a workspace. Entities are
+// matched by name, which GTM keeps unique within a workspace.
+type WorkspaceContents struct {
+	Tags      []*tagmanager.Tag
+	Triggers  []*tagmanager.Trigger
+	Variables []*tagmanager.Variable
+}
+
+// WorkspaceDiff are the changes turning the current contents of a workspace into the
+// desired ones. Update holds the desired entities with the id of the entity they
+// replace, and Current the versions they replace in the same order, which is what a
+// rollback restores. Delete holds the current entities that are not desired.
+type WorkspaceDiff struct {
+	Create  WorkspaceContents
+	Update  WorkspaceContents
+	Current WorkspaceContents
+	Delete  WorkspaceContents
+}
+
+// Empty reports whether the diff has no changes.
+func (d *WorkspaceDiff) Empty() bool {
+	return len(d.Create.Tags)+len(d.Create.Triggers)+len(d.Create.Variables)+
+		len(d.Update.Tags)+len(d.Update.Triggers)+len(d.Update.Variables)+
+		len(d.Delete.Tags)+len(d.Delete.Triggers)+len(d.Delete.Variables) == 0
+}
+
+// WorkspaceContents lists the tags, triggers and variables of the workspace.
+func (c *ClientInWorkspace) WorkspaceContents() (*WorkspaceContents, error) {
+	tags, err := c.ListTags()
+	if err != nil {
+		return nil, err
+	}
+
+	triggers, err := c.ListTriggers()
+	if err != nil {
+		return nil, err
+	}
+
+	variables, err := c.ListVariables()
+	if err != nil {
+		return nil, err
+	}
+
+	return &WorkspaceContents{Tags: tags, Triggers: triggers, Variables: variables}, nil
+}
+
+// DiffWorkspaceContents computes the changes turning current into desired. A desired
+// entity without a current entity of the same name is created, one that differs from
+// it is updated, and current entities without a desired one are deleted. References
+// between entities, e.g. the firing triggers of a tag, are compared as they are, so
+// they have to hold the ids of existing entities.
+func DiffWorkspaceContents(current *WorkspaceContents, desired *WorkspaceContents) *WorkspaceDiff {
+	var diff = &WorkspaceDiff{}
+
+	var tags = map[string]*tagmanager.Tag{}
+	for _, tag := range current.Tags {
+		tags[tag.Name] = tag
+	}
+	for _, tag := range desired.Tags {
+		existing, ok := tags[tag.Name]
+		delete(tags, tag.Name)
+
+		if !ok {
+			diff.Create.Tags = append(diff.Create.Tags, tag)
+		} else if !sameEntity(withoutTagIdentity(tag), withoutTagIdentity(existing)) {
+			update := *tag
+			update.TagId = existing.TagId
+			diff.Update.Tags = append(diff.Update.Tags, &update)
+			diff.Current.Tags = append(diff.Current.Tags, existing)
+		}
+	}
+	for _, tag := range current.Tags {
+		if _, ok := tags[tag.Name]; ok {
+			diff.Delete.Tags = append(diff.Delete.Tags, tag)
+		}
+	}
+
+	var triggers = map[string]*tagmanager.Trigger{}
+	for _, trigger := range current.Triggers {
+		triggers[trigger.Name] = trigger
+	}
+	for _, trigger := range desired.Triggers {
+		existing, ok := triggers[trigger.Name]
+		delete(triggers, trigger.Name)
+
+		if !ok {
+			diff.Create.Triggers = append(diff.Create.Triggers, trigger)
+		} else if !sameEntity(withoutTriggerIdentity(trigger), withoutTriggerIdentity(existing)) {
+			update := *trigger
+			update.TriggerId = existing.TriggerId
+			diff.Update.Triggers = append(diff.Update.Triggers, &update)
+			diff.Current.Triggers = append(diff.Current.Triggers, existing)
+		}
+	}
+	for _, trigger := range current.Triggers {
+		if _, ok := triggers[trigger.Name]; ok {
+			diff.Delete.Triggers = append(diff.Delete.Triggers, trigger)
+		}
+	}
+
+	var variables = map[string]*tagmanager.Variable{}
+	for _, variable := range current.Variables {
+		variables[variable.Name] = variable
+	}
+	for _, variable := range desired.Variables {
+		existing, ok := variables[variable.Name]
+		delete(variables, variable.Name)
+
+		if !ok {
+			diff.Create.Variables = append(diff.Create.Variables, variable)
+		} else if !sameEntity(withoutVariableIdentity(variable), withoutVariableIdentity(existing)) {
+			update := *variable
+			update.VariableId = existing.VariableId
+			diff.Update.Variables = append(diff.Update.Variables, &update)
+			diff.Current.Variables = append(diff.Current.Variables, existing)
+		}
+	}
+	for _, variable := range current.Variables {
+		if _, ok := variables[variable.Name]; ok {
+			diff.Delete.Variables = append(diff.Delete.Variables, variable)
+		}
+	}
+
+	return diff
+}
+
+// withoutTagIdentity returns a copy of the tag without the fields GTM assigns, which
+// are not part of the desired contents.
+func withoutTagIdentity(tag *tagmanager.Tag) *tagmanager.Tag {
+	t := *tag
+	t.AccountId, t.ContainerId, t.WorkspaceId, t.TagId = "", "", "", ""
+	t.Fingerprint, t.Path, t.TagManagerUrl, t.ParentFolderId = "", "", "", ""
+	return &t
+}
+
+// withoutTriggerIdentity returns a copy of the trigger like withoutTagIdentity.
+func withoutTriggerIdentity(trigger *tagmanager.Trigger) *tagmanager.Trigger {
+	t := *trigger
+	t.AccountId, t.ContainerId, t.WorkspaceId, t.TriggerId = "", "", "", ""
+	t.Fingerprint, t.Path, t.TagManagerUrl, t.ParentFolderId = "", "", "", ""
+	return &t
+}
+
+// withoutVariableIdentity returns a copy of the variable like withoutTagIdentity.
+func withoutVariableIdentity(variable *tagmanager.Variable) *tagmanager.Variable {
+	v := *variable
+	v.AccountId, v.ContainerId, v.WorkspaceId, v.VariableId = "", "", "", ""
+	v.Fingerprint, v.Path, v.TagManagerUrl, v.ParentFolderId = "", "", "", ""
+	return &v
+}
+
+// sameEntity compares two entities by their JSON encoding, which omits empty fields
+// so that a nil and an empty list compare equal.
+func sameEntity(a any, b any) bool {
+	aJson, aErr := json.Marshal(a)
+	bJson, bErr := json.Marshal(b)
+
+	return aErr == nil && bErr == nil && string(aJson) == string(bJson)
+}
+
+// workspaceJournal records the changes applied by ApplyWorkspaceDiff together with the
+// way to undo them.
+type workspaceJournal struct {
+	steps []string
+	undo  []func() error
+}
+
+func (j *workspaceJournal) record(step string, undo func() error) {
+	j.steps = append(j.steps, step)
+	j.undo = append(j.undo, undo)
+}
+
+// rollback undoes the recorded changes in reverse order and returns a log of what was
+// rolled back. A change that cannot be undone does not stop the rollback of the others.
+func (j *workspaceJournal) rollback() (string, error) {
+	var log []string
+	var errs []error
+
+	for i := len(j.undo) - 1; i >= 0; i-- {
+		if j.undo[i] == nil {
+			log = append(log, "cannot undo "+j.steps[i])
+			continue
+		}
+
+		if err := j.undo[i](); err != nil {
+			log = append(log, "failed to undo "+j.steps[i])
+			errs = append(errs, fmt.Errorf("undo %s: %w", j.steps[i], err))
+			continue
+		}
+
+		log = append(log, "undid "+j.steps[i])
+	}
+
+	return strings.Join(log, "; "), errors.Join(errs...)
+}
+
+// ApplyWorkspaceDiff applies the changes of diff to the workspace. Variables are
+// created first and tags last, since tags reference triggers and triggers may
+// reference variables, and deletes go the other way round after the updates. GTM has
+// no transactions, so when a change fails the ones applied before are rolled back on a
+// best effort basis: created entities are deleted and updated ones restored. Deleted
+// entities cannot be restored with their id and are kept deleted. The error describes
+// what was rolled back.
+func (c *ClientInWorkspace) ApplyWorkspaceDiff(diff *WorkspaceDiff) error {
+	var journal workspaceJournal
+
+	err := c.applyWorkspaceDiff(diff, &journal)
+	if err == nil {
+		return nil
+	}
+
+	log, rollbackErr := journal.rollback()
+	if log == "" {
+		return err
+	}
+
+	return errors.Join(fmt.Errorf("%w (rollback: %s)", err, log), rollbackErr)
+}
+
+func (c *ClientInWorkspace) applyWorkspaceDiff(diff *WorkspaceDiff, journal *workspaceJournal) error {
+	for _, variable := range diff.Create.Variables {
+		created, err := c.CreateVariable(variable)
+		if err != nil {
+			return fmt.Errorf("create variable %q: %w", variable.Name, err)
+		}
+		journal.record(fmt.Sprintf("create variable %q", variable.Name), func() error { return c.DeleteVariable(created.VariableId) })
+	}
+
+	for _, trigger := range diff.Create.Triggers {
+		created, err := c.CreateTrigger(trigger)
+		if err != nil {
+			return fmt.Errorf("create trigger %q: %w", trigger.Name, err)
+		}
+		journal.record(fmt.Sprintf("create trigger %q", trigger.Name), func() error { return c.DeleteTrigger(created.TriggerId) })
+	}
+
+	for _, tag := range diff.Create.Tags {
+		created, err := c.CreateTag(tag)
+		if err != nil {
+			return fmt.Errorf("create tag %q: %w", tag.Name, err)
+		}
+		journal.record(fmt.Sprintf("create tag %q", tag.Name), func() error { return c.DeleteTag(created.TagId) })
+	}
+
+	for i, variable := range diff.Update.Variables {
+		if _, err := c.UpdateVariable(variable.VariableId, variable); err != nil {
+			return fmt.Errorf("update variable %q: %w", variable.Name, err)
+		}
+		previous := diff.Current.Variables[i]
+		journal.record(fmt.Sprintf("update variable %q", variable.Name), func() error {
+			_, err := c.UpdateVariable(previous.VariableId, withoutVariableIdentity(previous))
+			return err
+		})
+	}
+
+	for i, trigger := range diff.Update.Triggers {
+		if _, err := c.UpdateTrigger(trigger.TriggerId, trigger); err != nil {
+			return fmt.Errorf("update trigger %q: %w", trigger.Name, err)
+		}
+		previous := diff.Current.Triggers[i]
+		journal.record(fmt.Sprintf("update trigger %q", trigger.Name), func() error {
+			_, err := c.UpdateTrigger(previous.TriggerId, withoutTriggerIdentity(previous))
+			return err
+		})
+	}
+
+	for i, tag := range diff.Update.Tags {
+		if _, err := c.UpdateTag(tag.TagId, tag); err != nil {
+			return fmt.Errorf("update tag %q: %w", tag.Name, err)
+		}
+		previous := diff.Current.Tags[i]
+		journal.record(fmt.Sprintf("update tag %q", tag.Name), func() error {
+			_, err := c.UpdateTag(previous.TagId, withoutTagIdentity(previous))
+			return err
+		})
+	}
+
+	for _, tag := range diff.Delete.Tags {
+		if err := c.DeleteTag(tag.TagId); err != nil && err != ErrNotExist {
+			return fmt.Errorf("delete tag %q: %w", tag.Name, err)
+		}
+		journal.record(fmt.Sprintf("delete tag %q", tag.Name), nil)
+	}
+
+	for _, trigger := range diff.Delete.Triggers {
+		if err := c.DeleteTrigger(trigger.TriggerId); err != nil && err != ErrNotExist {
+			return fmt.Errorf("delete trigger %q: %w", trigger.Name, err)
+		}
+		journal.record(fmt.Sprintf("delete trigger %q", trigger.Name), nil)
+	}
+
+	for _, variable := range diff.Delete.Variables {
+		if err := c.DeleteVariable(variable.VariableId); err != nil && err != ErrNotExist {
+			return fmt.Errorf("delete variable %q: %w", variable.Name, err)
+		}
+		journal.record(fmt.Sprintf("delete variable %q", variable.Name), nil)
+	}
+
+	return nil
+}
+
+// ApplyWorkspaceContents makes the tags, triggers and variables of the workspace match
+// desired like ApplyWorkspaceDiff and returns the diff that was applied.
+func (c *ClientInWorkspace) ApplyWorkspaceContents(desired *WorkspaceContents) (*WorkspaceDiff, error) {
+	current, err := c.WorkspaceContents()
+	if err != nil {
+		return nil, err
+	}
+
+	diff := DiffWorkspaceContents(current, desired)
+	if diff.Empty() {
+		return diff, nil
+	}
+
+	return diff, c.ApplyWorkspaceDiff(diff)
+}
+
+// containerExport is the JSON file GTM exports a container version to.
+type containerExport struct {
+	ExportFormatVersion int                          `json:"exportFormatVersion"`
+	ContainerVersion    *tagmanager.ContainerVersion `json:"containerVersion"`
+}
+
+// ParseContainerExport reads the tags, triggers and variables of a GTM container
+// export. Their references to each other hold the ids of the exported container.
+func ParseContainerExport(data []byte) (*WorkspaceContents, error) {
+	var export containerExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("parse container export: %w", err)
+	}
+
+	if export.ContainerVersion == nil {
+		return nil, errors.New("parse container export: no containerVersion, expected a file exported from GTM")
+	}
+
+	return &WorkspaceContents{
+		Tags:      export.ContainerVersion.Tag,
+		Triggers:  export.ContainerVersion.Trigger,
+		Variables: export.ContainerVersion.Variable,
+	}, nil
+}
+
+// withWorkspaceTriggerIds returns a copy of the exported contents whose tags
+// reference the triggers of the workspace with the same name as the exported ones
+// they reference. References to triggers the workspace lacks are kept as exported.
+func withWorkspaceTriggerIds(exported *WorkspaceContents, current *WorkspaceContents) *WorkspaceContents {
+	var currentIds = map[string]string{}
+	for _, trigger := range current.Triggers {
+		currentIds[trigger.Name] = trigger.TriggerId
+	}
+
+	var ids = map[string]string{}
+	for _, trigger := range exported.Triggers {
+		if id, ok := currentIds[trigger.Name]; ok {
+			ids[trigger.TriggerId] = id
+		}
+	}
+
+	var remap = func(triggerIds []string) []string {
+		var remapped []string
+		for _, id := range triggerIds {
+			if currentId, ok := ids[id]; ok {
+				id = currentId
+			}
+			remapped = append(remapped, id)
+		}
+		return remapped
+	}
+
+	var contents = &WorkspaceContents{Triggers: exported.Triggers, Variables: exported.Variables}
+	for _, tag := range exported.Tags {
+		t := *tag
+		t.FiringTriggerId = remap(tag.FiringTriggerId)
+		t.BlockingTriggerId = remap(tag.BlockingTriggerId)
+		contents.Tags = append(contents.Tags, &t)
+	}
+
+	return contents
+}
+
+// DiffContainerExport computes the changes that would make the workspace match a GTM
+// container export, without applying them. The triggers referenced by the exported
+// tags are matched by name with the ones of the workspace.
+func (c *ClientInWorkspace) DiffContainerExport(data []byte) (*WorkspaceDiff, error) {
+	exported, err := ParseContainerExport(data)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := c.WorkspaceContents()
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffWorkspaceContents(current, withWorkspaceTriggerIds(exported, current)), nil
+}
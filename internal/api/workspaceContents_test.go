@@ -0,0 +1,220 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// fakeWorkspace serves the tags, triggers and variables of workspace 3 from memory.
+// Creating an entity named failOn fails.
+type fakeWorkspace struct {
+	mutex    sync.Mutex
+	entities map[string]map[string]map[string]any
+	nextId   int
+	failOn   string
+}
+
+// fakeWorkspaceEntity maps a collection to the singular name GTM uses for its ids and lists.
+var fakeWorkspaceEntity = map[string]string{"tags": "tag", "triggers": "trigger", "variables": "variable"}
+
+func newFakeWorkspace() *fakeWorkspace {
+	return &fakeWorkspace{
+		entities: map[string]map[string]map[string]any{"tags": {}, "triggers": {}, "variables": {}},
+		nextId:   100,
+	}
+}
+
+func (f *fakeWorkspace) add(collection string, entity map[string]any) string {
+	f.nextId++
+	id := strconv.Itoa(f.nextId)
+	entity[fakeWorkspaceEntity[collection]+"Id"] = id
+	f.entities[collection][id] = entity
+	return id
+}
+
+// names returns the sorted names of the entities of a collection.
+func (f *fakeWorkspace) names(collection string) []string {
+	var names []string
+	for _, entity := range f.entities[collection] {
+		names = append(names, entity["name"].(string))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (f *fakeWorkspace) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	segments := strings.Split(r.URL.Path[strings.Index(r.URL.Path, "/workspaces/3/")+len("/workspaces/3/"):], "/")
+	collection := segments[0]
+	singular := fakeWorkspaceEntity[collection]
+
+	var entity map[string]any
+	if r.Body != nil {
+		_ = json.NewDecoder(r.Body).Decode(&entity)
+	}
+
+	switch {
+	case r.Method == http.MethodGet && len(segments) == 1:
+		var list []map[string]any
+		for _, e := range f.entities[collection] {
+			list = append(list, e)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{singular: list})
+	case r.Method == http.MethodPost && entity["name"] == f.failOn:
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"code": 400, "message": "Invalid entity"}}`))
+	case r.Method == http.MethodPost:
+		f.add(collection, entity)
+		_ = json.NewEncoder(w).Encode(entity)
+	case r.Method == http.MethodPut:
+		entity[singular+"Id"] = segments[1]
+		f.entities[collection][segments[1]] = entity
+		_ = json.NewEncoder(w).Encode(entity)
+	case r.Method == http.MethodDelete:
+		delete(f.entities[collection], segments[1])
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func testFakeWorkspaceClient(t *testing.T, workspace *fakeWorkspace) *ClientInWorkspace {
+	server := httptest.NewServer(workspace)
+	t.Cleanup(server.Close)
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	return &ClientInWorkspace{
+		Client:  &Client{Service: srv, Options: &ClientOptions{AccountId: "1", ContainerId: "2"}},
+		Options: &ClientInWorkspaceOptions{WorkspaceId: "3"},
+	}
+}
+
+func TestApplyWorkspaceContents(t *testing.T) {
+	workspace := newFakeWorkspace()
+	pageview := workspace.add("triggers", map[string]any{"name": "pageview", "type": "pageview"})
+	workspace.add("triggers", map[string]any{"name": "obsolete", "type": "click"})
+	workspace.add("tags", map[string]any{"name": "unchanged", "type": "html", "firingTriggerId": []string{pageview}, "fingerprint": "1"})
+	workspace.add("tags", map[string]any{"name": "changed", "type": "html", "notes": "old"})
+	workspace.add("variables", map[string]any{"name": "obsolete", "type": "c"})
+
+	client := testFakeWorkspaceClient(t, workspace)
+
+	desired := &WorkspaceContents{
+		Tags: []*tagmanager.Tag{
+			{Name: "unchanged", Type: "html", FiringTriggerId: []string{pageview}},
+			{Name: "changed", Type: "html", Notes: "new"},
+			{Name: "added", Type: "html"},
+		},
+		Triggers:  []*tagmanager.Trigger{{Name: "pageview", Type: "pageview"}},
+		Variables: []*tagmanager.Variable{{Name: "added", Type: "v"}},
+	}
+
+	diff, err := client.ApplyWorkspaceContents(desired)
+	assert.NoError(t, err)
+
+	assert.Len(t, diff.Create.Tags, 1)
+	assert.Equal(t, "added", diff.Create.Tags[0].Name)
+	assert.Len(t, diff.Update.Tags, 1)
+	assert.Equal(t, "changed", diff.Update.Tags[0].Name)
+	assert.Equal(t, "old", diff.Current.Tags[0].Notes)
+	assert.Empty(t, diff.Delete.Tags)
+	assert.Empty(t, diff.Create.Triggers)
+	assert.Empty(t, diff.Update.Triggers)
+	assert.Len(t, diff.Delete.Triggers, 1)
+	assert.Equal(t, "obsolete", diff.Delete.Triggers[0].Name)
+	assert.Len(t, diff.Create.Variables, 1)
+	assert.Len(t, diff.Delete.Variables, 1)
+
+	assert.Equal(t, []string{"added", "changed", "unchanged"}, workspace.names("tags"))
+	assert.Equal(t, []string{"pageview"}, workspace.names("triggers"))
+	assert.Equal(t, []string{"added"}, workspace.names("variables"))
+
+	diff, err = client.ApplyWorkspaceContents(desired)
+	assert.NoError(t, err)
+	assert.True(t, diff.Empty())
+}
+
+func TestApplyWorkspaceDiffRollback(t *testing.T) {
+	workspace := newFakeWorkspace()
+	workspace.add("tags", map[string]any{"name": "changed", "type": "html", "notes": "old"})
+	workspace.failOn = "invalid"
+
+	client := testFakeWorkspaceClient(t, workspace)
+
+	_, err := client.ApplyWorkspaceContents(&WorkspaceContents{
+		Tags: []*tagmanager.Tag{
+			{Name: "changed", Type: "html", Notes: "new"},
+			{Name: "invalid", Type: "html"},
+		},
+		Triggers: []*tagmanager.Trigger{{Name: "added", Type: "pageview"}},
+	})
+
+	assert.ErrorContains(t, err, `create tag "invalid"`)
+	assert.ErrorContains(t, err, `undid create trigger "added"`)
+	assert.Empty(t, workspace.names("triggers"))
+	assert.Equal(t, []string{"changed"}, workspace.names("tags"))
+}
+
+func TestDiffContainerExport(t *testing.T) {
+	workspace := newFakeWorkspace()
+	pageview := workspace.add("triggers", map[string]any{"name": "pageview", "type": "pageview"})
+	workspace.add("triggers", map[string]any{"name": "obsolete", "type": "click"})
+	workspace.add("tags", map[string]any{"name": "unchanged", "type": "html", "firingTriggerId": []string{pageview}})
+	workspace.add("tags", map[string]any{"name": "changed", "type": "html", "notes": "old"})
+	workspace.add("variables", map[string]any{"name": "obsolete", "type": "c"})
+
+	client := testFakeWorkspaceClient(t, workspace)
+
+	// The export references its own id of the pageview trigger
+	export := `{
+		"exportFormatVersion": 2,
+		"containerVersion": {
+			"accountId": "9",
+			"containerId": "8",
+			"tag": [
+				{"accountId": "9", "containerId": "8", "tagId": "1", "name": "unchanged", "type": "html", "firingTriggerId": ["7"], "fingerprint": "1"},
+				{"accountId": "9", "containerId": "8", "tagId": "2", "name": "changed", "type": "html", "notes": "new"},
+				{"accountId": "9", "containerId": "8", "tagId": "3", "name": "added", "type": "html"}
+			],
+			"trigger": [{"accountId": "9", "containerId": "8", "triggerId": "7", "name": "pageview", "type": "pageview"}],
+			"variable": [{"accountId": "9", "containerId": "8", "variableId": "5", "name": "added", "type": "v"}]
+		}
+	}`
+
+	diff, err := client.DiffContainerExport([]byte(export))
+	assert.NoError(t, err)
+
+	assert.Len(t, diff.Create.Tags, 1)
+	assert.Equal(t, "added", diff.Create.Tags[0].Name)
+	assert.Len(t, diff.Update.Tags, 1)
+	assert.Equal(t, "changed", diff.Update.Tags[0].Name)
+	assert.Empty(t, diff.Delete.Tags)
+	assert.Empty(t, diff.Create.Triggers)
+	assert.Empty(t, diff.Update.Triggers)
+	assert.Len(t, diff.Delete.Triggers, 1)
+	assert.Equal(t, "obsolete", diff.Delete.Triggers[0].Name)
+	assert.Len(t, diff.Create.Variables, 1)
+	assert.Len(t, diff.Delete.Variables, 1)
+
+	// A dry run leaves the workspace as it was
+	assert.Equal(t, []string{"changed", "unchanged"}, workspace.names("tags"))
+	assert.Equal(t, []string{"obsolete", "pageview"}, workspace.names("triggers"))
+
+	_, err = client.DiffContainerExport([]byte(`{"tag": []}`))
+	assert.ErrorContains(t, err, "no containerVersion")
+}
@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ resource.Resource                = &builtInVariableResource{}
+	_ resource.ResourceWithConfigure   = &builtInVariableResource{}
+	_ resource.ResourceWithImportState = &builtInVariableResource{}
+)
+
+type builtInVariableResource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewBuiltInVariableResource() resource.Resource {
+	return &builtInVariableResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *builtInVariableResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(r.client, &resp.Diagnostics)
+}
+
+// Metadata returns the resource type name.
+func (r *builtInVariableResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_built_in_variable"
+}
+
+var builtInVariableResourceSchemaAttributes = map[string]schema.Attribute{
+	"type": schema.StringAttribute{
+		Description: "The type of the built-in variable, e.g. pageUrl or clickText.",
+		Required:    true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	},
+	"id": schema.StringAttribute{
+		Description: "The ID of the built-in variable, which is its type.",
+		Computed:    true,
+	},
+	"name": schema.StringAttribute{
+		Description: "The name of the built-in variable.",
+		Computed:    true,
+	},
+}
+
+// Schema defines the schema for the resource.
+func (r *builtInVariableResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{Attributes: builtInVariableResourceSchemaAttributes}
+}
+
+type resourceBuiltInVariableModel struct {
+	Type types.String `tfsdk:"type"`
+	Id   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+}
+
+func toResourceBuiltInVariable(variable *tagmanager.BuiltInVariable) resourceBuiltInVariableModel {
+	return resourceBuiltInVariableModel{
+		Type: types.StringValue(variable.Type),
+		Id:   types.StringValue(variable.Type),
+		Name: nullableStringValue(variable.Name),
+	}
+}
+
+// findBuiltInVariable returns the enabled built-in variable of the given type, or nil
+// when it is not enabled.
+func findBuiltInVariable(variables []*tagmanager.BuiltInVariable, variableType string) *tagmanager.BuiltInVariable {
+	for _, variable := range variables {
+		if variable.Type == variableType {
+			return variable
+		}
+	}
+
+	return nil
+}
+
+// Create enables the built-in variable and sets the initial Terraform state. Built-in
+// variables are enabled rather than created, so one that is already enabled is adopted.
+func (r *builtInVariableResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan resourceBuiltInVariableModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	variables, err := r.client.ListBuiltInVariables()
+	if err != nil {
+		resp.Diagnostics.AddError("Error Enabling Built-In Variable", err.Error())
+		return
+	}
+
+	variable := findBuiltInVariable(variables, plan.Type.ValueString())
+	if variable == nil {
+		variable, err = r.client.EnableBuiltInVariable(plan.Type.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error Enabling Built-In Variable", err.Error())
+			return
+		}
+	}
+
+	var resource = toResourceBuiltInVariable(variable)
+
+	diags = resp.State.Set(ctx, &resource)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data. A built-in variable that
+// was disabled outside Terraform is removed from the state.
+func (r *builtInVariableResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state resourceBuiltInVariableModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	variables, err := r.client.ListBuiltInVariables()
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Built-In Variable", err.Error())
+		return
+	}
+
+	variable := findBuiltInVariable(variables, state.Id.ValueString())
+	if variable == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	var resource = toResourceBuiltInVariable(variable)
+
+	diags = resp.State.Set(ctx, &resource)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update is never called since every attribute requires replacement.
+func (r *builtInVariableResource) Update(_ context.Context, _ resource.UpdateRequest, _ *resource.UpdateResponse) {
+}
+
+// Delete disables the built-in variable and removes the Terraform state on success.
+func (r *builtInVariableResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state resourceBuiltInVariableModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DisableBuiltInVariable(state.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error Disabling Built-In Variable", err.Error())
+		return
+	}
+}
+
+func (r *builtInVariableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"terraform-provider-google-tag-manager/internal/api"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that enabling a built-in variable that is already enabled succeeds
+func TestAccBuiltInVariableResource_alreadyEnabled(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					client := testAccBuiltInVariableClient(t)
+					if _, err := client.EnableBuiltInVariable("clickText"); err != nil {
+						t.Fatalf("Failed to enable built-in variable: %v", err)
+					}
+				},
+				Config: testAccBuiltInVariableResourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_built_in_variable.click_text", "id", "clickText"),
+					resource.TestCheckResourceAttrSet("gtm_built_in_variable.click_text", "name"),
+				),
+			},
+		},
+	})
+}
+
+// Test that a built-in variable disabled outside Terraform is enabled again
+func TestAccBuiltInVariableResource_externallyDisabled(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBuiltInVariableResourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_built_in_variable.click_text", "id", "clickText"),
+				),
+			},
+			{
+				PreConfig: func() {
+					client := testAccBuiltInVariableClient(t)
+					if err := client.DisableBuiltInVariable("clickText"); err != nil {
+						t.Fatalf("Failed to disable built-in variable: %v", err)
+					}
+				},
+				Config:             testAccBuiltInVariableResourceConfig(),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccBuiltInVariableResourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_built_in_variable.click_text", "id", "clickText"),
+				),
+			},
+		},
+	})
+}
+
+// Test that built-in variables are looked up by type
+func TestFindBuiltInVariable(t *testing.T) {
+	variables := []*tagmanager.BuiltInVariable{
+		{Type: "pageUrl", Name: "Page URL"},
+		{Type: "clickText", Name: "Click Text"},
+	}
+
+	if v := findBuiltInVariable(variables, "clickText"); v == nil || v.Name != "Click Text" {
+		t.Fatalf("expected to find clickText, got %v", v)
+	}
+
+	if v := findBuiltInVariable(variables, "formId"); v != nil {
+		t.Fatalf("expected formId not to be enabled, got %v", v)
+	}
+}
+
+func testAccBuiltInVariableClient(t *testing.T) *api.ClientInWorkspace {
+	client, err := api.NewClientInWorkspaceFromEnv()
+	if err != nil {
+		t.Fatalf("Failed to create client in workspace: %v", err)
+	}
+
+	return client
+}
+
+func testAccBuiltInVariableResourceConfig() string {
+	return testAccProviderConfig() + `
+resource "gtm_built_in_variable" "click_text" {
+  type = "clickText"
+}
+`
+}
@@ -0,0 +1,276 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-google-tag-manager/internal/api"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ resource.Resource                = &clientResource{}
+	_ resource.ResourceWithConfigure   = &clientResource{}
+	_ resource.ResourceWithImportState = &clientResource{}
+	_ resource.ResourceWithModifyPlan  = &clientResource{}
+)
+
+type clientResource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewClientResource() resource.Resource {
+	return &clientResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *clientResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(r.client, &resp.Diagnostics)
+}
+
+// Metadata returns the resource type name.
+func (r *clientResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_client"
+}
+
+var clientResourceSchemaAttributes = withWorkspaceEntityAttributes(map[string]schema.Attribute{
+	"name": schema.StringAttribute{
+		Description: "The name of the client.",
+		Required:    true,
+	},
+	"type": schema.StringAttribute{
+		Description: "The type of the client, e.g. gaaw_client for the GA4 client of a server container.",
+		Required:    true,
+		Validators:  []validator.String{serverClientTypeValidator},
+	},
+	"id": schema.StringAttribute{
+		Description: "The ID of the client.",
+		Computed:    true,
+	},
+	"client_id": schema.StringAttribute{
+		Description: "The ID GTM assigned to the client, the same as id.",
+		Computed:    true,
+	},
+	"notes": schema.StringAttribute{
+		Description: "The notes of the client.",
+		Optional:    true,
+		Validators:  notesValidators,
+	},
+	"priority": schema.Int64Attribute{
+		Description: "The priority of the client. Clients with a higher priority claim requests first. GTM defaults to 0.",
+		Optional:    true,
+	},
+	"parameter":   parameterSchema,
+	"retry_limit": retryLimitAttribute,
+})
+
+// Schema defines the schema for the resource.
+func (r *clientResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{Attributes: clientResourceSchemaAttributes}
+}
+
+type resourceClientModel struct {
+	Name      types.String             `tfsdk:"name"`
+	Type      types.String             `tfsdk:"type"`
+	Id        types.String             `tfsdk:"id"`
+	ClientId  types.String             `tfsdk:"client_id"`
+	Notes     types.String             `tfsdk:"notes"`
+	Priority  types.Int64              `tfsdk:"priority"`
+	Parameter []ResourceParameterModel `tfsdk:"parameter"`
+	workspaceEntityModel
+	RetryLimit types.Int64 `tfsdk:"retry_limit"`
+}
+
+// toApiClient always sends the priority, so that a priority of 0 or one removed from
+// the configuration resets the priority of the client to the default.
+func toApiClient(resource resourceClientModel) *tagmanager.Client {
+	return &tagmanager.Client{
+		Name:            resource.Name.ValueString(),
+		Type:            resource.Type.ValueString(),
+		Notes:           resource.Notes.ValueString(),
+		Priority:        resource.Priority.ValueInt64(),
+		Parameter:       toApiParameter(resource.Parameter),
+		ForceSendFields: []string{"Priority"},
+	}
+}
+
+// clientPriority returns the priority to keep in state for the priority of a client in
+// GTM. GTM omits the default priority of 0, which is read as null unless the current
+// priority is an explicit 0.
+func clientPriority(priority int64, current types.Int64) types.Int64 {
+	if priority == 0 && !current.IsNull() && !current.IsUnknown() && current.ValueInt64() == 0 {
+		return current
+	}
+
+	return nullableInt64Value(priority)
+}
+
+func toResourceClient(client *tagmanager.Client, inWorkspace *api.ClientInWorkspace) resourceClientModel {
+	return resourceClientModel{
+		Name:                 types.StringValue(client.Name),
+		Type:                 types.StringValue(client.Type),
+		Id:                   types.StringValue(client.ClientId),
+		ClientId:             types.StringValue(client.ClientId),
+		Notes:                nullableStringValue(client.Notes),
+		Priority:             nullableInt64Value(client.Priority),
+		Parameter:            toResourceParameter(client.Parameter),
+		workspaceEntityModel: workspaceEntityLocation(inWorkspace, "clients", client.ClientId),
+	}
+}
+
+// ModifyPlan rejects the resource at plan time when the configured container is not a
+// server container, as only server containers have clients.
+func (r *clientResource) ModifyPlan(_ context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	requireServerContainer(r.client, "gtm_client", &resp.Diagnostics)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *clientResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan resourceClientModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	inWorkspace := withRequestLog(ctx, withRetryLimit(r.client, plan.RetryLimit), "client", sensitiveValues(plan.Parameter)...)
+
+	dto := toApiClient(plan)
+	dto.Notes = withManagedByNote(managedByNote(inWorkspace.Options, "client", time.Now()), dto.Notes)
+
+	client, err := inWorkspace.CreateServerClient(dto)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating Client", redactSensitiveValues(err.Error(), plan.Parameter))
+		return
+	}
+
+	diags = recordCreatedId(ctx, &resp.State, client.ClientId)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = types.StringValue(client.ClientId)
+	plan.ClientId = types.StringValue(client.ClientId)
+	plan.workspaceEntityModel = workspaceEntityLocation(r.client, "clients", client.ClientId)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *clientResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state resourceClientModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	inWorkspace := withRequestLog(ctx, withRetryLimit(r.client, state.RetryLimit), "client", sensitiveValues(state.Parameter)...)
+
+	client, err := inWorkspace.ServerClient(state.Id.ValueString())
+	if err == api.ErrNotExist {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Reading Client", err.Error())
+		return
+	}
+
+	var resource = toResourceClient(client, inWorkspace)
+	resource.Notes = withoutManagedByNote(remoteManagedByNote(inWorkspace.Options, "client", client.Notes), client.Notes, state.Notes)
+	resource.Priority = clientPriority(client.Priority, state.Priority)
+	resource.Parameter = alignParameterOrder(resource.Parameter, state.Parameter)
+	resource.Parameter = keepEmptyValues(resource.Parameter, state.Parameter)
+	resource.Parameter = keepSensitiveValues(resource.Parameter, state.Parameter)
+	resource.RetryLimit = state.RetryLimit
+
+	diags = resp.State.Set(ctx, &resource)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *clientResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state resourceClientModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	inWorkspace := withRequestLog(ctx, withRetryLimit(r.client, plan.RetryLimit), "client", sensitiveValues(plan.Parameter)...)
+
+	dto := toApiClient(plan)
+	dto.Notes = withManagedByNote(managedByNote(inWorkspace.Options, "client", time.Now()), dto.Notes)
+
+	client, err := inWorkspace.UpdateServerClient(state.Id.ValueString(), dto)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating Client", redactSensitiveValues(err.Error(), plan.Parameter))
+		return
+	}
+
+	plan.Id = types.StringValue(client.ClientId)
+	plan.ClientId = types.StringValue(client.ClientId)
+	plan.workspaceEntityModel = workspaceEntityLocation(r.client, "clients", client.ClientId)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *clientResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state resourceClientModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	inWorkspace := withRequestLog(ctx, withRetryLimit(r.client, state.RetryLimit), "client", sensitiveValues(state.Parameter)...)
+
+	err := inWorkspace.DeleteServerClient(state.Id.ValueString())
+	if err == api.ErrNotExist {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Deleting Client", err.Error())
+		return
+	}
+}
+
+// ImportState accepts the client id or its full GTM path.
+func (r *clientResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, err := workspaceImportId(r.client, "clients", req.ID)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("id"), "Error Importing Client", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("client_id"), id)...)
+}
@@ -0,0 +1,246 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"google.golang.org/api/option"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that the priority and the parameters of a GA4 client round-trip, with a
+// default priority read as unset
+func TestClient_roundTrip(t *testing.T) {
+	planned := resourceClientModel{
+		Name:     types.StringValue("GA4"),
+		Type:     types.StringValue("gaaw_client"),
+		Notes:    types.StringNull(),
+		Priority: types.Int64Value(10),
+		Parameter: []ResourceParameterModel{
+			testParameter("activateDefaultPaths", "false"),
+			{
+				Key:  types.StringValue("customPaths"),
+				Type: types.StringValue("list"),
+				List: []ResourceParameterModel{{Type: types.StringValue("template"), Value: types.StringValue("/collect")}},
+			},
+		},
+	}
+
+	client := toApiClient(planned)
+	client.ClientId = "12"
+
+	if client.Priority != 10 || client.Parameter[1].List[0].Value != "/collect" {
+		t.Fatalf("expected the priority and custom path to be sent, got %+v", client)
+	}
+
+	remote := toResourceClient(client, testClientInWorkspace())
+
+	if remote.Id.ValueString() != "12" || remote.Path.ValueString() != "accounts/1/containers/2/workspaces/3/clients/12" {
+		t.Fatalf("unexpected id or path: %s, %s", remote.Id, remote.Path)
+	}
+
+	if !remote.Name.Equal(planned.Name) || !remote.Type.Equal(planned.Type) || !remote.Notes.Equal(planned.Notes) || !remote.Priority.Equal(planned.Priority) {
+		t.Fatalf("expected the client to round-trip, got %v", remote)
+	}
+
+	if len(remote.Parameter) != len(planned.Parameter) {
+		t.Fatalf("expected %d parameters, got %v", len(planned.Parameter), remote.Parameter)
+	}
+
+	for i := range planned.Parameter {
+		if !remote.Parameter[i].Equal(planned.Parameter[i]) {
+			t.Fatalf("expected parameter %d to round-trip, got %v", i, remote.Parameter[i])
+		}
+	}
+
+	client.Priority = 0
+	if remote = toResourceClient(client, testClientInWorkspace()); !remote.Priority.IsNull() {
+		t.Fatalf("expected the default priority to be read as unset, got %s", remote.Priority)
+	}
+}
+
+// Test that an explicit priority of 0 is sent to GTM and kept in state when GTM omits
+// it, while an unset priority stays unset
+func TestClient_zeroPriority(t *testing.T) {
+	planned := resourceClientModel{Name: types.StringValue("GA4"), Type: types.StringValue("gaaw_client"), Priority: types.Int64Value(0)}
+
+	body, err := toApiClient(planned).MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(body), `"priority":0`) {
+		t.Fatalf("expected the priority of 0 to be sent, got %s", body)
+	}
+
+	// GTM omits the default priority, so the client reads back without one.
+	remote := &tagmanager.Client{ClientId: "12", Name: "GA4", Type: "gaaw_client"}
+
+	if priority := clientPriority(remote.Priority, planned.Priority); !priority.Equal(types.Int64Value(0)) {
+		t.Fatalf("expected the explicit priority of 0 to be kept, got %s", priority)
+	}
+
+	if priority := clientPriority(remote.Priority, types.Int64Null()); !priority.IsNull() {
+		t.Fatalf("expected an unset priority to stay unset, got %s", priority)
+	}
+
+	if priority := clientPriority(5, planned.Priority); !priority.Equal(types.Int64Value(5)) {
+		t.Fatalf("expected a priority changed outside Terraform to be read, got %s", priority)
+	}
+}
+
+// Test that a client is rejected at plan time for a web container
+func TestClientResource_webContainer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tagmanager/v2/accounts/1/containers/2" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"containerId": "2", "usageContext": ["web"], "features": {"supportTags": true, "supportZones": true}}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := testClientInWorkspace()
+	client.Client.Service = srv
+
+	ctx := context.Background()
+	r := &clientResource{client: client}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	if diags := plan.SetAttribute(ctx, path.Root("name"), "GA4"); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	var resp fwresource.ModifyPlanResponse
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{Plan: plan}, &resp)
+
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "gtm_client can only be used with a server container") {
+		t.Fatalf("expected the client to be rejected for a web container, got %v", resp.Diagnostics)
+	}
+}
+
+// Test that a client imported by its GTM path gets its id and client_id, and reads back
+// its priority and parameters
+func TestClientResource_import(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tagmanager/v2/accounts/1/containers/2/workspaces/3/clients/12" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"clientId": "12", "name": "GA4", "type": "gaaw_client", "priority": 10,
+			"parameter": [{"key": "activateDefaultPaths", "type": "boolean", "value": "false"}]}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := testClientInWorkspace()
+	client.Client.Service = srv
+
+	ctx := context.Background()
+	r := &clientResource{client: client}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	imported := fwresource.ImportStateResponse{State: tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: "accounts/1/containers/2/workspaces/3/clients/12"}, &imported)
+	if imported.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", imported.Diagnostics)
+	}
+
+	resp := fwresource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: imported.State.Raw.Copy()}}
+	r.Read(ctx, fwresource.ReadRequest{State: imported.State}, &resp)
+
+	var state resourceClientModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if state.Id.ValueString() != "12" || state.ClientId.ValueString() != "12" || state.Priority.ValueInt64() != 10 || len(state.Parameter) != 1 {
+		t.Fatalf("unexpected imported client: %+v", state)
+	}
+}
+
+// Test a GA4 client with a non-default priority and a custom path
+func TestAccClientResource_ga4(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccClientResourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gtm_client.test", "id"),
+					resource.TestCheckResourceAttrPair("gtm_client.test", "client_id", "gtm_client.test", "id"),
+					resource.TestCheckResourceAttr("gtm_client.test", "type", "gaaw_client"),
+					resource.TestCheckResourceAttr("gtm_client.test", "priority", "10"),
+					resource.TestCheckResourceAttr("gtm_client.test", "parameter.1.key", "customPaths"),
+					resource.TestCheckResourceAttr("gtm_client.test", "parameter.1.list.0.value", "/tftest/collect"),
+				),
+			},
+			{
+				Config:   testAccClientResourceConfig(),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      "gtm_client.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccClientResourceConfig() string {
+	return testAccProviderConfig() + `
+resource "gtm_client" "test" {
+  name     = "tftest GA4 client"
+  type     = "gaaw_client"
+  priority = 10
+
+  parameter = [
+    {
+      key   = "activateDefaultPaths"
+      type  = "boolean"
+      value = "false"
+    },
+    {
+      key  = "customPaths"
+      type = "list"
+      list = [
+        { type = "template", value = "/tftest/collect" },
+      ]
+    },
+  ]
+}
+`
+}
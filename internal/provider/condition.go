@@ -1,18 +1,42 @@
 package provider
 
 import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"google.golang.org/api/tagmanager/v2"
 )
 
+// conditionTypes are the operators GTM accepts for a condition. An operator is
+// negated with a boolean "negate" parameter rather than a type of its own.
+var conditionTypes = []string{
+	"equals",
+	"contains",
+	"startsWith",
+	"endsWith",
+	"matchRegex",
+	"greater",
+	"greaterOrEquals",
+	"less",
+	"lessOrEquals",
+	"cssSelector",
+	"urlMatches",
+}
+
+// conditionSchema is the schema of a list of conditions, shared by every attribute
+// holding tagmanager.Condition values together with toApiCondition and
+// toResourceCondition.
 var conditionSchema = schema.ListNestedAttribute{
 	Optional: true,
 	NestedObject: schema.NestedAttributeObject{
 		Attributes: map[string]schema.Attribute{
 			"type": schema.StringAttribute{
-				Description: "Condition type.",
-				Required:    true},
+				Description: "Condition type, one of equals, contains, startsWith, endsWith, matchRegex, greater, greaterOrEquals, less, lessOrEquals, cssSelector or urlMatches. Add a boolean negate parameter to negate it.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(conditionTypes...),
+				}},
 			"parameter": parameterSchema,
 		},
 	},
@@ -56,7 +80,14 @@ func toApiCondition(resourceCondition []ResourceConditionModel) []*tagmanager.Co
 	return condition
 }
 
+// toResourceCondition converts the conditions of an entity, e.g. the filters of a
+// trigger. An entity without conditions is read back as null, which is how a filter
+// that is not configured is kept in state.
 func toResourceCondition(condition []*tagmanager.Condition) []ResourceConditionModel {
+	if len(condition) == 0 {
+		return nil
+	}
+
 	resourceCondition := make([]ResourceConditionModel, len(condition))
 
 	for i, c := range condition {
@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that every operator and its parameters round-trip through the API model
+func TestCondition_operatorsRoundTrip(t *testing.T) {
+	for _, conditionType := range conditionTypes {
+		condition := []ResourceConditionModel{
+			{
+				Type: types.StringValue(conditionType),
+				Parameter: []ResourceParameterModel{
+					testParameter("arg0", "{{_event}}"),
+					testParameter("arg1", "purchase"),
+					{
+						Key:   types.StringValue("negate"),
+						Type:  types.StringValue("boolean"),
+						Value: types.StringValue("true"),
+					},
+				},
+			},
+		}
+
+		roundTripped := toResourceCondition(toApiCondition(condition))
+
+		if len(roundTripped) != 1 || !roundTripped[0].Equal(condition[0]) {
+			t.Fatalf("expected %s condition to round-trip, got %v", conditionType, roundTripped)
+		}
+	}
+}
+
+// Test that a case-insensitive matchRegex filter keeps its type and parameters
+func TestCondition_matchRegex(t *testing.T) {
+	condition := []ResourceConditionModel{
+		{
+			Type: types.StringValue("matchRegex"),
+			Parameter: []ResourceParameterModel{
+				testParameter("arg0", "{{_event}}"),
+				testParameter("arg1", "^(purchase|refund)$"),
+				{
+					Key:   types.StringValue("ignore_case"),
+					Type:  types.StringValue("boolean"),
+					Value: types.StringValue("true"),
+				},
+			},
+		},
+	}
+
+	apiCondition := toApiCondition(condition)
+
+	if apiCondition[0].Type != "matchRegex" || apiCondition[0].Parameter[1].Value != "^(purchase|refund)$" || apiCondition[0].Parameter[2].Key != "ignore_case" {
+		t.Fatalf("unexpected API condition: %+v", apiCondition[0])
+	}
+
+	if roundTripped := toResourceCondition(apiCondition); !roundTripped[0].Equal(condition[0]) {
+		t.Fatalf("expected matchRegex condition to round-trip, got %v", roundTripped[0])
+	}
+}
+
+// Test that nested list and map parameters of a condition round-trip at every depth
+func TestCondition_nestedParameters(t *testing.T) {
+	condition := []ResourceConditionModel{
+		{
+			Type: types.StringValue("equals"),
+			Parameter: []ResourceParameterModel{
+				testParameter("arg0", "{{Page Path}}"),
+				{
+					Key:  types.StringValue("arg1"),
+					Type: types.StringValue("list"),
+					List: []ResourceParameterModel{
+						{
+							Type: types.StringValue("map"),
+							Map: []ResourceParameterModel{
+								testParameter("path", "/checkout"),
+								{
+									Key:  types.StringValue("variants"),
+									Type: types.StringValue("list"),
+									List: []ResourceParameterModel{{Type: types.StringValue("template"), Value: types.StringValue("/checkout/")}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	apiCondition := toApiCondition(condition)
+
+	if value := apiCondition[0].Parameter[1].List[0].Map[1].List[0].Value; value != "/checkout/" {
+		t.Fatalf("expected the nested value to be sent, got %q", value)
+	}
+
+	if roundTripped := toResourceCondition(apiCondition); len(roundTripped) != 1 || !roundTripped[0].Equal(condition[0]) {
+		t.Fatalf("expected the nested condition to round-trip, got %v", roundTripped)
+	}
+}
+
+// Test that missing conditions are read back as null and conditions without
+// parameters keep their type
+func TestCondition_empty(t *testing.T) {
+	if toResourceCondition(nil) != nil || toResourceCondition([]*tagmanager.Condition{}) != nil {
+		t.Fatal("expected no conditions to be read back as null")
+	}
+
+	if len(toApiCondition(nil)) != 0 {
+		t.Fatal("expected no conditions to be sent")
+	}
+
+	condition := []ResourceConditionModel{{Type: types.StringValue("cssSelector")}}
+
+	roundTripped := toResourceCondition(toApiCondition(condition))
+	if len(roundTripped) != 1 || !roundTripped[0].Equal(condition[0]) || roundTripped[0].Parameter != nil {
+		t.Fatalf("expected a condition without parameters to round-trip, got %v", roundTripped)
+	}
+}
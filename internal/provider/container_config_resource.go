@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ resource.Resource                = &containerConfigResource{}
+	_ resource.ResourceWithConfigure   = &containerConfigResource{}
+	_ resource.ResourceWithImportState = &containerConfigResource{}
+	_ resource.ResourceWithModifyPlan  = &containerConfigResource{}
+)
+
+type containerConfigResource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewContainerConfigResource() resource.Resource {
+	return &containerConfigResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *containerConfigResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.ClientInWorkspace)
+}
+
+// Metadata returns the resource type name.
+func (r *containerConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_container_config"
+}
+
+var containerConfigResourceSchemaAttributes = map[string]schema.Attribute{
+	"id": schema.StringAttribute{
+		Description: "The ID of the container.",
+		Computed:    true,
+	},
+	"tagging_server_urls": schema.ListAttribute{
+		Description: "List of server-side container URLs for the container. If multiple URLs are provided, all URL paths must match.",
+		Optional:    true,
+		ElementType: types.StringType,
+	},
+	"notes": schema.StringAttribute{
+		Description: "The notes of the container. The notes in GTM are kept when unset.",
+		Optional:    true,
+		Computed:    true,
+		Validators:  notesValidators,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	},
+	"public_id": schema.StringAttribute{
+		Description: "The public ID of the container, e.g. GTM-XXXXXX.",
+		Computed:    true,
+	},
+}
+
+// Schema defines the schema for the resource.
+func (r *containerConfigResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{Attributes: containerConfigResourceSchemaAttributes}
+}
+
+type resourceContainerConfigModel struct {
+	Id                types.String   `tfsdk:"id"`
+	TaggingServerUrls []types.String `tfsdk:"tagging_server_urls"`
+	Notes             types.String   `tfsdk:"notes"`
+	PublicId          types.String   `tfsdk:"public_id"`
+}
+
+// ModifyPlan rejects the resource at plan time when the configured container is not a
+// server container, as only server containers have tagging server URLs.
+func (r *containerConfigResource) ModifyPlan(_ context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	requireServerContainer(r.client, "gtm_container_config", &resp.Diagnostics)
+}
+
+// Create applies the settings to the configured container and sets the initial Terraform state.
+func (r *containerConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan resourceContainerConfigModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	container, err := r.applyContainerConfig(plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating Container Config", err.Error())
+		return
+	}
+
+	var resource = toResourceContainerConfig(container)
+
+	diags = resp.State.Set(ctx, &resource)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *containerConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state resourceContainerConfigModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	container, err := r.client.Container()
+	if err == api.ErrNotExist {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Reading Container Config", err.Error())
+		return
+	}
+
+	var resource = toResourceContainerConfig(container)
+
+	diags = resp.State.Set(ctx, &resource)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *containerConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan resourceContainerConfigModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	container, err := r.applyContainerConfig(plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating Container Config", err.Error())
+		return
+	}
+
+	var resource = toResourceContainerConfig(container)
+
+	diags = resp.State.Set(ctx, &resource)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete clears the tagging server URLs. The notes and the container itself are left
+// untouched.
+func (r *containerConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state resourceContainerConfigModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := r.applyContainerConfig(resourceContainerConfigModel{})
+	if err == api.ErrNotExist {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Deleting Container Config", err.Error())
+		return
+	}
+}
+
+// ImportState accepts the id or the GTM path of the configured container, the only
+// container the resource can manage.
+func (r *containerConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	options := r.client.Client.Options
+	if req.ID != options.ContainerId && req.ID != "accounts/"+options.AccountId+"/containers/"+options.ContainerId {
+		resp.Diagnostics.AddAttributeError(path.Root("id"), "Error Importing Container Config",
+			fmt.Sprintf("gtm_container_config manages the configured container %s, got %s.", options.ContainerId, req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), options.ContainerId)...)
+}
+
+// applyContainerConfig writes the managed settings onto the current container,
+// keeping every field the resource does not manage as it is. Unset notes are not
+// managed either.
+func (r *containerConfigResource) applyContainerConfig(config resourceContainerConfigModel) (*tagmanager.Container, error) {
+	container, err := r.client.Container()
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(container.UsageContext, "server") {
+		return nil, fmt.Errorf("container %s is not a server container", container.PublicId)
+	}
+
+	container.TaggingServerUrls = unwrapStringArray(config.TaggingServerUrls)
+	container.ForceSendFields = []string{"TaggingServerUrls"}
+
+	if !config.Notes.IsNull() && !config.Notes.IsUnknown() {
+		container.Notes = config.Notes.ValueString()
+		container.ForceSendFields = append(container.ForceSendFields, "Notes")
+	}
+
+	return r.client.UpdateContainer(container)
+}
+
+func toResourceContainerConfig(container *tagmanager.Container) resourceContainerConfigModel {
+	return resourceContainerConfigModel{
+		Id:                types.StringValue(container.ContainerId),
+		TaggingServerUrls: toResourceStringArray(container.TaggingServerUrls),
+		Notes:             nullableStringValue(container.Notes),
+		PublicId:          types.StringValue(container.PublicId),
+	}
+}
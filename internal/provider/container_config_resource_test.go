@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"google.golang.org/api/option"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test setting and reading back the tagging server URLs of a server container
+func TestAccContainerConfigResource_taggingServerUrls(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccContainerConfigResourceConfig("https://sgtm.example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_container_config.test", "id", os.Getenv("GTM_CONTAINER_ID")),
+					resource.TestCheckResourceAttrSet("gtm_container_config.test", "public_id"),
+					resource.TestCheckResourceAttr("gtm_container_config.test", "tagging_server_urls.#", "1"),
+					resource.TestCheckResourceAttr("gtm_container_config.test", "tagging_server_urls.0", "https://sgtm.example.com"),
+				),
+			},
+			{
+				Config: testAccContainerConfigResourceConfig("https://sgtm-updated.example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_container_config.test", "tagging_server_urls.#", "1"),
+					resource.TestCheckResourceAttr("gtm_container_config.test", "tagging_server_urls.0", "https://sgtm-updated.example.com"),
+				),
+			},
+		},
+	})
+}
+
+func testAccContainerConfigResourceConfig(url string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "gtm_container_config" "test" {
+  tagging_server_urls = [%q]
+  notes               = "Managed by Terraform"
+}
+`, url)
+}
+
+// Test that a server-only resource is rejected for a web container
+func TestCheckServerContainer(t *testing.T) {
+	var diags diag.Diagnostics
+	checkServerContainer(&tagmanager.ContainerFeatures{SupportTags: true, SupportZones: true}, "gtm_container_config", "2", &diags)
+
+	if !diags.HasError() || !strings.Contains(diags[0].Detail(), "gtm_container_config can only be used with a server container") {
+		t.Fatalf("expected a web container to be rejected, got %v", diags)
+	}
+
+	diags = nil
+	checkServerContainer(&tagmanager.ContainerFeatures{SupportClients: true, SupportTransformations: true}, "gtm_container_config", "2", &diags)
+	if diags.HasError() {
+		t.Fatalf("expected a server container to be accepted, got %v", diags)
+	}
+}
+
+// Test that unset notes and deleting the resource keep the notes of the container, while
+// configured notes and the tagging server URLs are written
+func TestContainerConfig_notes(t *testing.T) {
+	var updates []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tagmanager/v2/accounts/1/containers/2" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		if r.Method == http.MethodPut {
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("unexpected body: %v", err)
+			}
+			updates = append(updates, body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"containerId": "2", "usageContext": ["server"], "notes": "Owned by analytics",
+			"taggingServerUrls": ["https://sgtm.example.com"]}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := testClientInWorkspace()
+	client.Client.Service = srv
+	r := &containerConfigResource{client: client}
+
+	configs := []resourceContainerConfigModel{
+		{TaggingServerUrls: []types.String{types.StringValue("https://sgtm.example.com")}, Notes: types.StringNull()},
+		{TaggingServerUrls: []types.String{types.StringValue("https://sgtm.example.com")}, Notes: types.StringUnknown()},
+		{},
+		{Notes: types.StringValue("")},
+	}
+	for _, config := range configs {
+		if _, err := r.applyContainerConfig(config); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for i, notes := range []string{"Owned by analytics", "Owned by analytics", "Owned by analytics", ""} {
+		if updates[i]["notes"] != notes {
+			t.Fatalf("expected update %d to send the notes %q, got %v", i, notes, updates[i])
+		}
+	}
+
+	if urls, ok := updates[2]["taggingServerUrls"]; !ok || len(urls.([]any)) != 0 {
+		t.Fatalf("expected the tagging server URLs to be cleared, got %v", updates[2])
+	}
+}
+
+// Test that only the configured container can be imported
+func TestContainerConfigResource_import(t *testing.T) {
+	ctx := context.Background()
+	r := &containerConfigResource{client: testClientInWorkspace()}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	for id, valid := range map[string]bool{"2": true, "accounts/1/containers/2": true, "3": false, "accounts/1/containers/3": false} {
+		resp := fwresource.ImportStateResponse{State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		}}
+		r.ImportState(ctx, fwresource.ImportStateRequest{ID: id}, &resp)
+
+		if resp.Diagnostics.HasError() == valid {
+			t.Fatalf("expected the import of %s to be valid: %t, got %v", id, valid, resp.Diagnostics)
+		}
+
+		var imported types.String
+		if valid {
+			resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("id"), &imported)...)
+			if imported.ValueString() != "2" {
+				t.Fatalf("expected the container id to be imported, got %s", imported)
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &containerExportDiffDataSource{}
+	_ datasource.DataSourceWithConfigure = &containerExportDiffDataSource{}
+)
+
+type containerExportDiffDataSource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewContainerExportDiffDataSource() datasource.DataSource {
+	return &containerExportDiffDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *containerExportDiffDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(d.client, &resp.Diagnostics)
+}
+
+// Metadata returns the data source type name.
+func (d *containerExportDiffDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_container_export_diff"
+}
+
+// changeListSchema returns the attribute listing the entities a reconciliation would
+// create, update or delete.
+func changeListSchema(action string) schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Description: "The tags, triggers and variables that would be " + action + ", ordered by kind and then by name.",
+		Computed:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"kind": schema.StringAttribute{
+					Description: "The kind of the entity, one of tag, trigger or variable.",
+					Computed:    true,
+				},
+				"id": schema.StringAttribute{
+					Description: "The ID of the entity in the workspace, null for an entity that would be created.",
+					Computed:    true,
+				},
+				"name": schema.StringAttribute{
+					Description: "The name of the entity.",
+					Computed:    true,
+				},
+				"type": schema.StringAttribute{
+					Description: "The type of the entity.",
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+// Schema defines the schema for the data source.
+func (d *containerExportDiffDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Previews the changes that would make the tags, triggers and variables of the workspace match a GTM container export, without applying them, e.g. to plan a migration. Entities are matched by name.",
+		Attributes: map[string]schema.Attribute{
+			"export_json": schema.StringAttribute{
+				Description: "The container export, as exported from the GTM UI, e.g. read with file().",
+				Required:    true,
+			},
+			"create": changeListSchema("created"),
+			"update": changeListSchema("updated"),
+			"delete": changeListSchema("deleted"),
+			"has_changes": schema.BoolAttribute{
+				Description: "Whether the workspace differs from the export.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type dataSourceContainerExportDiffModel struct {
+	ExportJson types.String            `tfsdk:"export_json"`
+	Create     []dataSourceChangeModel `tfsdk:"create"`
+	Update     []dataSourceChangeModel `tfsdk:"update"`
+	Delete     []dataSourceChangeModel `tfsdk:"delete"`
+	HasChanges types.Bool              `tfsdk:"has_changes"`
+}
+
+type dataSourceChangeModel struct {
+	Kind types.String `tfsdk:"kind"`
+	Id   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+}
+
+// toDataSourceChanges lists the entities of contents, tags first, then triggers and
+// variables, each ordered by name.
+func toDataSourceChanges(contents api.WorkspaceContents) []dataSourceChangeModel {
+	var changes = []dataSourceChangeModel{}
+
+	var add = func(kind string, id string, name string, entityType string) {
+		changes = append(changes, dataSourceChangeModel{
+			Kind: types.StringValue(kind),
+			Id:   nullableStringValue(id),
+			Name: types.StringValue(name),
+			Type: types.StringValue(entityType),
+		})
+	}
+
+	for _, tag := range contents.Tags {
+		add("tag", tag.TagId, tag.Name, tag.Type)
+	}
+	for _, trigger := range contents.Triggers {
+		add("trigger", trigger.TriggerId, trigger.Name, trigger.Type)
+	}
+	for _, variable := range contents.Variables {
+		add("variable", variable.VariableId, variable.Name, variable.Type)
+	}
+
+	var kinds = map[string]int{"tag": 0, "trigger": 1, "variable": 2}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if a, b := kinds[changes[i].Kind.ValueString()], kinds[changes[j].Kind.ValueString()]; a != b {
+			return a < b
+		}
+		return changes[i].Name.ValueString() < changes[j].Name.ValueString()
+	})
+
+	return changes
+}
+
+func toDataSourceContainerExportDiff(exportJson types.String, diff *api.WorkspaceDiff) dataSourceContainerExportDiffModel {
+	// Entities to create come from the export, whose ids are not the ones of the workspace.
+	var create = diff.Create
+	create.Tags, create.Triggers, create.Variables = nil, nil, nil
+	for _, tag := range diff.Create.Tags {
+		t := *tag
+		t.TagId = ""
+		create.Tags = append(create.Tags, &t)
+	}
+	for _, trigger := range diff.Create.Triggers {
+		t := *trigger
+		t.TriggerId = ""
+		create.Triggers = append(create.Triggers, &t)
+	}
+	for _, variable := range diff.Create.Variables {
+		v := *variable
+		v.VariableId = ""
+		create.Variables = append(create.Variables, &v)
+	}
+
+	return dataSourceContainerExportDiffModel{
+		ExportJson: exportJson,
+		Create:     toDataSourceChanges(create),
+		Update:     toDataSourceChanges(diff.Update),
+		Delete:     toDataSourceChanges(diff.Delete),
+		HasChanges: types.BoolValue(!diff.Empty()),
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *containerExportDiffDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config dataSourceContainerExportDiffModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diff, err := d.client.DiffContainerExport([]byte(config.ExportJson.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("export_json"), "Error Diffing Container Export", err.Error())
+		return
+	}
+
+	var state = toDataSourceContainerExportDiff(config.ExportJson, diff)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
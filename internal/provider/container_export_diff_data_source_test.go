@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"terraform-provider-google-tag-manager/internal/api"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that the changes are listed by kind and name, with no workspace id for the entities to create
+func TestToDataSourceContainerExportDiff(t *testing.T) {
+	diff := &api.WorkspaceDiff{
+		Create: api.WorkspaceContents{
+			Tags:      []*tagmanager.Tag{{TagId: "3", Name: "b", Type: "html"}, {TagId: "2", Name: "a", Type: "html"}},
+			Variables: []*tagmanager.Variable{{VariableId: "5", Name: "added", Type: "v"}},
+		},
+		Update: api.WorkspaceContents{Tags: []*tagmanager.Tag{{TagId: "102", Name: "changed", Type: "html"}}},
+		Delete: api.WorkspaceContents{Triggers: []*tagmanager.Trigger{{TriggerId: "101", Name: "obsolete", Type: "click"}}},
+	}
+
+	model := toDataSourceContainerExportDiff(types.StringValue("{}"), diff)
+
+	if len(model.Create) != 3 || model.Create[0].Name.ValueString() != "a" || model.Create[1].Name.ValueString() != "b" ||
+		model.Create[2].Kind.ValueString() != "variable" {
+		t.Fatalf("expected the tags to create ordered by name before the variable, got %v", model.Create)
+	}
+	for _, change := range model.Create {
+		if !change.Id.IsNull() {
+			t.Fatalf("expected no id for an entity to create, got %v", change)
+		}
+	}
+
+	if len(model.Update) != 1 || model.Update[0].Id.ValueString() != "102" || model.Update[0].Kind.ValueString() != "tag" {
+		t.Fatalf("unexpected updates: %v", model.Update)
+	}
+
+	if len(model.Delete) != 1 || model.Delete[0].Id.ValueString() != "101" || model.Delete[0].Kind.ValueString() != "trigger" {
+		t.Fatalf("unexpected deletes: %v", model.Delete)
+	}
+
+	if !model.HasChanges.ValueBool() {
+		t.Fatal("expected has_changes to be true")
+	}
+
+	if empty := toDataSourceContainerExportDiff(types.StringValue("{}"), &api.WorkspaceDiff{}); empty.HasChanges.ValueBool() || empty.Create == nil {
+		t.Fatalf("expected an empty diff without changes, got %v", empty)
+	}
+}
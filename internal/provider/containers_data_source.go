@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ datasource.DataSource              = &containersDataSource{}
+	_ datasource.DataSourceWithConfigure = &containersDataSource{}
+)
+
+type containersDataSource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewContainersDataSource() datasource.DataSource {
+	return &containersDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *containersDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.ClientInWorkspace)
+}
+
+// Metadata returns the data source type name.
+func (d *containersDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_containers"
+}
+
+// Schema defines the schema for the data source.
+func (d *containersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the containers of the account, e.g. to find the IDs of the other containers of a multi-container setup.",
+		Attributes: map[string]schema.Attribute{
+			"containers": schema.ListNestedAttribute{
+				Description: "The containers of the account.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"container_id": schema.StringAttribute{
+							Description: "The numeric ID of the container.",
+							Computed:    true,
+						},
+						"public_id": schema.StringAttribute{
+							Description: "The public ID of the container, e.g. GTM-XXXXXX.",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "The name of the container.",
+							Computed:    true,
+						},
+						"usage_context": schema.ListAttribute{
+							Description: "The usage contexts of the container, e.g. web, server, android or ios.",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type dataSourceContainersModel struct {
+	Containers []dataSourceContainerModel `tfsdk:"containers"`
+}
+
+type dataSourceContainerModel struct {
+	ContainerId  types.String   `tfsdk:"container_id"`
+	PublicId     types.String   `tfsdk:"public_id"`
+	Name         types.String   `tfsdk:"name"`
+	UsageContext []types.String `tfsdk:"usage_context"`
+}
+
+func toDataSourceContainers(containers []*tagmanager.Container) dataSourceContainersModel {
+	var model = dataSourceContainersModel{Containers: make([]dataSourceContainerModel, 0, len(containers))}
+
+	for _, container := range containers {
+		model.Containers = append(model.Containers, dataSourceContainerModel{
+			ContainerId:  types.StringValue(container.ContainerId),
+			PublicId:     nullableStringValue(container.PublicId),
+			Name:         nullableStringValue(container.Name),
+			UsageContext: toResourceStringArray(container.UsageContext),
+		})
+	}
+
+	return model
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *containersDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	containers, err := d.client.ListContainers()
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Containers", err.Error())
+		return
+	}
+
+	var state = toDataSourceContainers(containers)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
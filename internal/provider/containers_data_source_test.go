@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"google.golang.org/api/option"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that the containers listed by the account are read into the data source
+func TestContainersDataSource_read(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tagmanager/v2/accounts/1/containers" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"container": [
+			{"containerId": "2", "publicId": "GTM-TEST", "name": "Web", "usageContext": ["web"]},
+			{"containerId": "7", "publicId": "GTM-SERVER", "name": "Server", "usageContext": ["server"]}
+		]}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := testClientInWorkspace()
+	client.Client.Service = srv
+
+	ctx := context.Background()
+	d := &containersDataSource{client: client}
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	resp := datasource.ReadResponse{State: tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}}
+	d.Read(ctx, datasource.ReadRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var model dataSourceContainersModel
+	if diags := resp.State.Get(ctx, &model); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if len(model.Containers) != 2 {
+		t.Fatalf("expected 2 containers, got %d", len(model.Containers))
+	}
+
+	serverContainer := model.Containers[1]
+	if serverContainer.ContainerId.ValueString() != "7" || serverContainer.PublicId.ValueString() != "GTM-SERVER" ||
+		serverContainer.Name.ValueString() != "Server" || len(serverContainer.UsageContext) != 1 || serverContainer.UsageContext[0].ValueString() != "server" {
+		t.Fatalf("unexpected container: %v", serverContainer)
+	}
+}
@@ -0,0 +1,288 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ resource.Resource                = &customTemplateResource{}
+	_ resource.ResourceWithConfigure   = &customTemplateResource{}
+	_ resource.ResourceWithImportState = &customTemplateResource{}
+)
+
+type customTemplateResource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewCustomTemplateResource() resource.Resource {
+	return &customTemplateResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *customTemplateResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(r.client, &resp.Diagnostics)
+}
+
+// Metadata returns the resource type name.
+func (r *customTemplateResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_custom_template"
+}
+
+var galleryReferenceSchema = schema.SingleNestedAttribute{
+	Description: "Gallery the template was imported from. Leave unset for templates that are not from the Community Template Gallery.",
+	Optional:    true,
+	Attributes: map[string]schema.Attribute{
+		"host": schema.StringAttribute{
+			Description: "The name of the host for the community gallery template.",
+			Optional:    true,
+		},
+		"owner": schema.StringAttribute{
+			Description: "The name of the owner for the community gallery template.",
+			Optional:    true,
+		},
+		"repository": schema.StringAttribute{
+			Description: "The name of the repository for the community gallery template.",
+			Optional:    true,
+		},
+		"version": schema.StringAttribute{
+			Description: "The version of the community gallery template.",
+			Optional:    true,
+		},
+		"gallery_template_id": schema.StringAttribute{
+			Description: "ID for the gallery template that is generated once during first sync and travels with the template redirects.",
+			Optional:    true,
+		},
+		"template_developer_id": schema.StringAttribute{
+			Description: "The developer id of the community gallery template.",
+			Optional:    true,
+		},
+		"signature": schema.StringAttribute{
+			Description: "The signature of the community gallery template.",
+			Optional:    true,
+		},
+	},
+}
+
+var customTemplateResourceSchemaAttributes = withWorkspaceEntityAttributes(map[string]schema.Attribute{
+	"name": schema.StringAttribute{
+		Description: "The name of the custom template.",
+		Required:    true,
+	},
+	"template_data": schema.StringAttribute{
+		Description: "The custom template in .tpl format.",
+		Required:    true,
+	},
+	"id": schema.StringAttribute{
+		Description: "The ID of the custom template.",
+		Computed:    true,
+	},
+	"template_id": schema.StringAttribute{
+		Description: "The ID GTM assigned to the custom template, the same as id.",
+		Computed:    true,
+	},
+	"gallery_reference": galleryReferenceSchema,
+})
+
+// Schema defines the schema for the resource.
+func (r *customTemplateResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{Attributes: customTemplateResourceSchemaAttributes}
+}
+
+type resourceGalleryReferenceModel struct {
+	Host                types.String `tfsdk:"host"`
+	Owner               types.String `tfsdk:"owner"`
+	Repository          types.String `tfsdk:"repository"`
+	Version             types.String `tfsdk:"version"`
+	GalleryTemplateId   types.String `tfsdk:"gallery_template_id"`
+	TemplateDeveloperId types.String `tfsdk:"template_developer_id"`
+	Signature           types.String `tfsdk:"signature"`
+}
+
+type resourceCustomTemplateModel struct {
+	Name             types.String                   `tfsdk:"name"`
+	TemplateData     types.String                   `tfsdk:"template_data"`
+	Id               types.String                   `tfsdk:"id"`
+	TemplateId       types.String                   `tfsdk:"template_id"`
+	GalleryReference *resourceGalleryReferenceModel `tfsdk:"gallery_reference"`
+	workspaceEntityModel
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *customTemplateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan resourceCustomTemplateModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	template, err := r.client.CreateTemplate(toApiCustomTemplate(plan))
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating Custom Template", err.Error())
+		return
+	}
+
+	diags = recordCreatedId(ctx, &resp.State, template.TemplateId)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = types.StringValue(template.TemplateId)
+	plan.TemplateId = types.StringValue(template.TemplateId)
+	plan.workspaceEntityModel = workspaceEntityLocation(r.client, "templates", template.TemplateId)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *customTemplateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state resourceCustomTemplateModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	template, err := r.client.Template(state.Id.ValueString())
+	if err == api.ErrNotExist {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Reading Custom Template", err.Error())
+		return
+	}
+
+	var resource = toResourceCustomTemplate(template, r.client)
+
+	diags = resp.State.Set(ctx, &resource)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *customTemplateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state resourceCustomTemplateModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	template, err := r.client.UpdateTemplate(state.Id.ValueString(), toApiCustomTemplate(plan))
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating Custom Template", err.Error())
+		return
+	}
+
+	plan.Id = types.StringValue(template.TemplateId)
+	plan.TemplateId = types.StringValue(template.TemplateId)
+	plan.workspaceEntityModel = workspaceEntityLocation(r.client, "templates", template.TemplateId)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *customTemplateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state resourceCustomTemplateModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteTemplate(state.Id.ValueString())
+	if err == api.ErrNotExist {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Deleting Custom Template", err.Error())
+		return
+	}
+}
+
+// ImportState accepts the template id or the full GTM path of the template.
+func (r *customTemplateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, err := workspaceImportId(r.client, "templates", req.ID)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("id"), "Error Importing Custom Template", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+func toResourceGalleryReference(ref *tagmanager.GalleryReference) *resourceGalleryReferenceModel {
+	if ref == nil {
+		return nil
+	}
+
+	return &resourceGalleryReferenceModel{
+		Host:                nullableStringValue(ref.Host),
+		Owner:               nullableStringValue(ref.Owner),
+		Repository:          nullableStringValue(ref.Repository),
+		Version:             nullableStringValue(ref.Version),
+		GalleryTemplateId:   nullableStringValue(ref.GalleryTemplateId),
+		TemplateDeveloperId: nullableStringValue(ref.TemplateDeveloperId),
+		Signature:           nullableStringValue(ref.Signature),
+	}
+}
+
+func toApiGalleryReference(ref *resourceGalleryReferenceModel) *tagmanager.GalleryReference {
+	if ref == nil {
+		return nil
+	}
+
+	return &tagmanager.GalleryReference{
+		Host:                ref.Host.ValueString(),
+		Owner:               ref.Owner.ValueString(),
+		Repository:          ref.Repository.ValueString(),
+		Version:             ref.Version.ValueString(),
+		GalleryTemplateId:   ref.GalleryTemplateId.ValueString(),
+		TemplateDeveloperId: ref.TemplateDeveloperId.ValueString(),
+		Signature:           ref.Signature.ValueString(),
+	}
+}
+
+func toResourceCustomTemplate(template *tagmanager.CustomTemplate, client *api.ClientInWorkspace) resourceCustomTemplateModel {
+	return resourceCustomTemplateModel{
+		Name:                 types.StringValue(template.Name),
+		TemplateData:         types.StringValue(template.TemplateData),
+		Id:                   types.StringValue(template.TemplateId),
+		TemplateId:           types.StringValue(template.TemplateId),
+		GalleryReference:     toResourceGalleryReference(template.GalleryReference),
+		workspaceEntityModel: workspaceEntityLocation(client, "templates", template.TemplateId),
+	}
+}
+
+func toApiCustomTemplate(resource resourceCustomTemplateModel) *tagmanager.CustomTemplate {
+	return &tagmanager.CustomTemplate{
+		Name:             resource.Name.ValueString(),
+		TemplateData:     resource.TemplateData.ValueString(),
+		TemplateId:       resource.Id.ValueString(),
+		GalleryReference: toApiGalleryReference(resource.GalleryReference),
+	}
+}
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// Test that a gallery reference round-trips through create and read
+func TestAccCustomTemplateResource_galleryReference(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomTemplateResourceGalleryConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gtm_custom_template.gallery", "id"),
+					resource.TestCheckResourceAttrPair("gtm_custom_template.gallery", "template_id", "gtm_custom_template.gallery", "id"),
+					resource.TestCheckResourceAttr("gtm_custom_template.gallery", "name", "tf-test-template-gallery"),
+					resource.TestCheckResourceAttr("gtm_custom_template.gallery", "gallery_reference.host", "github.com"),
+					resource.TestCheckResourceAttr("gtm_custom_template.gallery", "gallery_reference.owner", "example"),
+					resource.TestCheckResourceAttr("gtm_custom_template.gallery", "gallery_reference.repository", "gtm-template"),
+					resource.TestCheckResourceAttr("gtm_custom_template.gallery", "gallery_reference.version", "1.0.0"),
+				),
+			},
+			{
+				ResourceName:      "gtm_custom_template.gallery",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// Test that a custom template imported by its GTM path gets its id and template_id
+func TestAccCustomTemplateResource_importByPath(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomTemplateResourceGalleryConfig(),
+			},
+			{
+				ResourceName: "gtm_custom_template.gallery",
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["gtm_custom_template.gallery"]
+					if !ok {
+						return "", fmt.Errorf("Custom template resource not found: gtm_custom_template.gallery")
+					}
+
+					return rs.Primary.Attributes["path"], nil
+				},
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCustomTemplateResourceGalleryConfig() string {
+	return testAccProviderConfig() + `
+resource "gtm_custom_template" "gallery" {
+  name          = "tf-test-template-gallery"
+  template_data = <<-EOT
+    ___INFO___
+
+    {
+      "type": "TAG",
+      "id": "cvt_temp_public_id",
+      "version": 1,
+      "displayName": "tf-test-template-gallery",
+      "containerContexts": ["WEB"]
+    }
+
+    ___SANDBOXED_JS_FOR_WEB_TEMPLATE___
+
+    data.gtmOnSuccess();
+  EOT
+
+  gallery_reference = {
+    host       = "github.com"
+    owner      = "example"
+    repository = "gtm-template"
+    version    = "1.0.0"
+  }
+}
+`
+}
@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ resource.Resource                = &destinationResource{}
+	_ resource.ResourceWithConfigure   = &destinationResource{}
+	_ resource.ResourceWithImportState = &destinationResource{}
+)
+
+type destinationResource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewDestinationResource() resource.Resource {
+	return &destinationResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *destinationResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.ClientInWorkspace)
+}
+
+// Metadata returns the resource type name.
+func (r *destinationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_destination"
+}
+
+var destinationResourceSchemaAttributes = map[string]schema.Attribute{
+	"destination_id": schema.StringAttribute{
+		Description: "The ID of the destination to link, e.g. a GA4 measurement ID such as G-XXXXXXXXXX.",
+		Required:    true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	},
+	"id": schema.StringAttribute{
+		Description: "The link ID of the destination in the container.",
+		Computed:    true,
+	},
+	"name": schema.StringAttribute{
+		Description: "The display name of the destination.",
+		Computed:    true,
+	},
+}
+
+// Schema defines the schema for the resource.
+func (r *destinationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{Attributes: destinationResourceSchemaAttributes}
+}
+
+type resourceDestinationModel struct {
+	DestinationId types.String `tfsdk:"destination_id"`
+	Id            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+}
+
+func toResourceDestination(destination *tagmanager.Destination) resourceDestinationModel {
+	return resourceDestinationModel{
+		DestinationId: types.StringValue(destination.DestinationId),
+		Id:            types.StringValue(destination.DestinationLinkId),
+		Name:          nullableStringValue(destination.Name),
+	}
+}
+
+// Create links the destination to the container and sets the initial Terraform state.
+func (r *destinationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan resourceDestinationModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	destination, err := r.client.LinkDestination(plan.DestinationId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error Linking Destination", err.Error())
+		return
+	}
+
+	diags = recordCreatedId(ctx, &resp.State, destination.DestinationLinkId)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var resource = toResourceDestination(destination)
+
+	diags = resp.State.Set(ctx, &resource)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *destinationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state resourceDestinationModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	destination, err := r.client.Destination(state.Id.ValueString())
+	if err == api.ErrNotExist {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Reading Destination", err.Error())
+		return
+	}
+
+	var resource = toResourceDestination(destination)
+
+	diags = resp.State.Set(ctx, &resource)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update is never called since every attribute requires replacement.
+func (r *destinationResource) Update(_ context.Context, _ resource.UpdateRequest, _ *resource.UpdateResponse) {
+}
+
+// Delete removes the destination from the Terraform state. The GTM API has no way to
+// unlink a destination, so the link itself stays in place until it is moved to another
+// container or removed in the GTM UI.
+func (r *destinationResource) Delete(_ context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.AddWarning("Destination Left Linked",
+		"The GTM API does not support unlinking destinations. The destination was removed from the Terraform state but is still linked to the container.")
+}
+
+func (r *destinationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
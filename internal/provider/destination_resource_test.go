@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// Test that a destination can be linked, read back and imported
+func TestAccDestinationResource_link(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	destinationId := os.Getenv("GTM_TEST_DESTINATION_ID")
+	if destinationId == "" {
+		t.Skip("GTM_TEST_DESTINATION_ID must be set to a GA4 measurement ID to test destination linking")
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDestinationResourceConfig(destinationId),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gtm_destination.ga4", "id"),
+					resource.TestCheckResourceAttr("gtm_destination.ga4", "destination_id", destinationId),
+				),
+			},
+			{
+				ResourceName:      "gtm_destination.ga4",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccDestinationResourceConfig(destinationId string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "gtm_destination" "ga4" {
+  destination_id = %q
+}
+`, destinationId)
+}
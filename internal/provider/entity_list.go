@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// entityListSchema returns the attribute listing the entities of a kind in the plural
+// data sources, e.g. gtm_variables.
+func entityListSchema(kind string) schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Description: "The " + kind + "s of the workspace, ordered by name and then by ID.",
+		Computed:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"id": schema.StringAttribute{
+					Description: "The ID of the " + kind + ".",
+					Computed:    true,
+				},
+				"name": schema.StringAttribute{
+					Description: "The name of the " + kind + ".",
+					Computed:    true,
+				},
+				"type": schema.StringAttribute{
+					Description: "The type of the " + kind + ".",
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+type dataSourceEntityModel struct {
+	Id   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+}
+
+// sortEntities orders listed entities by name and then by id. GTM lists entities in
+// no particular order, which would otherwise churn the plans using the list.
+func sortEntities(entities []dataSourceEntityModel) []dataSourceEntityModel {
+	sort.SliceStable(entities, func(i, j int) bool {
+		if a, b := entities[i].Name.ValueString(), entities[j].Name.ValueString(); a != b {
+			return a < b
+		}
+		return entities[i].Id.ValueString() < entities[j].Id.ValueString()
+	})
+
+	return entities
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"testing"
+
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that two reads of a list GTM returned in different orders produce the same state
+func TestToDataSourceVariables_stableOrder(t *testing.T) {
+	first := toDataSourceVariables([]*tagmanager.Variable{
+		{VariableId: "3", Name: "Page Type", Type: "v"},
+		{VariableId: "1", Name: "Currency", Type: "c"},
+		{VariableId: "7", Name: "Currency", Type: "jsm"},
+	})
+	second := toDataSourceVariables([]*tagmanager.Variable{
+		{VariableId: "7", Name: "Currency", Type: "jsm"},
+		{VariableId: "3", Name: "Page Type", Type: "v"},
+		{VariableId: "1", Name: "Currency", Type: "c"},
+	})
+
+	var ids []string
+	for i := range first.Variables {
+		if first.Variables[i] != second.Variables[i] {
+			t.Fatalf("expected the same order across reads, got %v and %v", first.Variables, second.Variables)
+		}
+		ids = append(ids, first.Variables[i].Id.ValueString())
+	}
+
+	if ids[0] != "1" || ids[1] != "7" || ids[2] != "3" {
+		t.Fatalf("expected variables ordered by name and then id, got %v", ids)
+	}
+}
+
+// Test that triggers are ordered by name
+func TestToDataSourceTriggers_stableOrder(t *testing.T) {
+	model := toDataSourceTriggers([]*tagmanager.Trigger{
+		{TriggerId: "2", Name: "Purchase", Type: "customEvent"},
+		{TriggerId: "9", Name: "All Pages", Type: "pageview"},
+	})
+
+	if model.Triggers[0].Name.ValueString() != "All Pages" || model.Triggers[1].Name.ValueString() != "Purchase" {
+		t.Fatalf("expected triggers ordered by name, got %v", model.Triggers)
+	}
+
+	if empty := toDataSourceTriggers(nil); empty.Triggers == nil {
+		t.Fatal("expected an empty list for a workspace without triggers")
+	}
+}
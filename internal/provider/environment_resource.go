@@ -0,0 +1,250 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ resource.Resource                = &environmentResource{}
+	_ resource.ResourceWithConfigure   = &environmentResource{}
+	_ resource.ResourceWithImportState = &environmentResource{}
+)
+
+type environmentResource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewEnvironmentResource() resource.Resource {
+	return &environmentResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *environmentResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.ClientInWorkspace)
+}
+
+// Metadata returns the resource type name.
+func (r *environmentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_environment"
+}
+
+var environmentResourceSchemaAttributes = map[string]schema.Attribute{
+	"name": schema.StringAttribute{
+		Description: "The name of the environment.",
+		Required:    true,
+	},
+	"id": schema.StringAttribute{
+		Description: "The ID of the environment.",
+		Computed:    true,
+	},
+	"description": schema.StringAttribute{
+		Description: "The description of the environment.",
+		Optional:    true,
+		Validators:  notesValidators,
+	},
+	"url": schema.StringAttribute{
+		Description: "The default preview page URL of the environment.",
+		Optional:    true,
+	},
+	"enable_debug": schema.BoolAttribute{
+		Description: "Whether debug mode is enabled by default when previewing the environment.",
+		Optional:    true,
+	},
+	"type": schema.StringAttribute{
+		Description: "The type of the environment. Environments created by the provider are user environments; latest, live and workspace environments are managed by GTM and can only be imported.",
+		Computed:    true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	},
+	"container_version_id": schema.StringAttribute{
+		Description: "The ID of the container version the environment serves. Only user environments can be pinned to a version. Changing it updates the environment in place, e.g. to promote a new version to staging.",
+		Optional:    true,
+	},
+}
+
+// Schema defines the schema for the resource.
+func (r *environmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{Attributes: environmentResourceSchemaAttributes}
+}
+
+type resourceEnvironmentModel struct {
+	Name               types.String `tfsdk:"name"`
+	Id                 types.String `tfsdk:"id"`
+	Description        types.String `tfsdk:"description"`
+	Url                types.String `tfsdk:"url"`
+	EnableDebug        types.Bool   `tfsdk:"enable_debug"`
+	Type               types.String `tfsdk:"type"`
+	ContainerVersionId types.String `tfsdk:"container_version_id"`
+}
+
+func toResourceEnvironment(environment *tagmanager.Environment, current resourceEnvironmentModel) resourceEnvironmentModel {
+	var enableDebug = types.BoolValue(environment.EnableDebug)
+	if current.EnableDebug.IsNull() && !environment.EnableDebug {
+		enableDebug = types.BoolNull()
+	}
+
+	return resourceEnvironmentModel{
+		Name:               types.StringValue(environment.Name),
+		Id:                 types.StringValue(environment.EnvironmentId),
+		Description:        nullableStringValue(environment.Description),
+		Url:                nullableStringValue(environment.Url),
+		EnableDebug:        enableDebug,
+		Type:               types.StringValue(environment.Type),
+		ContainerVersionId: nullableStringValue(environment.ContainerVersionId),
+	}
+}
+
+// userEnvironmentType is the type of the environments the provider creates.
+const userEnvironmentType = "user"
+
+// toApiEnvironment keeps the type of an imported environment, since GTM does not
+// change the type of an existing environment, and creates user environments otherwise.
+func toApiEnvironment(resource resourceEnvironmentModel) *tagmanager.Environment {
+	var environmentType = userEnvironmentType
+	if !resource.Type.IsNull() && !resource.Type.IsUnknown() {
+		environmentType = resource.Type.ValueString()
+	}
+
+	return &tagmanager.Environment{
+		Name:               resource.Name.ValueString(),
+		Description:        resource.Description.ValueString(),
+		Url:                resource.Url.ValueString(),
+		EnableDebug:        resource.EnableDebug.ValueBool(),
+		Type:               environmentType,
+		ContainerVersionId: resource.ContainerVersionId.ValueString(),
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *environmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan resourceEnvironmentModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	environment, err := r.client.CreateEnvironment(toApiEnvironment(plan))
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating Environment", err.Error())
+		return
+	}
+
+	diags = recordCreatedId(ctx, &resp.State, environment.EnvironmentId)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = types.StringValue(environment.EnvironmentId)
+	plan.Type = types.StringValue(environment.Type)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *environmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state resourceEnvironmentModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	environment, err := r.client.Environment(state.Id.ValueString())
+	if err == api.ErrNotExist {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Reading Environment", err.Error())
+		return
+	}
+
+	var resource = toResourceEnvironment(environment, state)
+
+	diags = resp.State.Set(ctx, &resource)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *environmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state resourceEnvironmentModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	environment, err := r.client.UpdateEnvironment(state.Id.ValueString(), toApiEnvironment(plan))
+	if err == nil {
+		err = checkEnvironmentVersion(environment, plan.ContainerVersionId)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating Environment", err.Error())
+		return
+	}
+
+	plan.Id = types.StringValue(environment.EnvironmentId)
+	plan.Type = types.StringValue(environment.Type)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// checkEnvironmentVersion reports an environment that does not serve the planned
+// container version after an update, e.g. a built-in environment GTM does not let pin.
+func checkEnvironmentVersion(environment *tagmanager.Environment, containerVersionId types.String) error {
+	if containerVersionId.IsNull() || containerVersionId.IsUnknown() || environment.ContainerVersionId == containerVersionId.ValueString() {
+		return nil
+	}
+
+	return fmt.Errorf("environment %s serves container version %q instead of %q. Only user environments can be pinned to a version",
+		environment.EnvironmentId, environment.ContainerVersionId, containerVersionId.ValueString())
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *environmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state resourceEnvironmentModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteEnvironment(state.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error Deleting Environment", err.Error())
+		return
+	}
+}
+
+func (r *environmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
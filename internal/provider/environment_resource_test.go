@@ -0,0 +1,249 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"terraform-provider-google-tag-manager/internal/api"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that container level resources work without a workspace_name
+func TestAccEnvironmentResource_withoutWorkspaceName(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEnvironmentResourceConfig("Created by Terraform"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gtm_environment.test", "id"),
+					resource.TestCheckResourceAttr("gtm_environment.test", "name", "tf-test-environment"),
+					resource.TestCheckResourceAttr("gtm_environment.test", "description", "Created by Terraform"),
+				),
+			},
+			{
+				Config: testAccEnvironmentResourceConfig("Updated by Terraform"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_environment.test", "description", "Updated by Terraform"),
+				),
+			},
+			{
+				ResourceName:      "gtm_environment.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// Test that a user environment can be pinned to a container version
+func TestAccEnvironmentResource_containerVersion(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfigWithoutWorkspace() + `
+data "gtm_latest_version" "test" {}
+
+resource "gtm_environment" "pinned" {
+  name                 = "tf-test-pinned-environment"
+  container_version_id = data.gtm_latest_version.test.id
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_environment.pinned", "type", "user"),
+					resource.TestCheckResourceAttrPair("gtm_environment.pinned", "container_version_id", "data.gtm_latest_version.test", "id"),
+				),
+			},
+			{
+				ResourceName:            "gtm_environment.pinned",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"enable_debug"},
+			},
+		},
+	})
+}
+
+// Test that changing the pinned version updates the environment in place, promoting
+// a newly created version
+func TestAccEnvironmentResource_updateContainerVersion(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEnvironmentResourcePinnedConfig("data.gtm_latest_version.test.id", ""),
+				Check:  resource.TestCheckResourceAttrPair("gtm_environment.promoted", "container_version_id", "data.gtm_latest_version.test", "id"),
+			},
+			{
+				Config: testAccEnvironmentResourcePinnedConfig("gtm_version.promoted.id", `
+resource "gtm_version" "promoted" {
+  name = "tf-test-promoted-version"
+}
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gtm_environment.promoted", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("gtm_environment.promoted", "container_version_id", "gtm_version.promoted", "id"),
+					testAccCheckEnvironmentVersion("gtm_environment.promoted", "gtm_version.promoted"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckEnvironmentVersion checks through the GTM API that the environment serves the version
+func testAccCheckEnvironmentVersion(environmentName string, versionName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		environmentState, ok := s.RootModule().Resources[environmentName]
+		if !ok {
+			return fmt.Errorf("Environment resource not found: %s", environmentName)
+		}
+
+		versionState, ok := s.RootModule().Resources[versionName]
+		if !ok {
+			return fmt.Errorf("Version resource not found: %s", versionName)
+		}
+
+		client, err := api.NewClientInWorkspaceFromEnv()
+		if err != nil {
+			return err
+		}
+
+		environment, err := client.Environment(environmentState.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if environment.ContainerVersionId != versionState.Primary.ID {
+			return fmt.Errorf("expected environment %s to serve version %s, got %s",
+				environment.EnvironmentId, versionState.Primary.ID, environment.ContainerVersionId)
+		}
+
+		return nil
+	}
+}
+
+// Test that an update leaving the environment on another version than planned is an error
+func TestCheckEnvironmentVersion(t *testing.T) {
+	environment := &tagmanager.Environment{EnvironmentId: "5", Type: "user", ContainerVersionId: "12"}
+
+	if err := checkEnvironmentVersion(environment, types.StringValue("12")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := checkEnvironmentVersion(environment, types.StringNull()); err != nil {
+		t.Fatalf("expected no error for an unpinned environment, got %v", err)
+	}
+
+	err := checkEnvironmentVersion(environment, types.StringValue("13"))
+	if err == nil || !strings.Contains(err.Error(), `serves container version "12" instead of "13"`) {
+		t.Fatalf("expected a version mismatch error, got %v", err)
+	}
+}
+
+// Test that new environments are user environments and imported ones keep their type
+func TestEnvironmentType(t *testing.T) {
+	planned := resourceEnvironmentModel{
+		Name:               types.StringValue("staging"),
+		Type:               types.StringUnknown(),
+		ContainerVersionId: types.StringValue("12"),
+	}
+
+	environment := toApiEnvironment(planned)
+	if environment.Type != "user" || environment.ContainerVersionId != "12" {
+		t.Fatalf("expected a user environment pinned to version 12, got %+v", environment)
+	}
+
+	environment.EnvironmentId = "5"
+	read := toResourceEnvironment(environment, planned)
+	if read.Type.ValueString() != "user" || read.ContainerVersionId.ValueString() != "12" {
+		t.Fatalf("expected type user and container_version_id 12, got %s and %s", read.Type, read.ContainerVersionId)
+	}
+
+	live := toResourceEnvironment(&tagmanager.Environment{EnvironmentId: "1", Name: "Live", Type: "live"}, resourceEnvironmentModel{})
+	if !live.ContainerVersionId.IsNull() {
+		t.Fatalf("expected no container_version_id, got %s", live.ContainerVersionId)
+	}
+
+	if environment := toApiEnvironment(live); environment.Type != "live" {
+		t.Fatalf("expected an imported live environment to keep its type, got %s", environment.Type)
+	}
+}
+
+// Test that workspace scoped resources still require a workspace_name
+func TestAccEnvironmentResource_workspaceResourceWithoutWorkspaceName(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfigWithoutWorkspace() + `
+resource "gtm_tag" "test" {
+  name = "tf-test-tag"
+  type = "html"
+}
+`,
+				ExpectError: regexp.MustCompile("Missing Workspace Name"),
+			},
+		},
+	})
+}
+
+func testAccProviderConfigWithoutWorkspace() string {
+	return fmt.Sprintf(`
+provider "gtm" {
+  credential_file = %q
+  account_id      = %q
+  container_id    = %q
+  retry_limit     = 15
+}
+`,
+		os.Getenv("GTM_CREDENTIAL_FILE"),
+		os.Getenv("GTM_ACCOUNT_ID"),
+		os.Getenv("GTM_CONTAINER_ID"),
+	)
+}
+
+func testAccEnvironmentResourcePinnedConfig(containerVersionId string, extra string) string {
+	return testAccProviderConfig() + `
+data "gtm_latest_version" "test" {}
+
+resource "gtm_environment" "promoted" {
+  name                 = "tf-test-promoted-environment"
+  container_version_id = ` + containerVersionId + `
+}
+` + extra
+}
+
+func testAccEnvironmentResourceConfig(description string) string {
+	return testAccProviderConfigWithoutWorkspace() + fmt.Sprintf(`
+resource "gtm_environment" "test" {
+  name         = "tf-test-environment"
+  description  = %q
+  url          = "https://staging.example.com"
+  enable_debug = true
+}
+`, description)
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ datasource.DataSource              = &environmentsDataSource{}
+	_ datasource.DataSourceWithConfigure = &environmentsDataSource{}
+)
+
+type environmentsDataSource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewEnvironmentsDataSource() datasource.DataSource {
+	return &environmentsDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *environmentsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.ClientInWorkspace)
+}
+
+// Metadata returns the data source type name.
+func (d *environmentsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_environments"
+}
+
+// Schema defines the schema for the data source.
+func (d *environmentsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the environments of the container, including the built-in live and latest environments.",
+		Attributes: map[string]schema.Attribute{
+			"environments": schema.ListNestedAttribute{
+				Description: "The environments of the container.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"environment_id": schema.StringAttribute{
+							Description: "The ID of the environment.",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "The name of the environment.",
+							Computed:    true,
+						},
+						"type": schema.StringAttribute{
+							Description: "The type of the environment: user, live, latest or workspace.",
+							Computed:    true,
+						},
+						"url": schema.StringAttribute{
+							Description: "The default preview page url of the environment.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type dataSourceEnvironmentsModel struct {
+	Environments []dataSourceEnvironmentModel `tfsdk:"environments"`
+}
+
+type dataSourceEnvironmentModel struct {
+	EnvironmentId types.String `tfsdk:"environment_id"`
+	Name          types.String `tfsdk:"name"`
+	Type          types.String `tfsdk:"type"`
+	Url           types.String `tfsdk:"url"`
+}
+
+func toDataSourceEnvironments(environments []*tagmanager.Environment) dataSourceEnvironmentsModel {
+	var model = dataSourceEnvironmentsModel{Environments: make([]dataSourceEnvironmentModel, 0, len(environments))}
+
+	for _, environment := range environments {
+		model.Environments = append(model.Environments, dataSourceEnvironmentModel{
+			EnvironmentId: types.StringValue(environment.EnvironmentId),
+			Name:          nullableStringValue(environment.Name),
+			Type:          nullableStringValue(environment.Type),
+			Url:           nullableStringValue(environment.Url),
+		})
+	}
+
+	return model
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *environmentsDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	environments, err := d.client.ListEnvironments()
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Environments", err.Error())
+		return
+	}
+
+	var state = toDataSourceEnvironments(environments)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"testing"
+
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that every listed environment is mapped, with unset fields read as null
+func TestToDataSourceEnvironments(t *testing.T) {
+	model := toDataSourceEnvironments([]*tagmanager.Environment{
+		{EnvironmentId: "1", Name: "Live", Type: "live"},
+		{EnvironmentId: "2", Name: "Latest", Type: "latest"},
+		{EnvironmentId: "5", Name: "Staging", Type: "user", Url: "https://staging.example.com"},
+	})
+
+	if len(model.Environments) != 3 {
+		t.Fatalf("expected 3 environments, got %d", len(model.Environments))
+	}
+
+	staging := model.Environments[2]
+	if staging.EnvironmentId.ValueString() != "5" || staging.Name.ValueString() != "Staging" ||
+		staging.Type.ValueString() != "user" || staging.Url.ValueString() != "https://staging.example.com" {
+		t.Fatalf("unexpected environment: %v", staging)
+	}
+
+	if !model.Environments[0].Url.IsNull() {
+		t.Fatalf("expected an unset url to be null, got %v", model.Environments[0].Url)
+	}
+
+	if empty := toDataSourceEnvironments(nil); empty.Environments == nil || len(empty.Environments) != 0 {
+		t.Fatalf("expected an empty list for a container without environments, got %v", empty.Environments)
+	}
+}
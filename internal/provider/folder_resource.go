@@ -0,0 +1,345 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-google-tag-manager/internal/api"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ resource.Resource                = &folderResource{}
+	_ resource.ResourceWithConfigure   = &folderResource{}
+	_ resource.ResourceWithImportState = &folderResource{}
+)
+
+type folderResource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewFolderResource() resource.Resource {
+	return &folderResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *folderResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(r.client, &resp.Diagnostics)
+}
+
+// Metadata returns the resource type name.
+func (r *folderResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_folder"
+}
+
+var folderResourceSchemaAttributes = map[string]schema.Attribute{
+	"name": schema.StringAttribute{
+		Description: "The name of the folder.",
+		Required:    true,
+	},
+	"id": schema.StringAttribute{
+		Description: "The ID of the folder.",
+		Computed:    true,
+	},
+	"notes": schema.StringAttribute{
+		Description: "The notes of the folder.",
+		Optional:    true,
+		Validators:  notesValidators,
+	},
+	"tag_ids": schema.SetAttribute{
+		Description: "The IDs of the tags in the folder. Tags removed from the set are moved out of the folder. Leave unset to not manage the tags of the folder.",
+		Optional:    true,
+		ElementType: types.StringType,
+	},
+	"trigger_ids": schema.SetAttribute{
+		Description: "The IDs of the triggers in the folder. Triggers removed from the set are moved out of the folder. Leave unset to not manage the triggers of the folder.",
+		Optional:    true,
+		ElementType: types.StringType,
+	},
+	"variable_ids": schema.SetAttribute{
+		Description: "The IDs of the variables in the folder. Variables removed from the set are moved out of the folder. Leave unset to not manage the variables of the folder.",
+		Optional:    true,
+		ElementType: types.StringType,
+	},
+}
+
+// Schema defines the schema for the resource.
+func (r *folderResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{Attributes: folderResourceSchemaAttributes}
+}
+
+type resourceFolderModel struct {
+	Name        types.String   `tfsdk:"name"`
+	Id          types.String   `tfsdk:"id"`
+	Notes       types.String   `tfsdk:"notes"`
+	TagIds      []types.String `tfsdk:"tag_ids"`
+	TriggerIds  []types.String `tfsdk:"trigger_ids"`
+	VariableIds []types.String `tfsdk:"variable_ids"`
+}
+
+func toApiFolder(resource resourceFolderModel) *tagmanager.Folder {
+	return &tagmanager.Folder{
+		Name:  resource.Name.ValueString(),
+		Notes: resource.Notes.ValueString(),
+	}
+}
+
+// folderMembership returns the ids found in the folder when the membership is
+// managed, and null otherwise so that an unmanaged membership shows no diff.
+func folderMembership(ids []string, managed []types.String) []types.String {
+	if managed == nil {
+		return nil
+	}
+
+	var membership = make([]types.String, 0, len(ids))
+	for _, id := range ids {
+		membership = append(membership, types.StringValue(id))
+	}
+
+	return membership
+}
+
+// missingIds returns the values of list that are not in other.
+func missingIds(list []types.String, other []types.String) []string {
+	var missing []string
+
+	for _, v := range list {
+		found := false
+		for _, o := range other {
+			if v.Equal(o) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, v.ValueString())
+		}
+	}
+
+	return missing
+}
+
+// membershipChanges returns the ids to move into and out of the folder. A membership
+// that is not managed in the plan is left as it is.
+func membershipChanges(planned []types.String, current []types.String) ([]string, []string) {
+	if planned == nil {
+		return nil, nil
+	}
+
+	return missingIds(planned, current), missingIds(current, planned)
+}
+
+// reconcileMembership moves the entities added to the plan into the folder and the
+// ones removed from it back to the root folder.
+func (r *folderResource) reconcileMembership(folderId string, plan resourceFolderModel, state resourceFolderModel) error {
+	tagsIn, tagsOut := membershipChanges(plan.TagIds, state.TagIds)
+	triggersIn, triggersOut := membershipChanges(plan.TriggerIds, state.TriggerIds)
+	variablesIn, variablesOut := membershipChanges(plan.VariableIds, state.VariableIds)
+
+	if err := r.client.MoveEntitiesToFolder(api.RootFolderId, tagsOut, triggersOut, variablesOut); err != nil {
+		return err
+	}
+
+	return r.client.MoveEntitiesToFolder(folderId, tagsIn, triggersIn, variablesIn)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *folderResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan resourceFolderModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dto := toApiFolder(plan)
+	dto.Notes = withManagedByNote(managedByNote(r.client.Options, "folder", time.Now()), dto.Notes)
+
+	folder, err := r.client.CreateFolder(dto)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating Folder", err.Error())
+		return
+	}
+
+	diags = recordCreatedId(ctx, &resp.State, folder.FolderId)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.reconcileMembership(folder.FolderId, plan, resourceFolderModel{}); err != nil {
+		resp.Diagnostics.AddError("Error Moving Entities To Folder", err.Error())
+		return
+	}
+
+	plan.Id = types.StringValue(folder.FolderId)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *folderResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state resourceFolderModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	folder, err := r.client.Folder(state.Id.ValueString())
+	if err == api.ErrNotExist {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Reading Folder", err.Error())
+		return
+	}
+
+	entities, err := r.client.FolderEntities(folder.FolderId)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Folder Entities", err.Error())
+		return
+	}
+
+	var tagIds, triggerIds, variableIds []string
+	for _, tag := range entities.Tag {
+		tagIds = append(tagIds, tag.TagId)
+	}
+	for _, trigger := range entities.Trigger {
+		triggerIds = append(triggerIds, trigger.TriggerId)
+	}
+	for _, variable := range entities.Variable {
+		variableIds = append(variableIds, variable.VariableId)
+	}
+
+	var resource = resourceFolderModel{
+		Name:        types.StringValue(folder.Name),
+		Id:          types.StringValue(folder.FolderId),
+		Notes:       withoutManagedByNote(remoteManagedByNote(r.client.Options, "folder", folder.Notes), folder.Notes, state.Notes),
+		TagIds:      folderMembership(tagIds, state.TagIds),
+		TriggerIds:  folderMembership(triggerIds, state.TriggerIds),
+		VariableIds: folderMembership(variableIds, state.VariableIds),
+	}
+
+	diags = resp.State.Set(ctx, &resource)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *folderResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state resourceFolderModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dto := toApiFolder(plan)
+	dto.Notes = withManagedByNote(managedByNote(r.client.Options, "folder", time.Now()), dto.Notes)
+
+	folder, err := r.client.UpdateFolder(state.Id.ValueString(), dto)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating Folder", err.Error())
+		return
+	}
+
+	if err := r.reconcileMembership(folder.FolderId, plan, state); err != nil {
+		resp.Diagnostics.AddError("Error Moving Entities To Folder", err.Error())
+		return
+	}
+
+	plan.Id = types.StringValue(folder.FolderId)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *folderResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state resourceFolderModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteFolder(state.Id.ValueString())
+	if err == api.ErrNotExist {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Deleting Folder", err.Error())
+		return
+	}
+}
+
+// ImportState imports the folder by its id, its full GTM path, or its name prefixed
+// with "name:", e.g. name:Analytics.
+func (r *folderResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var id string
+	var err error
+
+	if name, ok := strings.CutPrefix(req.ID, nameImportPrefix); ok {
+		var folders []*tagmanager.Folder
+		folders, err = r.client.ListFolders()
+		if err == nil {
+			id, err = folderIdByName(folders, name)
+		}
+	} else {
+		id, err = workspaceImportId(r.client, "folders", req.ID)
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("id"), "Error Importing Folder", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// folderIdByName returns the id of the folder named name. GTM does not keep folder
+// names unique, so when several folders match the import cannot pick one and they are
+// listed with their ids.
+func folderIdByName(folders []*tagmanager.Folder, name string) (string, error) {
+	var matches []string
+	var id string
+
+	for _, folder := range folders {
+		if folder.Name == name {
+			id = folder.FolderId
+			matches = append(matches, folder.FolderId)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no folder named %q exists in the workspace", name)
+	case 1:
+		return id, nil
+	default:
+		return "", fmt.Errorf("%d folders are named %q, with ids %s. Import the folder by its id instead",
+			len(matches), name, strings.Join(matches, ", "))
+	}
+}
@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that tags are moved in and out of the folder as the set changes
+func TestAccFolderResource_membership(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFolderResourceConfig("[gtm_tag.in_folder.id]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gtm_folder.test", "id"),
+					resource.TestCheckResourceAttr("gtm_folder.test", "tag_ids.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair("gtm_folder.test", "tag_ids.*", "gtm_tag.in_folder", "id"),
+				),
+			},
+			{
+				Config: testAccFolderResourceConfig("[]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_folder.test", "tag_ids.#", "0"),
+				),
+			},
+			{
+				ResourceName:            "gtm_folder.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"tag_ids"},
+			},
+		},
+	})
+}
+
+// Test that only the ids that changed are moved
+func TestFolderMembershipChanges(t *testing.T) {
+	current := []types.String{types.StringValue("1"), types.StringValue("2")}
+	planned := []types.String{types.StringValue("2"), types.StringValue("3")}
+
+	in, out := membershipChanges(planned, current)
+	if len(in) != 1 || in[0] != "3" {
+		t.Fatalf("expected 3 to be moved in, got %v", in)
+	}
+	if len(out) != 1 || out[0] != "1" {
+		t.Fatalf("expected 1 to be moved out, got %v", out)
+	}
+
+	in, out = membershipChanges(nil, current)
+	if in != nil || out != nil {
+		t.Fatalf("expected an unmanaged membership to be left alone, got %v and %v", in, out)
+	}
+
+	if membership := folderMembership([]string{"1"}, nil); membership != nil {
+		t.Fatalf("expected an unmanaged membership to stay null, got %v", membership)
+	}
+
+	if membership := folderMembership(nil, []types.String{}); membership == nil || len(membership) != 0 {
+		t.Fatalf("expected an empty managed membership, got %v", membership)
+	}
+}
+
+func testAccFolderResourceConfig(tagIds string) string {
+	return testAccProviderConfig() + `
+resource "gtm_tag" "in_folder" {
+  name = "tf-test-folder-tag"
+  type = "gaawe"
+
+  parameter = [
+    {
+      key   = "eventName"
+      type  = "template"
+      value = "folder_event"
+    },
+    {
+      key   = "measurementIdOverride"
+      type  = "template"
+      value = "G-XXXXXX"
+    }
+  ]
+}
+
+resource "gtm_folder" "test" {
+  name    = "tf-test-folder"
+  notes   = "Managed by Terraform"
+  tag_ids = ` + tagIds + `
+}
+`
+}
+
+// Test that a folder is imported by its name, and that a missing or ambiguous name is reported
+func TestFolderIdByName(t *testing.T) {
+	folders := []*tagmanager.Folder{
+		{FolderId: "1", Name: "Analytics"},
+		{FolderId: "2", Name: "Marketing"},
+		{FolderId: "3", Name: "Marketing"},
+	}
+
+	if id, err := folderIdByName(folders, "Analytics"); err != nil || id != "1" {
+		t.Fatalf("expected folder 1, got %q and %v", id, err)
+	}
+
+	if _, err := folderIdByName(folders, "Ads"); err == nil || !strings.Contains(err.Error(), `no folder named "Ads"`) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+
+	if _, err := folderIdByName(folders, "Marketing"); err == nil || !strings.Contains(err.Error(), "ids 2, 3") {
+		t.Fatalf("expected an ambiguity error listing both folders, got %v", err)
+	}
+}
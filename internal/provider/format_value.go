@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var formatValueSchema = schema.SingleNestedAttribute{
+	Description: "Formatting applied to the value of the variable. The converted values may be literals or variable references, e.g. {{Default}}.",
+	Optional:    true,
+	Attributes: map[string]schema.Attribute{
+		"case_conversion_type": schema.StringAttribute{
+			Description: "Converts a string value to lowercase or uppercase. One of none, lowercase or uppercase.",
+			Optional:    true,
+			Validators:  []validator.String{stringvalidator.OneOf("none", "lowercase", "uppercase")},
+		},
+		"convert_null_to_value": schema.StringAttribute{
+			Description: "The value used when the variable value is null.",
+			Optional:    true,
+		},
+		"convert_undefined_to_value": schema.StringAttribute{
+			Description: "The value used when the variable value is undefined.",
+			Optional:    true,
+		},
+		"convert_true_to_value": schema.StringAttribute{
+			Description: "The value used when the variable value is true.",
+			Optional:    true,
+		},
+		"convert_false_to_value": schema.StringAttribute{
+			Description: "The value used when the variable value is false.",
+			Optional:    true,
+		},
+	},
+}
+
+type ResourceFormatValueModel struct {
+	CaseConversionType      types.String `tfsdk:"case_conversion_type"`
+	ConvertNullToValue      types.String `tfsdk:"convert_null_to_value"`
+	ConvertUndefinedToValue types.String `tfsdk:"convert_undefined_to_value"`
+	ConvertTrueToValue      types.String `tfsdk:"convert_true_to_value"`
+	ConvertFalseToValue     types.String `tfsdk:"convert_false_to_value"`
+}
+
+// toApiConvertedValue returns the template parameter GTM stores a converted value in.
+func toApiConvertedValue(value types.String) *tagmanager.Parameter {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+
+	return &tagmanager.Parameter{Type: "template", Value: value.ValueString()}
+}
+
+func toResourceConvertedValue(parameter *tagmanager.Parameter) types.String {
+	if parameter == nil {
+		return types.StringNull()
+	}
+
+	return types.StringValue(parameter.Value)
+}
+
+func toApiFormatValue(format *ResourceFormatValueModel) *tagmanager.VariableFormatValue {
+	if format == nil {
+		return nil
+	}
+
+	return &tagmanager.VariableFormatValue{
+		CaseConversionType:      format.CaseConversionType.ValueString(),
+		ConvertNullToValue:      toApiConvertedValue(format.ConvertNullToValue),
+		ConvertUndefinedToValue: toApiConvertedValue(format.ConvertUndefinedToValue),
+		ConvertTrueToValue:      toApiConvertedValue(format.ConvertTrueToValue),
+		ConvertFalseToValue:     toApiConvertedValue(format.ConvertFalseToValue),
+	}
+}
+
+func toResourceFormatValue(format *tagmanager.VariableFormatValue) *ResourceFormatValueModel {
+	if format == nil {
+		return nil
+	}
+
+	return &ResourceFormatValueModel{
+		CaseConversionType:      nullableStringValue(format.CaseConversionType),
+		ConvertNullToValue:      toResourceConvertedValue(format.ConvertNullToValue),
+		ConvertUndefinedToValue: toResourceConvertedValue(format.ConvertUndefinedToValue),
+		ConvertTrueToValue:      toResourceConvertedValue(format.ConvertTrueToValue),
+		ConvertFalseToValue:     toResourceConvertedValue(format.ConvertFalseToValue),
+	}
+}
+
+// Equal compares the format value model with the given format value model
+func (m *ResourceFormatValueModel) Equal(o *ResourceFormatValueModel) bool {
+	if m == nil || o == nil {
+		return m == o
+	}
+
+	return m.CaseConversionType.Equal(o.CaseConversionType) &&
+		m.ConvertNullToValue.Equal(o.ConvertNullToValue) &&
+		m.ConvertUndefinedToValue.Equal(o.ConvertUndefinedToValue) &&
+		m.ConvertTrueToValue.Equal(o.ConvertTrueToValue) &&
+		m.ConvertFalseToValue.Equal(o.ConvertFalseToValue)
+}
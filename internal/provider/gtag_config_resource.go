@@ -0,0 +1,203 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ resource.Resource                = &gtagConfigResource{}
+	_ resource.ResourceWithConfigure   = &gtagConfigResource{}
+	_ resource.ResourceWithImportState = &gtagConfigResource{}
+)
+
+type gtagConfigResource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewGtagConfigResource() resource.Resource {
+	return &gtagConfigResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *gtagConfigResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(r.client, &resp.Diagnostics)
+}
+
+// Metadata returns the resource type name.
+func (r *gtagConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_gtag_config"
+}
+
+var gtagConfigResourceSchemaAttributes = withWorkspaceEntityAttributes(map[string]schema.Attribute{
+	"type": schema.StringAttribute{
+		Description: "The type of the Google tag config, e.g. googtag.",
+		Required:    true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	},
+	"id": schema.StringAttribute{
+		Description: "The ID of the Google tag config.",
+		Computed:    true,
+	},
+	"parameter": parameterSchema,
+})
+
+// Schema defines the schema for the resource.
+func (r *gtagConfigResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{Attributes: gtagConfigResourceSchemaAttributes}
+}
+
+type resourceGtagConfigModel struct {
+	Type      types.String             `tfsdk:"type"`
+	Id        types.String             `tfsdk:"id"`
+	Parameter []ResourceParameterModel `tfsdk:"parameter"`
+	workspaceEntityModel
+}
+
+func toApiGtagConfig(resource resourceGtagConfigModel) *tagmanager.GtagConfig {
+	return &tagmanager.GtagConfig{
+		Type:      resource.Type.ValueString(),
+		Parameter: toApiParameter(resource.Parameter),
+	}
+}
+
+func toResourceGtagConfig(gtagConfig *tagmanager.GtagConfig, client *api.ClientInWorkspace) resourceGtagConfigModel {
+	return resourceGtagConfigModel{
+		Type:                 types.StringValue(gtagConfig.Type),
+		Id:                   types.StringValue(gtagConfig.GtagConfigId),
+		Parameter:            toResourceParameter(gtagConfig.Parameter),
+		workspaceEntityModel: workspaceEntityLocation(client, "gtag_config", gtagConfig.GtagConfigId),
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *gtagConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan resourceGtagConfigModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	gtagConfig, err := r.client.CreateGtagConfig(toApiGtagConfig(plan))
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating Google Tag Config", err.Error())
+		return
+	}
+
+	diags = recordCreatedId(ctx, &resp.State, gtagConfig.GtagConfigId)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = types.StringValue(gtagConfig.GtagConfigId)
+	plan.workspaceEntityModel = workspaceEntityLocation(r.client, "gtag_config", gtagConfig.GtagConfigId)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *gtagConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state resourceGtagConfigModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	gtagConfig, err := r.client.GtagConfig(state.Id.ValueString())
+	if err == api.ErrNotExist {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Reading Google Tag Config", err.Error())
+		return
+	}
+
+	var resource = toResourceGtagConfig(gtagConfig, r.client)
+	resource.Parameter = alignParameterOrder(resource.Parameter, state.Parameter)
+	resource.Parameter = keepEmptyValues(resource.Parameter, state.Parameter)
+	resource.Parameter = keepSensitiveValues(resource.Parameter, state.Parameter)
+
+	diags = resp.State.Set(ctx, &resource)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *gtagConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state resourceGtagConfigModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	gtagConfig, err := r.client.UpdateGtagConfig(state.Id.ValueString(), toApiGtagConfig(plan))
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating Google Tag Config", err.Error())
+		return
+	}
+
+	plan.Id = types.StringValue(gtagConfig.GtagConfigId)
+	plan.workspaceEntityModel = workspaceEntityLocation(r.client, "gtag_config", gtagConfig.GtagConfigId)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *gtagConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state resourceGtagConfigModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteGtagConfig(state.Id.ValueString())
+	if err == api.ErrNotExist {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Deleting Google Tag Config", err.Error())
+		return
+	}
+}
+
+// ImportState accepts the Google tag config id or its full GTM path.
+func (r *gtagConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, err := workspaceImportId(r.client, "gtag_config", req.ID)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("id"), "Error Importing Google Tag Config", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
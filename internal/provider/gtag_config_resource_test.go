@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testSettingsTable(key string, settings ...[2]string) ResourceParameterModel {
+	var list []ResourceParameterModel
+	for _, setting := range settings {
+		list = append(list, ResourceParameterModel{
+			Type: types.StringValue("map"),
+			Map:  []ResourceParameterModel{testParameter("parameter", setting[0]), testParameter("parameterValue", setting[1])},
+		})
+	}
+
+	return ResourceParameterModel{Key: types.StringValue(key), Type: types.StringValue("list"), List: list}
+}
+
+// Test that config and event settings round-trip through GTM, including the nested maps
+func TestGtagConfig_roundTrip(t *testing.T) {
+	planned := resourceGtagConfigModel{
+		Type: types.StringValue("googtag"),
+		Parameter: []ResourceParameterModel{
+			testParameter("tagId", "G-ABC123XYZ9"),
+			testSettingsTable("configSettingsTable", [2]string{"send_page_view", "false"}, [2]string{"cookie_domain", "auto"}),
+			testSettingsTable("eventSettingsTable", [2]string{"user_type", "member"}),
+		},
+	}
+
+	gtagConfig := toApiGtagConfig(planned)
+	gtagConfig.GtagConfigId = "12"
+
+	if gtagConfig.Parameter[1].List[1].Map[0].Value != "cookie_domain" {
+		t.Fatalf("expected the nested config settings to be sent, got %+v", gtagConfig.Parameter[1].List[1])
+	}
+
+	remote := toResourceGtagConfig(gtagConfig, testClientInWorkspace())
+
+	if remote.Id.ValueString() != "12" || remote.Path.ValueString() != "accounts/1/containers/2/workspaces/3/gtag_config/12" {
+		t.Fatalf("unexpected id or path: %s, %s", remote.Id, remote.Path)
+	}
+
+	if !remote.Type.Equal(planned.Type) || len(remote.Parameter) != len(planned.Parameter) {
+		t.Fatalf("expected the config to round-trip, got %v", remote)
+	}
+
+	for i := range planned.Parameter {
+		if !remote.Parameter[i].Equal(planned.Parameter[i]) {
+			t.Fatalf("expected parameter %d to round-trip, got %v", i, remote.Parameter[i])
+		}
+	}
+}
+
+// Test a Google tag config with a couple of config settings
+func TestAccGtagConfigResource_configSettings(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGtagConfigResourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gtm_gtag_config.test", "id"),
+					resource.TestCheckResourceAttr("gtm_gtag_config.test", "type", "googtag"),
+					resource.TestCheckResourceAttr("gtm_gtag_config.test", "parameter.1.key", "configSettingsTable"),
+					resource.TestCheckResourceAttr("gtm_gtag_config.test", "parameter.1.list.#", "2"),
+					resource.TestCheckResourceAttr("gtm_gtag_config.test", "parameter.1.list.1.map.0.value", "cookie_domain"),
+				),
+			},
+			{
+				Config:   testAccGtagConfigResourceConfig(),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      "gtm_gtag_config.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccGtagConfigResourceConfig() string {
+	return testAccProviderConfig() + `
+resource "gtm_gtag_config" "test" {
+  type = "googtag"
+
+  parameter = [
+    {
+      key   = "tagId"
+      type  = "template"
+      value = "G-TFTEST0001"
+    },
+    {
+      key  = "configSettingsTable"
+      type = "list"
+      list = [
+        {
+          type = "map"
+          map = [
+            { key = "parameter", type = "template", value = "send_page_view" },
+            { key = "parameterValue", type = "template", value = "false" },
+          ]
+        },
+        {
+          type = "map"
+          map = [
+            { key = "parameter", type = "template", value = "cookie_domain" },
+            { key = "parameterValue", type = "template", value = "auto" },
+          ]
+        },
+      ]
+    },
+  ]
+}
+`
+}
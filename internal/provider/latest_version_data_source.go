@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ datasource.DataSource              = &latestVersionDataSource{}
+	_ datasource.DataSourceWithConfigure = &latestVersionDataSource{}
+)
+
+type latestVersionDataSource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewLatestVersionDataSource() datasource.DataSource {
+	return &latestVersionDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *latestVersionDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.ClientInWorkspace)
+}
+
+// Metadata returns the data source type name.
+func (d *latestVersionDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_latest_version"
+}
+
+// Schema defines the schema for the data source.
+func (d *latestVersionDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up the most recently created version of the container.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the latest container version.",
+				Computed:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the latest container version.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type dataSourceLatestVersionModel struct {
+	Id   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+}
+
+func toDataSourceLatestVersion(header *tagmanager.ContainerVersionHeader) dataSourceLatestVersionModel {
+	return dataSourceLatestVersionModel{
+		Id:   types.StringValue(header.ContainerVersionId),
+		Name: nullableStringValue(header.Name),
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *latestVersionDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	header, err := d.client.LatestVersionHeader()
+	if err == api.ErrNotExist {
+		resp.Diagnostics.AddError("Error Reading Latest Version", "The container has no versions yet.")
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Reading Latest Version", err.Error())
+		return
+	}
+
+	var state = toDataSourceLatestVersion(header)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
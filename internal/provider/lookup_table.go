@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The parameters of Lookup Table (smm) and RegEx Table (remm) variables holding the
+// input value and the rows of the table.
+const (
+	lookupTableInputKey = "input"
+	lookupTableMapKey   = "map"
+)
+
+// lookupTableVariableTypes are the variable types that input and row compile to.
+var lookupTableVariableTypes = []string{"smm", "remm"}
+
+var lookupTableInputSchema = schema.StringAttribute{
+	Description: "The value looked up in the rows, e.g. {{Page Path}}. Only supported on smm and remm variables, where it is compiled to the input parameter.",
+	Optional:    true,
+	Validators:  []validator.String{stringvalidator.AlsoRequires(path.MatchRoot("row"))},
+}
+
+var lookupTableRowSchema = schema.ListNestedAttribute{
+	Description: "The rows of the lookup table, matched in order. Only supported on smm and remm variables, where it is compiled to the map parameter.",
+	Optional:    true,
+	Validators: []validator.List{
+		listvalidator.SizeAtLeast(1),
+		listvalidator.AlsoRequires(path.MatchRoot("input")),
+	},
+	NestedObject: schema.NestedAttributeObject{
+		Attributes: map[string]schema.Attribute{
+			"case": schema.StringAttribute{
+				Description: "The value matched against the input, a regular expression on remm variables.",
+				Required:    true},
+			"value": schema.StringAttribute{
+				Description: "The value of the variable when the row matches.",
+				Required:    true},
+		},
+	},
+}
+
+type ResourceLookupTableRowModel struct {
+	Case  types.String `tfsdk:"case"`
+	Value types.String `tfsdk:"value"`
+}
+
+// isLookupTableVariableType reports whether variables of variableType hold a lookup table.
+func isLookupTableVariableType(variableType string) bool {
+	for _, t := range lookupTableVariableTypes {
+		if t == variableType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compileLookupTable returns the input parameter and the map parameter, a list of
+// key/value maps, for the given input and rows.
+func compileLookupTable(input types.String, rows []ResourceLookupTableRowModel) []ResourceParameterModel {
+	var list = make([]ResourceParameterModel, 0, len(rows))
+
+	for _, row := range rows {
+		list = append(list, ResourceParameterModel{
+			Type: types.StringValue("map"),
+			Map: []ResourceParameterModel{
+				{Key: types.StringValue("key"), Type: types.StringValue("template"), Value: row.Case},
+				{Key: types.StringValue("value"), Type: types.StringValue("template"), Value: row.Value},
+			},
+		})
+	}
+
+	return []ResourceParameterModel{
+		{Key: types.StringValue(lookupTableInputKey), Type: types.StringValue("template"), Value: input},
+		{Key: types.StringValue(lookupTableMapKey), Type: types.StringValue("list"), List: list},
+	}
+}
+
+// decompileLookupTable moves the input and map parameters out of parameter into an
+// input and rows. The parameters are returned unchanged when either is missing or
+// they do not have the shape compileLookupTable produces.
+func decompileLookupTable(parameter []ResourceParameterModel) ([]ResourceParameterModel, types.String, []ResourceLookupTableRowModel) {
+	var input = types.StringNull()
+	var rows []ResourceLookupTableRowModel
+	var rest []ResourceParameterModel
+
+	for _, p := range parameter {
+		switch p.Key.ValueString() {
+		case lookupTableInputKey:
+			if p.Type.ValueString() != "template" {
+				return parameter, types.StringNull(), nil
+			}
+			input = types.StringValue(p.Value.ValueString())
+		case lookupTableMapKey:
+			var ok bool
+			if rows, ok = toLookupTableRows(p); !ok {
+				return parameter, types.StringNull(), nil
+			}
+		default:
+			rest = append(rest, p)
+		}
+	}
+
+	if input.IsNull() || rows == nil {
+		return parameter, types.StringNull(), nil
+	}
+
+	return rest, input, rows
+}
+
+func toLookupTableRows(parameter ResourceParameterModel) ([]ResourceLookupTableRowModel, bool) {
+	if parameter.Type.ValueString() != "list" || len(parameter.List) == 0 {
+		return nil, false
+	}
+
+	var rows = make([]ResourceLookupTableRowModel, 0, len(parameter.List))
+
+	for _, entry := range parameter.List {
+		if entry.Type.ValueString() != "map" || len(entry.Map) != 2 {
+			return nil, false
+		}
+
+		var row ResourceLookupTableRowModel
+		var hasCase, hasValue bool
+		for _, field := range entry.Map {
+			switch field.Key.ValueString() {
+			case "key":
+				row.Case, hasCase = types.StringValue(field.Value.ValueString()), true
+			case "value":
+				row.Value, hasValue = types.StringValue(field.Value.ValueString()), true
+			}
+		}
+
+		if !hasCase || !hasValue {
+			return nil, false
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, true
+}
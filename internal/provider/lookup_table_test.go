@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Test that a lookup table variable compiles its rows to the nested map parameter and
+// decompiles them on read
+func TestLookupTable_roundTrip(t *testing.T) {
+	rows := []ResourceLookupTableRowModel{
+		{Case: types.StringValue("/checkout"), Value: types.StringValue("checkout")},
+		{Case: types.StringValue("/cart"), Value: types.StringValue("cart")},
+	}
+
+	planned := resourceVariableModel{
+		Name:      types.StringValue("page type"),
+		Type:      types.StringValue("smm"),
+		Parameter: []ResourceParameterModel{testParameter("defaultValue", "other")},
+		Input:     types.StringValue("{{Page Path}}"),
+		Row:       rows,
+	}
+
+	variable := toApiVariable(planned, false)
+
+	manual := toApiParameter([]ResourceParameterModel{
+		testParameter("defaultValue", "other"),
+		testParameter("input", "{{Page Path}}"),
+		{
+			Key:  types.StringValue("map"),
+			Type: types.StringValue("list"),
+			List: []ResourceParameterModel{
+				{
+					Type: types.StringValue("map"),
+					Map:  []ResourceParameterModel{testParameter("key", "/checkout"), testParameter("value", "checkout")},
+				},
+				{
+					Type: types.StringValue("map"),
+					Map:  []ResourceParameterModel{testParameter("key", "/cart"), testParameter("value", "cart")},
+				},
+			},
+		},
+	})
+
+	if !reflect.DeepEqual(variable.Parameter, manual) {
+		t.Fatalf("expected the compiled parameters to match the manual ones, got %+v", variable.Parameter)
+	}
+
+	parameter, input, decompiled := decompileLookupTable(toResourceParameter(variable.Parameter))
+
+	if len(parameter) != 1 || parameter[0].Key.ValueString() != "defaultValue" {
+		t.Fatalf("expected only defaultValue to remain, got %+v", parameter)
+	}
+
+	if input.ValueString() != "{{Page Path}}" || !reflect.DeepEqual(decompiled, rows) {
+		t.Fatalf("expected %+v, got %s and %+v", rows, input, decompiled)
+	}
+
+	// Without an input the map parameter is left as a raw parameter.
+	parameter, input, decompiled = decompileLookupTable(toResourceParameter(manual[2:]))
+	if len(parameter) != 1 || !input.IsNull() || decompiled != nil {
+		t.Fatalf("expected the parameter to be kept, got %+v, %s and %+v", parameter, input, decompiled)
+	}
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var monitoringMetadataSchema = schema.MapAttribute{
+	Description: "Key-value pairs of metadata included in the event data of the tag for tag monitoring, e.g. by server containers. Values may reference variables, e.g. {{Page URL}}.",
+	Optional:    true,
+	ElementType: types.StringType,
+}
+
+var monitoringMetadataTagNameKeySchema = schema.StringAttribute{
+	Description: "The key under which the name of the tag is added to the monitoring metadata. The name is not added when unset.",
+	Optional:    true,
+}
+
+// compileMonitoringMetadata returns the map parameter GTM stores the monitoring
+// metadata in, with its entries ordered by key. Metadata without entries is omitted.
+func compileMonitoringMetadata(metadata map[string]types.String) *tagmanager.Parameter {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	var keys = make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var entries = make([]*tagmanager.Parameter, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, &tagmanager.Parameter{Key: key, Type: "template", Value: metadata[key].ValueString()})
+	}
+
+	return &tagmanager.Parameter{Type: "map", Map: entries}
+}
+
+// decompileMonitoringMetadata reads the monitoring metadata map parameter back into
+// its key-value pairs. GTM returns an empty map for tags without metadata, which is
+// read as null like metadata that is not configured.
+func decompileMonitoringMetadata(parameter *tagmanager.Parameter) map[string]types.String {
+	if parameter == nil || len(parameter.Map) == 0 {
+		return nil
+	}
+
+	var metadata = make(map[string]types.String, len(parameter.Map))
+	for _, entry := range parameter.Map {
+		metadata[entry.Key] = types.StringValue(entry.Value)
+	}
+
+	return metadata
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that monitoring_metadata compiles to a map parameter ordered by key and reads
+// back to the same pairs, whatever the order GTM returns the entries in
+func TestMonitoringMetadata_roundTrip(t *testing.T) {
+	metadata := map[string]types.String{
+		"team":     types.StringValue("analytics"),
+		"page_url": types.StringValue("{{Page URL}}"),
+	}
+
+	compiled := compileMonitoringMetadata(metadata)
+	expected := &tagmanager.Parameter{Type: "map", Map: []*tagmanager.Parameter{
+		{Key: "page_url", Type: "template", Value: "{{Page URL}}"},
+		{Key: "team", Type: "template", Value: "analytics"},
+	}}
+
+	if !reflect.DeepEqual(compiled, expected) {
+		t.Fatalf("expected the entries ordered by key, got %+v", compiled)
+	}
+
+	compiled.Map[0], compiled.Map[1] = compiled.Map[1], compiled.Map[0]
+	if read := decompileMonitoringMetadata(compiled); !reflect.DeepEqual(read, metadata) {
+		t.Fatalf("expected %v, got %v", metadata, read)
+	}
+
+	// Tags without metadata are sent without it, and the empty map GTM returns for
+	// them is read back as null.
+	if compileMonitoringMetadata(nil) != nil {
+		t.Fatal("expected no monitoring metadata to be sent")
+	}
+
+	if read := decompileMonitoringMetadata(&tagmanager.Parameter{Type: "map"}); read != nil {
+		t.Fatalf("expected empty monitoring metadata to be read as null, got %v", read)
+	}
+}
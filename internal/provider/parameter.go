@@ -1,7 +1,18 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"google.golang.org/api/tagmanager/v2"
 )
@@ -14,7 +25,7 @@ func wrapParameterSchema(list schema.ListNestedAttribute) schema.ListNestedAttri
 		NestedObject: schema.NestedAttributeObject{
 			Attributes: map[string]schema.Attribute{
 				"key": schema.StringAttribute{
-					Description: "Parameter key.",
+					Description: "Parameter key. Required except on the entries of a list parameter.",
 					Optional:    true},
 				"type": schema.StringAttribute{
 					Description: "Parameter type.",
@@ -22,9 +33,18 @@ func wrapParameterSchema(list schema.ListNestedAttribute) schema.ListNestedAttri
 				"value": schema.StringAttribute{
 					Description: "Parameter value.",
 					Optional:    true},
+				"sensitive_value": schema.StringAttribute{
+					Description: "Parameter value that is redacted from plans and logs, e.g. an API key passed in a sensitive variable. Conflicts with value.",
+					Optional:    true,
+					Sensitive:   true,
+					Validators:  []validator.String{stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("value"))}},
+				"is_weak_reference": schema.BoolAttribute{
+					Description: "Whether a tagReference or triggerReference parameter is a weak reference, which does not make the referenced entity a dependency, e.g. a soft dependency in tag sequencing.",
+					Optional:    true},
 				"list": list,
 				"map":  list,
 			},
+			Validators: []validator.Object{parameterKeyValidator{}, templateBracesValidator{}, measurementIdValidator{}},
 		},
 	}
 }
@@ -36,25 +56,121 @@ func buildParameterSchema() schema.ListNestedAttribute {
 		NestedObject: schema.NestedAttributeObject{},
 	}
 
-	for i := 0; i < 3; i++ {
+	for i := 0; i < parameterSchemaDepth; i++ {
 		s = wrapParameterSchema(s)
 	}
 
 	return s
 }
 
+// parameterSchemaDepth is the number of nested parameter levels the schemas support.
+const parameterSchemaDepth = 3
+
+var dataSourceParameterSchema = buildDataSourceParameterSchema()
+
+// wrapDataSourceParameterSchema is the read-only counterpart of wrapParameterSchema,
+// without sensitive values, which only the configuration knows.
+func wrapDataSourceParameterSchema(list dsschema.ListNestedAttribute) dsschema.ListNestedAttribute {
+	return dsschema.ListNestedAttribute{
+		Computed: true,
+		NestedObject: dsschema.NestedAttributeObject{
+			Attributes: map[string]dsschema.Attribute{
+				"key": dsschema.StringAttribute{
+					Description: "Parameter key, null on the entries of a list parameter.",
+					Computed:    true},
+				"type": dsschema.StringAttribute{
+					Description: "Parameter type.",
+					Computed:    true},
+				"value": dsschema.StringAttribute{
+					Description: "Parameter value.",
+					Computed:    true},
+				"is_weak_reference": dsschema.BoolAttribute{
+					Description: "Whether a tagReference or triggerReference parameter is a weak reference.",
+					Computed:    true},
+				"list": list,
+				"map":  list,
+			},
+		},
+	}
+}
+
+func buildDataSourceParameterSchema() dsschema.ListNestedAttribute {
+	var s = dsschema.ListNestedAttribute{
+		Description:  "Parameters.",
+		Computed:     true,
+		NestedObject: dsschema.NestedAttributeObject{},
+	}
+
+	for i := 0; i < parameterSchemaDepth; i++ {
+		s = wrapDataSourceParameterSchema(s)
+	}
+
+	return s
+}
+
+type DataSourceParameterModel struct {
+	Key             types.String               `tfsdk:"key"`
+	Type            types.String               `tfsdk:"type"`
+	Value           types.String               `tfsdk:"value"`
+	IsWeakReference types.Bool                 `tfsdk:"is_weak_reference"`
+	List            []DataSourceParameterModel `tfsdk:"list"`
+	Map             []DataSourceParameterModel `tfsdk:"map"`
+}
+
+// toDataSourceParameter reads parameters like toResourceParameter for a data source.
+// Lists and maps nested deeper than the schema supports are left out.
+func toDataSourceParameter(parameter []*tagmanager.Parameter, depth int) []DataSourceParameterModel {
+	var dataSourceParameter []DataSourceParameterModel
+
+	for _, p := range toResourceParameter(parameter) {
+		dataSourceParameter = append(dataSourceParameter, toDataSourceParameterModel(p, depth))
+	}
+
+	return dataSourceParameter
+}
+
+func toDataSourceParameterModel(p ResourceParameterModel, depth int) DataSourceParameterModel {
+	var model = DataSourceParameterModel{
+		Key:             p.Key,
+		Type:            p.Type,
+		Value:           p.Value,
+		IsWeakReference: types.BoolValue(p.IsWeakReference.ValueBool()),
+	}
+
+	if depth <= 1 {
+		return model
+	}
+
+	for _, entry := range p.List {
+		model.List = append(model.List, toDataSourceParameterModel(entry, depth-1))
+	}
+
+	for _, entry := range p.Map {
+		model.Map = append(model.Map, toDataSourceParameterModel(entry, depth-1))
+	}
+
+	return model
+}
+
 type ResourceParameterModel struct {
-	Key   types.String             `tfsdk:"key"`
-	Type  types.String             `tfsdk:"type"`
-	Value types.String             `tfsdk:"value"`
-	List  []ResourceParameterModel `tfsdk:"list"`
-	Map   []ResourceParameterModel `tfsdk:"map"`
+	Key             types.String             `tfsdk:"key"`
+	Type            types.String             `tfsdk:"type"`
+	Value           types.String             `tfsdk:"value"`
+	SensitiveValue  types.String             `tfsdk:"sensitive_value"`
+	IsWeakReference types.Bool               `tfsdk:"is_weak_reference"`
+	List            []ResourceParameterModel `tfsdk:"list"`
+	Map             []ResourceParameterModel `tfsdk:"map"`
 }
 
+// Equal reports whether two parameters are the same to GTM. The entries of a list are
+// compared by position, since their order is meaningful, e.g. the items of a GA4
+// event, whereas map entries are matched by key regardless of their order.
 func (r *ResourceParameterModel) Equal(o ResourceParameterModel) bool {
 	if !r.Key.Equal(o.Key) ||
 		!r.Type.Equal(o.Type) ||
 		!r.Value.Equal(o.Value) ||
+		!r.SensitiveValue.Equal(o.SensitiveValue) ||
+		r.IsWeakReference.ValueBool() != o.IsWeakReference.ValueBool() ||
 		len(r.List) != len(o.List) ||
 		len(r.Map) != len(o.Map) {
 		return false
@@ -66,8 +182,18 @@ func (r *ResourceParameterModel) Equal(o ResourceParameterModel) bool {
 		}
 	}
 
+	// Map entries are keyed, so GTM is free to return them in any order.
+	used := make([]bool, len(o.Map))
 	for i := 0; i < len(r.Map); i++ {
-		if !r.Map[i].Equal(o.Map[i]) {
+		found := false
+		for j := 0; j < len(o.Map); j++ {
+			if !used[j] && r.Map[i].Key.Equal(o.Map[j].Key) && r.Map[i].Equal(o.Map[j]) {
+				used[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
 			return false
 		}
 	}
@@ -80,28 +206,55 @@ func toApiParameter(resourceParameter []ResourceParameterModel) []*tagmanager.Pa
 
 	for _, p := range resourceParameter {
 		var list, mmap []*tagmanager.Parameter
+		var forceSendFields []string
 
+		// A list or map configured as [] is sent as an explicitly empty one rather than
+		// omitted, so that it means the same to GTM as in the configuration.
 		if p.List != nil {
 			list = toApiParameter(p.List)
+			if len(list) == 0 {
+				forceSendFields = append(forceSendFields, "List")
+			}
 		}
 
 		if p.Map != nil {
 			mmap = toApiParameter(p.Map)
+			if len(mmap) == 0 {
+				forceSendFields = append(forceSendFields, "Map")
+			}
 		}
 
 		parameter = append(parameter, &tagmanager.Parameter{
-			Key:   p.Key.ValueString(),
-			Type:  p.Type.ValueString(),
-			Value: p.Value.ValueString(),
-			List:  list,
-			Map:   mmap,
+			Key:             p.Key.ValueString(),
+			Type:            p.Type.ValueString(),
+			Value:           parameterValue(p),
+			IsWeakReference: p.IsWeakReference.ValueBool(),
+			List:            list,
+			Map:             mmap,
+			ForceSendFields: forceSendFields,
 		})
 	}
 
 	return parameter
 }
 
+// parameterValue returns the value sent to GTM for a parameter, its sensitive_value
+// when one is set.
+func parameterValue(p ResourceParameterModel) string {
+	if !p.SensitiveValue.IsNull() {
+		return p.SensitiveValue.ValueString()
+	}
+
+	return p.Value.ValueString()
+}
+
 func toResourceParameter(parameter []*tagmanager.Parameter) []ResourceParameterModel {
+	// An empty list has to be read back as null, otherwise an omitted parameter
+	// attribute would show a permanent diff against the refreshed state.
+	if len(parameter) == 0 {
+		return nil
+	}
+
 	var resourceParameter []ResourceParameterModel = make([]ResourceParameterModel, len(parameter))
 
 	for i, p := range parameter {
@@ -112,17 +265,325 @@ func toResourceParameter(parameter []*tagmanager.Parameter) []ResourceParameterM
 		}
 
 		if p.Map != nil {
-			mmap = toResourceParameter(p.Map)
+			mmap = sortParameterMapEntries(toResourceParameter(p.Map))
+		}
+
+		var isWeakReference = types.BoolNull()
+		if p.IsWeakReference {
+			isWeakReference = types.BoolValue(true)
 		}
 
 		resourceParameter[i] = ResourceParameterModel{
-			Key:   nullableStringValue(p.Key),
-			Type:  nullableStringValue(p.Type),
-			Value: nullableStringValue(p.Value),
-			List:  list,
-			Map:   mmap,
+			Key:             nullableStringValue(p.Key),
+			Type:            nullableStringValue(p.Type),
+			Value:           nullableStringValue(p.Value),
+			IsWeakReference: isWeakReference,
+			List:            list,
+			Map:             mmap,
 		}
 	}
 
 	return resourceParameter
 }
+
+// sortParameterMapEntries orders map entries by key. GTM does not guarantee the order
+// of map entries, so without a state to align them to, e.g. on import, they are read
+// in the same order every time.
+func sortParameterMapEntries(mmap []ResourceParameterModel) []ResourceParameterModel {
+	sort.SliceStable(mmap, func(i, j int) bool {
+		return mmap[i].Key.ValueString() < mmap[j].Key.ValueString()
+	})
+
+	return mmap
+}
+
+// alignParameterOrder reorders the map entries of parameter to follow the key
+// order found in reference, so that a reordering done by GTM does not show up
+// as a diff. The order of top-level and list parameters is kept as returned.
+func alignParameterOrder(parameter []ResourceParameterModel, reference []ResourceParameterModel) []ResourceParameterModel {
+	for i := range parameter {
+		if i >= len(reference) || !parameter[i].Key.Equal(reference[i].Key) {
+			continue
+		}
+
+		parameter[i].List = alignParameterOrder(parameter[i].List, reference[i].List)
+		parameter[i].Map = alignParameterMapOrder(parameter[i].Map, reference[i].Map)
+	}
+
+	return parameter
+}
+
+func alignParameterMapOrder(mmap []ResourceParameterModel, reference []ResourceParameterModel) []ResourceParameterModel {
+	if mmap == nil {
+		return nil
+	}
+
+	var aligned = make([]ResourceParameterModel, 0, len(mmap))
+	var used = make([]bool, len(mmap))
+
+	for _, ref := range reference {
+		for j, p := range mmap {
+			if !used[j] && p.Key.Equal(ref.Key) {
+				used[j] = true
+				p.List = alignParameterOrder(p.List, ref.List)
+				p.Map = alignParameterMapOrder(p.Map, ref.Map)
+				aligned = append(aligned, p)
+				break
+			}
+		}
+	}
+
+	for j, p := range mmap {
+		if !used[j] {
+			aligned = append(aligned, p)
+		}
+	}
+
+	return aligned
+}
+
+// keepEmptyValues puts back the empty values, empty lists and maps and false
+// is_weak_reference flags found in reference where GTM omitted them, so that a
+// parameter configured with value = "" or list = [] shows no diff against the null
+// read back. Parameters are matched by position and key like in alignParameterOrder.
+func keepEmptyValues(parameter []ResourceParameterModel, reference []ResourceParameterModel) []ResourceParameterModel {
+	for i := range parameter {
+		if i >= len(reference) || !parameter[i].Key.Equal(reference[i].Key) {
+			continue
+		}
+
+		p, ref := &parameter[i], reference[i]
+		if p.Value.IsNull() && !ref.Value.IsNull() && !ref.Value.IsUnknown() && ref.Value.ValueString() == "" {
+			p.Value = ref.Value
+		}
+
+		if p.IsWeakReference.IsNull() && !ref.IsWeakReference.IsNull() && !ref.IsWeakReference.IsUnknown() && !ref.IsWeakReference.ValueBool() {
+			p.IsWeakReference = ref.IsWeakReference
+		}
+
+		if p.List == nil && ref.List != nil && len(ref.List) == 0 {
+			p.List = ref.List
+		}
+
+		if p.Map == nil && ref.Map != nil && len(ref.Map) == 0 {
+			p.Map = ref.Map
+		}
+
+		p.List = keepEmptyValues(p.List, ref.List)
+		p.Map = keepEmptyValues(p.Map, ref.Map)
+	}
+
+	return parameter
+}
+
+// keepSensitiveValues reads the values of the parameters configured with a
+// sensitive_value in reference back into sensitive_value, so that they stay redacted
+// and show no diff. Parameters are matched by position and key like in
+// alignParameterOrder.
+func keepSensitiveValues(parameter []ResourceParameterModel, reference []ResourceParameterModel) []ResourceParameterModel {
+	for i := range parameter {
+		if i >= len(reference) || !parameter[i].Key.Equal(reference[i].Key) {
+			continue
+		}
+
+		p, ref := &parameter[i], reference[i]
+		if !ref.SensitiveValue.IsNull() && !ref.SensitiveValue.IsUnknown() {
+			p.SensitiveValue = types.StringValue(p.Value.ValueString())
+			p.Value = types.StringNull()
+		}
+
+		p.List = keepSensitiveValues(p.List, ref.List)
+		p.Map = keepSensitiveValues(p.Map, ref.Map)
+	}
+
+	return parameter
+}
+
+// sensitiveValues returns the non-empty sensitive_value of parameter and its nested
+// parameters, which are masked in the logs of the requests sending them.
+func sensitiveValues(parameter []ResourceParameterModel) []string {
+	var values []string
+
+	for _, p := range parameter {
+		if value := p.SensitiveValue.ValueString(); value != "" {
+			values = append(values, value)
+		}
+
+		values = append(values, sensitiveValues(p.List)...)
+		values = append(values, sensitiveValues(p.Map)...)
+	}
+
+	return values
+}
+
+// redactSensitiveValues replaces the sensitive values of parameter in message, e.g. a
+// GTM error that quotes the request, so that they do not reach the apply output.
+func redactSensitiveValues(message string, parameter []ResourceParameterModel) string {
+	for _, value := range sensitiveValues(parameter) {
+		message = strings.ReplaceAll(message, value, "***")
+	}
+
+	return message
+}
+
+var templateBracesEscaper = strings.NewReplacer("{{", `\{\{`, "}}", `\}\}`)
+
+// escapeTemplateBraces escapes every "{{" and "}}" in s so that GTM keeps them as
+// literal text instead of reading them as a variable reference.
+func escapeTemplateBraces(s string) string {
+	return templateBracesEscaper.Replace(s)
+}
+
+// templateBracesBalanced reports whether every unescaped "{{" in s is closed by a
+// matching "}}", which is how GTM delimits variable references. A "}}" that no "{{"
+// opened is ignored, as nested blocks of JavaScript in custom HTML end that way, e.g.
+// function(){if(a){b()}}.
+func templateBracesBalanced(s string) bool {
+	depth := 0
+
+	for i := 0; i+1 < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+
+		switch s[i : i+2] {
+		case "{{":
+			depth++
+			i++
+		case "}}":
+			if depth > 0 {
+				depth--
+			}
+			i++
+		}
+	}
+
+	return depth == 0
+}
+
+// parameterKeyValidator requires a key on parameters, except on the entries of a
+// list, which GTM identifies by their position. A top-level parameter or map entry
+// without a key is otherwise only rejected by GTM, or silently ignored by the template.
+type parameterKeyValidator struct{}
+
+func (v parameterKeyValidator) Description(_ context.Context) string {
+	return "key is required, except on the entries of a list parameter"
+}
+
+func (v parameterKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v parameterKeyValidator) ValidateObject(_ context.Context, req validator.ObjectRequest, resp *validator.ObjectResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if step, _ := req.Path.ParentPath().Steps().LastStep(); step == path.PathStepAttributeName("list") {
+		return
+	}
+
+	key, ok := req.ConfigValue.Attributes()["key"].(types.String)
+	if ok && (key.IsUnknown() || key.ValueString() != "") {
+		return
+	}
+
+	addMissingParameterKeyError(req.Path.AtName("key"), &resp.Diagnostics)
+}
+
+func addMissingParameterKeyError(at path.Path, diags *diag.Diagnostics) {
+	diags.AddAttributeError(at, "Missing Parameter Key",
+		"A parameter needs a key unless it is an entry of a list parameter. "+
+			"Set the key the tag, trigger or variable template expects for this parameter.")
+}
+
+// templateBracesValidator warns about template parameters with a "{{" that GTM would
+// misread as an unclosed variable reference, typically in HTML or JS snippets.
+type templateBracesValidator struct{}
+
+func (v templateBracesValidator) Description(_ context.Context) string {
+	return "template values must not contain an unclosed {{"
+}
+
+func (v templateBracesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v templateBracesValidator) ValidateObject(_ context.Context, req validator.ObjectRequest, resp *validator.ObjectResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	attributes := req.ConfigValue.Attributes()
+	typ, ok := attributes["type"].(types.String)
+	if !ok || typ.ValueString() != "template" {
+		return
+	}
+
+	if value, ok := attributes["value"].(types.String); ok {
+		addTemplateBracesWarning(req.Path.AtName("value"), value, &resp.Diagnostics)
+	}
+}
+
+func addTemplateBracesWarning(at path.Path, value types.String, diags *diag.Diagnostics) {
+	if value.IsNull() || value.IsUnknown() || templateBracesBalanced(value.ValueString()) {
+		return
+	}
+
+	diags.AddAttributeWarning(at, "Unbalanced Template Braces",
+		fmt.Sprintf("GTM reads {{ and }} as variable reference delimiters, but this value has a {{ that is not closed. "+
+			"If they are meant as literal text, escape them: %q.", escapeTemplateBraces(value.ValueString())))
+}
+
+// measurementIdOverrideKey is the key of the parameter overriding the measurement ID
+// of GA4 tags.
+const measurementIdOverrideKey = "measurementIdOverride"
+
+var (
+	measurementIdPattern     = regexp.MustCompile(`^G-[A-Z0-9]+$`)
+	variableReferencePattern = regexp.MustCompile(`^\{\{[^{}]+\}\}$`)
+)
+
+// measurementIdValidator warns about measurementIdOverride values of GA4 tags that are
+// neither a G-XXXXXXXXXX measurement ID nor a variable reference, which typically is
+// a Universal Analytics UA- ID pasted into a GA4 field.
+type measurementIdValidator struct{}
+
+func (v measurementIdValidator) Description(_ context.Context) string {
+	return "measurementIdOverride values must be a G-XXXXXXXXXX measurement ID or a {{variable}} reference"
+}
+
+func (v measurementIdValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v measurementIdValidator) ValidateObject(_ context.Context, req validator.ObjectRequest, resp *validator.ObjectResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	attributes := req.ConfigValue.Attributes()
+	key, ok := attributes["key"].(types.String)
+	if !ok || key.ValueString() != measurementIdOverrideKey {
+		return
+	}
+
+	if value, ok := attributes["value"].(types.String); ok {
+		addMeasurementIdWarning(req.Path.AtName("value"), value, &resp.Diagnostics)
+	}
+}
+
+func addMeasurementIdWarning(at path.Path, value types.String, diags *diag.Diagnostics) {
+	if value.IsNull() || value.IsUnknown() || value.ValueString() == "" {
+		return
+	}
+
+	if measurementIdPattern.MatchString(value.ValueString()) || variableReferencePattern.MatchString(value.ValueString()) {
+		return
+	}
+
+	diags.AddAttributeWarning(at, "Invalid Measurement ID",
+		fmt.Sprintf("%q is not a GA4 measurement ID. GA4 measurement IDs have the form G-XXXXXXXXXX; "+
+			"UA- IDs belong to Universal Analytics and are not accepted by GA4 tags.", value.ValueString()))
+}
@@ -0,0 +1,453 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+func testParameter(key string, value string) ResourceParameterModel {
+	return ResourceParameterModel{
+		Key:   types.StringValue(key),
+		Type:  types.StringValue("template"),
+		Value: types.StringValue(value),
+	}
+}
+
+// Test that map entries reordered by GTM produce no diff while the top-level order is kept
+func TestParameter_reorderedMapEntries(t *testing.T) {
+	planned := []ResourceParameterModel{
+		testParameter("eventName", "purchase"),
+		{
+			Key:  types.StringValue("eventSettings"),
+			Type: types.StringValue("map"),
+			Map: []ResourceParameterModel{
+				testParameter("name", "currency"),
+				testParameter("value", "EUR"),
+			},
+		},
+	}
+
+	remote := toResourceParameter(toApiParameter(planned))
+	remote[1].Map[0], remote[1].Map[1] = remote[1].Map[1], remote[1].Map[0]
+
+	if !remote[1].Equal(planned[1]) {
+		t.Fatal("expected map parameters to be equal regardless of entry order")
+	}
+
+	aligned := alignParameterOrder(remote, planned)
+
+	if aligned[0].Key.ValueString() != "eventName" || aligned[1].Key.ValueString() != "eventSettings" {
+		t.Fatalf("expected top-level order to be preserved, got %s, %s", aligned[0].Key, aligned[1].Key)
+	}
+
+	for i := range planned {
+		if !aligned[i].Key.Equal(planned[i].Key) || len(aligned[i].Map) != len(planned[i].Map) {
+			t.Fatalf("unexpected parameter at index %d: %v", i, aligned[i])
+		}
+
+		for j := range planned[i].Map {
+			if !aligned[i].Map[j].Key.Equal(planned[i].Map[j].Key) {
+				t.Fatalf("expected map entry %d to be %s, got %s", j, planned[i].Map[j].Key, aligned[i].Map[j].Key)
+			}
+		}
+	}
+}
+
+// Test that empty values GTM omits are read back as configured, at any depth
+func TestParameter_keepEmptyValues(t *testing.T) {
+	planned := []ResourceParameterModel{
+		testParameter("cacheBusterQueryParam", ""),
+		{
+			Key:  types.StringValue("eventSettings"),
+			Type: types.StringValue("map"),
+			Map:  []ResourceParameterModel{testParameter("name", "currency"), testParameter("value", "")},
+		},
+		{Key: types.StringValue("unset"), Type: types.StringValue("template")},
+	}
+
+	remote := keepEmptyValues(toResourceParameter(toApiParameter(planned)), planned)
+
+	if !remote[0].Value.Equal(types.StringValue("")) || !remote[1].Map[1].Value.Equal(types.StringValue("")) {
+		t.Fatalf("expected empty values to be kept, got %v", remote)
+	}
+
+	if !remote[2].Value.IsNull() {
+		t.Fatalf("expected an absent value to stay null, got %v", remote[2].Value)
+	}
+
+	if changed := keepEmptyValues(toResourceParameter(toApiParameter(planned)), nil); !changed[0].Value.IsNull() {
+		t.Fatalf("expected no empty value without a reference, got %v", changed[0].Value)
+	}
+}
+
+// Test that an explicitly empty list is sent as empty and read back as configured,
+// while an absent one stays null
+func TestParameter_emptyList(t *testing.T) {
+	planned := []ResourceParameterModel{
+		{Key: types.StringValue("customParams"), Type: types.StringValue("list"), List: []ResourceParameterModel{}},
+		{Key: types.StringValue("fieldsToSet"), Type: types.StringValue("list")},
+	}
+
+	parameter := toApiParameter(planned)
+
+	sent, err := parameter[0].MarshalJSON()
+	if err != nil || !strings.Contains(string(sent), `"list":[]`) {
+		t.Fatalf("expected an explicitly empty list to be sent, got %s (%v)", sent, err)
+	}
+
+	if sent, _ := parameter[1].MarshalJSON(); strings.Contains(string(sent), `"list":`) {
+		t.Fatalf("expected an absent list to be omitted, got %s", sent)
+	}
+
+	// GTM omits the empty list in its response
+	parameter[0].List, parameter[0].ForceSendFields = nil, nil
+	remote := keepEmptyValues(toResourceParameter(parameter), planned)
+
+	if remote[0].List == nil || len(remote[0].List) != 0 {
+		t.Fatalf("expected the empty list to be kept, got %v", remote[0].List)
+	}
+
+	if remote[1].List != nil {
+		t.Fatalf("expected an absent list to stay null, got %v", remote[1].List)
+	}
+}
+
+// Test that no field of the API parameter is dropped by the round trip through the
+// resource model. The fixture sets every field, so that a field added to
+// tagmanager.Parameter fails the test until it is modeled.
+func TestParameter_roundTripKeepsAllFields(t *testing.T) {
+	remote := []*tagmanager.Parameter{
+		{
+			Key:             "tagReference",
+			Type:            "tagReference",
+			Value:           "setup",
+			IsWeakReference: true,
+			List: []*tagmanager.Parameter{
+				{Type: "map", Map: []*tagmanager.Parameter{{Key: "name", Type: "template", Value: "currency"}}},
+			},
+			Map: []*tagmanager.Parameter{{Key: "value", Type: "template", Value: "EUR"}},
+		},
+	}
+
+	fields := reflect.TypeOf(*remote[0])
+	for i := 0; i < fields.NumField(); i++ {
+		name := fields.Field(i).Name
+		if name == "ForceSendFields" || name == "NullFields" {
+			continue
+		}
+
+		if reflect.ValueOf(*remote[0]).Field(i).IsZero() {
+			t.Fatalf("expected the fixture to set the %s field", name)
+		}
+	}
+
+	if roundTrip := toApiParameter(toResourceParameter(remote)); !reflect.DeepEqual(roundTrip, remote) {
+		before, _ := json.Marshal(remote)
+		after, _ := json.Marshal(roundTrip)
+		t.Fatalf("expected the parameter to survive the round trip, got %s instead of %s", after, before)
+	}
+}
+
+// testGA4EventParameters returns the three levels of the eventParameters of a GA4 event
+// tag: the list parameter, its map items and their name and value entries.
+func testGA4EventParameters(entries ...[]*tagmanager.Parameter) *tagmanager.Parameter {
+	var list []*tagmanager.Parameter
+	for _, entry := range entries {
+		list = append(list, &tagmanager.Parameter{Type: "map", Map: entry})
+	}
+
+	return &tagmanager.Parameter{Key: "eventParameters", Type: "list", List: list}
+}
+
+// Test that nested list/map trees decode the same whatever order GTM returns the map
+// entries in, and that the decoded tree encodes back to the parameters losslessly
+func TestParameter_stableDecode(t *testing.T) {
+	sorted := []*tagmanager.Parameter{
+		{Key: "eventName", Type: "template", Value: "purchase"},
+		testGA4EventParameters(
+			[]*tagmanager.Parameter{{Key: "name", Type: "template", Value: "currency"}, {Key: "value", Type: "template", Value: "EUR"}},
+			[]*tagmanager.Parameter{{Key: "name", Type: "template", Value: "value"}, {Key: "value", Type: "template", Value: "{{Total}}"}},
+		),
+	}
+	shuffled := []*tagmanager.Parameter{
+		{Key: "eventName", Type: "template", Value: "purchase"},
+		testGA4EventParameters(
+			[]*tagmanager.Parameter{{Key: "value", Type: "template", Value: "EUR"}, {Key: "name", Type: "template", Value: "currency"}},
+			[]*tagmanager.Parameter{{Key: "value", Type: "template", Value: "{{Total}}"}, {Key: "name", Type: "template", Value: "value"}},
+		),
+	}
+
+	decoded := toResourceParameter(sorted)
+	if !reflect.DeepEqual(toResourceParameter(shuffled), decoded) {
+		t.Fatalf("expected the map entries to decode in the same order, got %+v", toResourceParameter(shuffled))
+	}
+
+	// The order of the list items is meaningful and kept
+	if item := decoded[1].List[1].Map[1]; item.Key.ValueString() != "value" || item.Value.ValueString() != "{{Total}}" {
+		t.Fatalf("expected the second item to keep its position, got %+v", decoded[1].List[1])
+	}
+
+	if roundTrip := toApiParameter(decoded); !reflect.DeepEqual(roundTrip, sorted) {
+		before, _ := json.Marshal(sorted)
+		after, _ := json.Marshal(roundTrip)
+		t.Fatalf("expected the parameters to survive the round trip, got %s instead of %s", after, before)
+	}
+}
+
+// Test that a changed map entry is still reported as a difference
+func TestParameter_changedMapEntry(t *testing.T) {
+	a := ResourceParameterModel{
+		Key:  types.StringValue("eventSettings"),
+		Type: types.StringValue("map"),
+		Map:  []ResourceParameterModel{testParameter("name", "currency"), testParameter("value", "EUR")},
+	}
+	b := ResourceParameterModel{
+		Key:  types.StringValue("eventSettings"),
+		Type: types.StringValue("map"),
+		Map:  []ResourceParameterModel{testParameter("value", "USD"), testParameter("name", "currency")},
+	}
+
+	if a.Equal(b) {
+		t.Fatal("expected map parameters with different values to differ")
+	}
+}
+
+func testItem(id string, name string) ResourceParameterModel {
+	return ResourceParameterModel{
+		Type: types.StringValue("map"),
+		Map:  []ResourceParameterModel{testParameter("item_id", id), testParameter("item_name", name)},
+	}
+}
+
+// Test that reordered list entries, e.g. GA4 items, are a difference while reordered map entries within them are not
+func TestParameter_reorderedListEntries(t *testing.T) {
+	planned := []ResourceParameterModel{{
+		Key:  types.StringValue("items"),
+		Type: types.StringValue("list"),
+		List: []ResourceParameterModel{testItem("sku-1", "Shirt"), testItem("sku-2", "Socks")},
+	}}
+
+	remote := toResourceParameter(toApiParameter(planned))
+	remote[0].List[0].Map[0], remote[0].List[0].Map[1] = remote[0].List[0].Map[1], remote[0].List[0].Map[0]
+
+	if !remote[0].Equal(planned[0]) {
+		t.Fatal("expected list entries with reordered map entries to be equal")
+	}
+
+	aligned := alignParameterOrder(remote, planned)
+	if aligned[0].List[0].Map[0].Key.ValueString() != "item_id" {
+		t.Fatalf("expected the map entries of a list entry to be aligned, got %s first", aligned[0].List[0].Map[0].Key)
+	}
+
+	remote = toResourceParameter(toApiParameter(planned))
+	remote[0].List[0], remote[0].List[1] = remote[0].List[1], remote[0].List[0]
+
+	if remote[0].Equal(planned[0]) {
+		t.Fatal("expected reordered list entries to differ")
+	}
+
+	aligned = alignParameterOrder(remote, planned)
+	if aligned[0].List[0].Map[0].Value.ValueString() != "sku-2" {
+		t.Fatalf("expected the list order returned by GTM to be kept, got %s first", aligned[0].List[0].Map[0].Value)
+	}
+}
+
+// Test that a sensitive_value is sent as the value of its parameter and read back
+// into sensitive_value, also when nested in a map
+func TestParameter_sensitiveValue(t *testing.T) {
+	secret := ResourceParameterModel{Key: types.StringValue("apiKey"), Type: types.StringValue("template"), SensitiveValue: types.StringValue("s3cr3t")}
+	configured := []ResourceParameterModel{
+		testParameter("endpoint", "https://example.com"),
+		secret,
+		{Key: types.StringValue("headers"), Type: types.StringValue("map"), Map: []ResourceParameterModel{secret}},
+	}
+
+	api := toApiParameter(configured)
+	if api[1].Value != "s3cr3t" || api[2].Map[0].Value != "s3cr3t" {
+		t.Fatalf("expected the sensitive values to be sent, got %+v and %+v", api[1], api[2].Map[0])
+	}
+
+	read := keepSensitiveValues(toResourceParameter(api), configured)
+	for _, p := range []ResourceParameterModel{read[1], read[2].Map[0]} {
+		if !p.Value.IsNull() || p.SensitiveValue.ValueString() != "s3cr3t" {
+			t.Fatalf("expected the value to be read back into sensitive_value, got %+v", p)
+		}
+	}
+
+	if !read[0].SensitiveValue.IsNull() || read[0].Value.ValueString() != "https://example.com" {
+		t.Fatalf("expected other values to be kept, got %+v", read[0])
+	}
+
+	if values := sensitiveValues(configured); !reflect.DeepEqual(values, []string{"s3cr3t", "s3cr3t"}) {
+		t.Fatalf("expected both sensitive values to be masked, got %v", values)
+	}
+}
+
+func validateTemplateBraces(t *testing.T, value string) *validator.ObjectResponse {
+	t.Helper()
+
+	object := types.ObjectValueMust(
+		map[string]attr.Type{"type": types.StringType, "value": types.StringType},
+		map[string]attr.Value{"type": types.StringValue("template"), "value": types.StringValue(value)},
+	)
+
+	resp := &validator.ObjectResponse{}
+	templateBracesValidator{}.ValidateObject(context.Background(), validator.ObjectRequest{
+		Path:        path.Root("parameter").AtListIndex(0),
+		ConfigValue: object,
+	}, resp)
+
+	return resp
+}
+
+// Test that variable references, escaped braces and the nested blocks of JavaScript
+// pass without warnings
+func TestParameter_templateBracesBalanced(t *testing.T) {
+	for _, value := range []string{
+		"<script>var page = {{Page URL}};</script>",
+		"{{Click Text}} - {{Click URL}}",
+		"const msg = `${user}`;",
+		`\{\{literal\}\}`,
+		escapeTemplateBraces("{{ not a reference"),
+		"<script>(function(){if(window.dataLayer){dataLayer.push({event: {{Event}}})}})();</script>",
+		"}} {{Page URL}}",
+	} {
+		if resp := validateTemplateBraces(t, value); resp.Diagnostics.WarningsCount() != 0 {
+			t.Fatalf("expected no warning for %q, got %v", value, resp.Diagnostics)
+		}
+	}
+}
+
+// Test that unbalanced braces produce a warning but no error
+func TestParameter_templateBracesUnbalanced(t *testing.T) {
+	for _, value := range []string{
+		"<script>var tpl = '{{';</script>",
+		"<script>function f(){if(a){b()}} var url = {{Page URL}</script>",
+		"{{Page URL}",
+	} {
+		resp := validateTemplateBraces(t, value)
+
+		if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+			t.Fatalf("expected a single warning for %q, got %v", value, resp.Diagnostics)
+		}
+	}
+}
+
+func validateParameterKey(t *testing.T, at path.Path, key types.String) *validator.ObjectResponse {
+	t.Helper()
+
+	object := types.ObjectValueMust(
+		map[string]attr.Type{"key": types.StringType, "type": types.StringType, "value": types.StringType},
+		map[string]attr.Value{"key": key, "type": types.StringValue("template"), "value": types.StringValue("value")},
+	)
+
+	resp := &validator.ObjectResponse{}
+	parameterKeyValidator{}.ValidateObject(context.Background(), validator.ObjectRequest{
+		Path:        at,
+		ConfigValue: object,
+	}, resp)
+
+	return resp
+}
+
+// Test that top-level parameters and map entries need a key while list entries do not
+func TestParameter_key(t *testing.T) {
+	topLevel := path.Root("parameter").AtListIndex(0)
+	mapEntry := topLevel.AtName("map").AtListIndex(1)
+	listEntry := topLevel.AtName("list").AtListIndex(1)
+
+	if resp := validateParameterKey(t, topLevel, types.StringNull()); resp.Diagnostics.ErrorsCount() != 1 {
+		t.Fatalf("expected an error for a top-level parameter without key, got %v", resp.Diagnostics)
+	} else if !resp.Diagnostics[0].(diag.DiagnosticWithPath).Path().Equal(topLevel.AtName("key")) {
+		t.Fatalf("expected the error on the key, got %v", resp.Diagnostics)
+	}
+
+	if resp := validateParameterKey(t, mapEntry, types.StringNull()); resp.Diagnostics.ErrorsCount() != 1 {
+		t.Fatalf("expected an error for a map entry without key, got %v", resp.Diagnostics)
+	}
+
+	if resp := validateParameterKey(t, listEntry, types.StringNull()); resp.Diagnostics.HasError() {
+		t.Fatalf("expected no error for a list entry without key, got %v", resp.Diagnostics)
+	}
+
+	for _, key := range []types.String{types.StringValue("html"), types.StringUnknown()} {
+		if resp := validateParameterKey(t, topLevel, key); resp.Diagnostics.HasError() {
+			t.Fatalf("expected no error for key %v, got %v", key, resp.Diagnostics)
+		}
+	}
+}
+
+func validateMeasurementId(t *testing.T, value string) *validator.ObjectResponse {
+	t.Helper()
+
+	object := types.ObjectValueMust(
+		map[string]attr.Type{"key": types.StringType, "type": types.StringType, "value": types.StringType},
+		map[string]attr.Value{"key": types.StringValue("measurementIdOverride"), "type": types.StringValue("template"), "value": types.StringValue(value)},
+	)
+
+	resp := &validator.ObjectResponse{}
+	measurementIdValidator{}.ValidateObject(context.Background(), validator.ObjectRequest{
+		Path:        path.Root("parameter").AtListIndex(0),
+		ConfigValue: object,
+	}, resp)
+
+	return resp
+}
+
+// Test that measurement IDs and variable references pass without warnings
+func TestParameter_measurementIdValid(t *testing.T) {
+	for _, value := range []string{"G-ABC123XYZ9", "{{GA4 Measurement ID}}"} {
+		if resp := validateMeasurementId(t, value); resp.Diagnostics.WarningsCount() != 0 {
+			t.Fatalf("expected no warning for %q, got %v", value, resp.Diagnostics)
+		}
+	}
+}
+
+// Test that malformed measurement IDs produce a warning but no error
+func TestParameter_measurementIdMalformed(t *testing.T) {
+	for _, value := range []string{"UA-12345678-1", "g-abc123", "G-ABC123 ", "{{GA4 Measurement ID}}-2"} {
+		resp := validateMeasurementId(t, value)
+
+		if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+			t.Fatalf("expected a single warning for %q, got %v", value, resp.Diagnostics)
+		}
+	}
+}
+
+// Test that custom HTML with JS template literals passes through every conversion and
+// normalization of parameters byte-for-byte
+func TestParameter_jsTemplateLiteralsVerbatim(t *testing.T) {
+	planned := []ResourceParameterModel{testParameter("html", testJsTemplateLiteralsHtml)}
+
+	resolved, err := testReferenceResolver().resolveReferences(planned)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	api := toApiParameter(resolved)
+	if api[0].Value != testJsTemplateLiteralsHtml {
+		t.Fatalf("expected the html to be sent verbatim, got %q", api[0].Value)
+	}
+
+	remote := toResourceParameter(api)
+	remote = alignParameterOrder(remote, planned)
+	remote = keepEmptyValues(remote, planned)
+	remote = testReferenceResolver().keepReferenceNames(remote, planned)
+	if remote[0].Value.ValueString() != testJsTemplateLiteralsHtml {
+		t.Fatalf("expected the html to be read back verbatim, got %q", remote[0].Value.ValueString())
+	}
+
+	if resp := validateTemplateBraces(t, testJsTemplateLiteralsHtml); resp.Diagnostics.WarningsCount() != 0 {
+		t.Fatalf("expected no warning for JS template literals, got %v", resp.Diagnostics)
+	}
+}
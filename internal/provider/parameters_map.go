@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var parametersMapSchema = schema.MapAttribute{
+	Description: "The parameters as a map of keys to values, a shorthand for parameter when every parameter is a template, e.g. { html = \"<script></script>\" }. Conflicts with parameter.",
+	Optional:    true,
+	ElementType: types.StringType,
+	Validators:  []validator.Map{mapvalidator.ConflictsWith(path.MatchRoot("parameter")), parametersMapValidator{}},
+}
+
+// parametersMapValidator applies the checks of the parameter validators to the entries
+// of parameters_map, so that the shorthand is validated like the parameters it stands for.
+type parametersMapValidator struct{}
+
+func (v parametersMapValidator) Description(_ context.Context) string {
+	return "keys must not be empty and values are checked like template parameters"
+}
+
+func (v parametersMapValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v parametersMapValidator) ValidateMap(_ context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	elements := req.ConfigValue.Elements()
+
+	var keys = make([]string, 0, len(elements))
+	for key := range elements {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, ok := elements[key].(types.String)
+		if !ok {
+			continue
+		}
+
+		if key == "" {
+			addMissingParameterKeyError(req.Path.AtMapKey(key), &resp.Diagnostics)
+		}
+
+		addTemplateBracesWarning(req.Path.AtMapKey(key), value, &resp.Diagnostics)
+
+		if key == measurementIdOverrideKey {
+			addMeasurementIdWarning(req.Path.AtMapKey(key), value, &resp.Diagnostics)
+		}
+	}
+}
+
+// compileParametersMap returns the template parameters that parameters_map is a
+// shorthand for, ordered by key.
+func compileParametersMap(parametersMap map[string]types.String) []ResourceParameterModel {
+	var keys = make([]string, 0, len(parametersMap))
+	for key := range parametersMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parameter = make([]ResourceParameterModel, 0, len(keys))
+	for _, key := range keys {
+		parameter = append(parameter, ResourceParameterModel{
+			Key:   types.StringValue(key),
+			Type:  types.StringValue("template"),
+			Value: parametersMap[key],
+		})
+	}
+
+	return parameter
+}
+
+// withParametersMap returns the parameters to send to GTM: the compiled parametersMap
+// when it is set, parameter otherwise. Reference resolution and variable reference
+// warnings run on the result, so that parameters_map is checked like parameter.
+func withParametersMap(parameter []ResourceParameterModel, parametersMap map[string]types.String) []ResourceParameterModel {
+	if parametersMap != nil {
+		return compileParametersMap(parametersMap)
+	}
+
+	return parameter
+}
+
+// decompileParametersMap reads parameters back into parameters_map. It reports false
+// when a parameter is not a keyed template, which only the parameter attribute can
+// hold, so that such a change made outside Terraform shows up as a diff.
+func decompileParametersMap(parameter []ResourceParameterModel) (map[string]types.String, bool) {
+	var parametersMap = make(map[string]types.String, len(parameter))
+
+	for _, p := range parameter {
+		if p.Type.ValueString() != "template" || p.Key.ValueString() == "" || p.List != nil || p.Map != nil || p.IsWeakReference.ValueBool() {
+			return nil, false
+		}
+
+		parametersMap[p.Key.ValueString()] = types.StringValue(p.Value.ValueString())
+	}
+
+	return parametersMap, true
+}
+
+// equalParametersMap reports whether two parameters_map values hold the same entries.
+func equalParametersMap(m, o map[string]types.String) bool {
+	if (m == nil) != (o == nil) || len(m) != len(o) {
+		return false
+	}
+
+	for key, value := range m {
+		if other, ok := o[key]; !ok || !value.Equal(other) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Test that parameters_map compiles to the same parameters as the equivalent parameter
+// blocks on tags and variables, and reads back only when every parameter is a template
+func TestParametersMap_compile(t *testing.T) {
+	parametersMap := map[string]types.String{
+		"html":                 types.StringValue("<script>console.log('{{Page URL}}')</script>"),
+		"supportDocumentWrite": types.StringValue("false"),
+	}
+	blocks := []ResourceParameterModel{
+		testParameter("html", "<script>console.log('{{Page URL}}')</script>"),
+		testParameter("supportDocumentWrite", "false"),
+	}
+
+	tag := toApiTag(resourceTagModel{Name: types.StringValue("html"), Type: types.StringValue("html"), ParametersMap: parametersMap}, false)
+	expected := toApiTag(resourceTagModel{Name: types.StringValue("html"), Type: types.StringValue("html"), Parameter: blocks}, false)
+
+	if !reflect.DeepEqual(tag.Parameter, expected.Parameter) {
+		t.Fatalf("expected the tag parameters to match the blocks, got %+v", tag.Parameter)
+	}
+
+	variable := toApiVariable(resourceVariableModel{Name: types.StringValue("id"), Type: types.StringValue("c"), ParametersMap: map[string]types.String{"value": types.StringValue("G-ABC123XYZ9")}}, false)
+	if len(variable.Parameter) != 1 || variable.Parameter[0].Key != "value" || variable.Parameter[0].Type != "template" || variable.Parameter[0].Value != "G-ABC123XYZ9" {
+		t.Fatalf("expected a single template parameter, got %+v", variable.Parameter)
+	}
+
+	read, ok := decompileParametersMap(toResourceParameter(tag.Parameter))
+	if !ok || !equalParametersMap(read, parametersMap) {
+		t.Fatalf("expected %v to read back, got %v", parametersMap, read)
+	}
+
+	nested := append(append([]ResourceParameterModel{}, blocks...), ResourceParameterModel{
+		Key:  types.StringValue("eventParameters"),
+		Type: types.StringValue("list"),
+		List: []ResourceParameterModel{},
+	})
+	if _, ok := decompileParametersMap(nested); ok {
+		t.Fatalf("expected a list parameter not to read back into parameters_map")
+	}
+}
+
+// diagnosticMessages returns the severity, summary and detail of each diagnostic,
+// leaving out the attribute path, which differs between parameters_map and parameter.
+func diagnosticMessages(diags diag.Diagnostics) []string {
+	var messages []string
+	for _, d := range diags {
+		messages = append(messages, d.Severity().String()+": "+d.Summary()+": "+d.Detail())
+	}
+
+	return messages
+}
+
+// Test that parameters_map produces the same diagnostics as the equivalent parameter
+// blocks, from the plan time validators and the variable reference warnings
+func TestParametersMap_diagnostics(t *testing.T) {
+	ctx := context.Background()
+
+	parametersMap := map[string]types.String{
+		"":                      types.StringValue("no key"),
+		"html":                  types.StringValue("<script>var url = {{Page URL}</script>"),
+		"measurementIdOverride": types.StringValue("UA-12345678-1"),
+		"text":                  types.StringValue("{{Missing}}"),
+	}
+	blocks := []ResourceParameterModel{
+		testParameter("", "no key"),
+		testParameter("html", "<script>var url = {{Page URL}</script>"),
+		testParameter("measurementIdOverride", "UA-12345678-1"),
+		testParameter("text", "{{Missing}}"),
+	}
+
+	mapResp := &validator.MapResponse{}
+	for _, v := range parametersMapSchema.Validators[1:] {
+		v.ValidateMap(ctx, validator.MapRequest{
+			Path: path.Root("parameters_map"),
+			ConfigValue: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"":                      parametersMap[""],
+				"html":                  parametersMap["html"],
+				"measurementIdOverride": parametersMap["measurementIdOverride"],
+				"text":                  parametersMap["text"],
+			}),
+		}, mapResp)
+	}
+
+	blocksResp := &validator.ObjectResponse{}
+	for i, block := range blocks {
+		object, diags := types.ObjectValueFrom(ctx, parameterSchema.NestedObject.Type().(types.ObjectType).AttrTypes, block)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+
+		for _, v := range parameterSchema.NestedObject.Validators {
+			v.ValidateObject(ctx, validator.ObjectRequest{Path: path.Root("parameter").AtListIndex(i), ConfigValue: object}, blocksResp)
+		}
+	}
+
+	if len(mapResp.Diagnostics) != 3 || !reflect.DeepEqual(diagnosticMessages(mapResp.Diagnostics), diagnosticMessages(blocksResp.Diagnostics)) {
+		t.Fatalf("expected the blocks diagnostics %v, got %v", blocksResp.Diagnostics, mapResp.Diagnostics)
+	}
+
+	resolver := testReferenceResolver()
+	resolver.ids[variableReference] = map[string]string{}
+
+	var mapWarnings, blocksWarnings diag.Diagnostics
+	addUnknownVariableReferenceWarnings(resolver, withParametersMap(nil, parametersMap), &mapWarnings)
+	addUnknownVariableReferenceWarnings(resolver, withParametersMap(blocks, nil), &blocksWarnings)
+
+	if mapWarnings.WarningsCount() != 1 || !reflect.DeepEqual(diagnosticMessages(mapWarnings), diagnosticMessages(blocksWarnings)) {
+		t.Fatalf("expected a single unknown variable warning like the blocks %v, got %v", blocksWarnings, mapWarnings)
+	}
+}
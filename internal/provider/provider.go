@@ -2,9 +2,14 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"terraform-provider-google-tag-manager/internal/api"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -35,30 +40,103 @@ func (p *gtmProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"credential_file": schema.StringAttribute{
-				Description: "Path to the credential file.",
-				Required:    true},
+				Description: "Path to the credential file. Ignored when credentials_json is set.",
+				Optional:    true},
+			"credentials_json": schema.StringAttribute{
+				Description: "Contents of a credential file, e.g. read from a secret store. Takes precedence over credential_file and impersonate_service_account.",
+				Optional:    true,
+				Sensitive:   true},
+			"impersonate_service_account": schema.StringAttribute{
+				Description: "Email of a service account to impersonate with the application default credentials. Used only when neither credentials_json nor credential_file is set. Without any of them the application default credentials are used directly.",
+				Optional:    true},
 			"account_id": schema.StringAttribute{
 				Description: "GTM Account ID.",
 				Required:    true},
 			"container_id": schema.StringAttribute{
-				Description: "GTM Container ID.",
+				Description: "GTM Container ID, either numeric or the public GTM-XXXXXX form.",
 				Required:    true},
 			"workspace_name": schema.StringAttribute{
-				Description: "Workspace name.",
-				Required:    true},
+				Description: "Workspace name. Required by workspace scoped resources such as tags, triggers and variables; configurations using only account or container level resources may omit it.",
+				Optional:    true},
+			"require_existing_workspace": schema.BoolAttribute{
+				Description: "Fail when no workspace is named workspace_name instead of creating it, which avoids accidentally adding workspaces to a shared container. The error lists the existing workspaces.",
+				Optional:    true},
 			"retry_limit": schema.Int64Attribute{
-				Description: "Number of times to retry requests when rate-limited before giving up.",
+				Description: "Number of times to retry requests when rate-limited or when an attempt exceeds request_timeout before giving up. Set to 0 to disable retries.",
+				Optional:    true},
+			"auto_resolve_conflicts": schema.BoolAttribute{
+				Description: "Retry an update once with the latest version of the entity when it was modified concurrently. This overwrites the concurrent changes to the managed fields.",
+				Optional:    true},
+			"confirm_deletes": schema.BoolAttribute{
+				Description: "Wait after deleting a tag, trigger or variable until GTM no longer returns it, so that later reads and lists do not see it. Costs a few extra API calls per deletion.",
+				Optional:    true},
+			"max_retry_after": schema.Int64Attribute{
+				Description: "Longest wait in seconds honored when a rate-limited response asks to retry later with a Retry-After header. Longer waits are shortened to it. Defaults to 300.",
+				Optional:    true},
+			"request_timeout": schema.Int64Attribute{
+				Description: "Seconds a single attempt of an API request may take. An attempt that takes longer is abandoned and retried within retry_limit, except for creates, which GTM may have applied anyway. No timeout when unset.",
 				Optional:    true},
+			"max_retry_duration": schema.Int64Attribute{
+				Description: "Seconds all the attempts of an API request may take together, including the waits between them. No retry is made that would end later, even within retry_limit. Only retry_limit bounds the retries when unset.",
+				Optional:    true},
+			"max_idle_conns": schema.Int64Attribute{
+				Description: "Maximum number of idle HTTP connections kept for reuse. Defaults to 100.",
+				Optional:    true},
+			"max_idle_conns_per_host": schema.Int64Attribute{
+				Description: "Maximum number of idle HTTP connections kept for reuse with the Tag Manager API. Raise it with the parallelism of large applies. Defaults to 20.",
+				Optional:    true},
+			"idle_conn_timeout": schema.Int64Attribute{
+				Description: "Seconds an idle HTTP connection is kept for reuse before it is closed. Defaults to 90.",
+				Optional:    true},
+			"name_prefix": schema.StringAttribute{
+				Description: "Prefix added to the names of managed tags, triggers and variables in GTM. It is not part of the names in the configuration or state.",
+				Optional:    true},
+			"validate_access": schema.BoolAttribute{
+				Description: "Check during provider configuration that the credentials can access the container, so that a permission problem is reported up front. Costs one extra API call.",
+				Optional:    true},
+			"scopes": schema.ListAttribute{
+				Description: "OAuth scopes requested for the credentials, e.g. https://www.googleapis.com/auth/tagmanager.edit.containers. Add tagmanager.publish or the tagmanager.manage.* scopes to publish versions or manage users. Defaults to all Tag Manager scopes.",
+				Optional:    true,
+				ElementType: types.StringType},
+			"managed_by_note": schema.StringAttribute{
+				Description: "Notes set in GTM on managed tags, triggers, variables and folders that have no notes configured, e.g. \"Managed by Terraform\". Notes set in the configuration are never overwritten.",
+				Optional:    true,
+				Validators:  notesValidators},
+			"default_notes": schema.StringAttribute{
+				Description: "Template of the notes set in GTM on managed tags, triggers, variables and folders that have no notes configured, e.g. \"Created by Terraform as a {resource_type} on {timestamp}\". {resource_type} is replaced with tag, trigger, variable or folder and {timestamp} with the UTC time the provider wrote the entity in RFC 3339 format. Takes precedence over managed_by_note.",
+				Optional:    true,
+				Validators:  defaultNotesValidators},
+			"default_firing_trigger_id": schema.ListAttribute{
+				Description: "IDs of the firing triggers set on tags that omit firing_trigger_id, e.g. the All Pages trigger. A tag with an explicit empty firing_trigger_id has no firing triggers.",
+				Optional:    true,
+				ElementType: types.StringType},
 		},
 	}
 }
 
 type gtmProviderModel struct {
-	CredentialFile types.String `tfsdk:"credential_file"`
-	AccountId      types.String `tfsdk:"account_id"`
-	ContainerId    types.String `tfsdk:"container_id"`
-	WorkspaceName  types.String `tfsdk:"workspace_name"`
-	RetryLimit     types.Int64  `tfsdk:"retry_limit"`
+	CredentialFile            types.String `tfsdk:"credential_file"`
+	CredentialsJson           types.String `tfsdk:"credentials_json"`
+	ImpersonateServiceAccount types.String `tfsdk:"impersonate_service_account"`
+	AccountId                 types.String `tfsdk:"account_id"`
+	ContainerId               types.String `tfsdk:"container_id"`
+	WorkspaceName             types.String `tfsdk:"workspace_name"`
+	RetryLimit                types.Int64  `tfsdk:"retry_limit"`
+	NamePrefix                types.String `tfsdk:"name_prefix"`
+	ManagedByNote             types.String `tfsdk:"managed_by_note"`
+	DefaultNotes              types.String `tfsdk:"default_notes"`
+	ValidateAccess            types.Bool   `tfsdk:"validate_access"`
+	AutoResolveConflicts      types.Bool   `tfsdk:"auto_resolve_conflicts"`
+	Scopes                    []string     `tfsdk:"scopes"`
+	RequireExistingWorkspace  types.Bool   `tfsdk:"require_existing_workspace"`
+	ConfirmDeletes            types.Bool   `tfsdk:"confirm_deletes"`
+	MaxRetryAfter             types.Int64  `tfsdk:"max_retry_after"`
+	RequestTimeout            types.Int64  `tfsdk:"request_timeout"`
+	MaxRetryDuration          types.Int64  `tfsdk:"max_retry_duration"`
+	MaxIdleConns              types.Int64  `tfsdk:"max_idle_conns"`
+	MaxIdleConnsPerHost       types.Int64  `tfsdk:"max_idle_conns_per_host"`
+	IdleConnTimeout           types.Int64  `tfsdk:"idle_conn_timeout"`
+	DefaultFiringTriggerId    []string     `tfsdk:"default_firing_trigger_id"`
 }
 
 // Configure prepares an API client for data sources and resources.
@@ -74,31 +152,112 @@ func (p *gtmProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		return
 	}
 
+	validateProviderConfig(config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var retryLimit = 10
-	if !config.RetryLimit.IsNull() && !config.RetryLimit.IsUnknown() {
+	if !config.RetryLimit.IsNull() && !config.RetryLimit.IsUnknown() && config.RetryLimit.ValueInt64() >= 0 {
 		retryLimit = int(config.RetryLimit.ValueInt64())
 	}
 
 	client, err := api.NewClientInWorkspace(&api.ClientInWorkspaceOptions{
 		ClientOptions: &api.ClientOptions{
-			CredentialFile: config.CredentialFile.ValueString(),
-			AccountId:      config.AccountId.ValueString(),
-			ContainerId:    config.ContainerId.ValueString(),
-			RetryLimit:     retryLimit,
+			CredentialFile:            config.CredentialFile.ValueString(),
+			CredentialsJSON:           config.CredentialsJson.ValueString(),
+			ImpersonateServiceAccount: config.ImpersonateServiceAccount.ValueString(),
+			AccountId:                 config.AccountId.ValueString(),
+			ContainerId:               config.ContainerId.ValueString(),
+			RetryLimit:                retryLimit,
+			AutoResolveConflicts:      config.AutoResolveConflicts.ValueBool(),
+			ValidateAccess:            config.ValidateAccess.ValueBool(),
+			Scopes:                    config.Scopes,
+			ConfirmDeletes:            config.ConfirmDeletes.ValueBool(),
+			MaxRetryAfter:             time.Duration(config.MaxRetryAfter.ValueInt64()) * time.Second,
+			RequestTimeout:            time.Duration(config.RequestTimeout.ValueInt64()) * time.Second,
+			MaxRetryDuration:          time.Duration(config.MaxRetryDuration.ValueInt64()) * time.Second,
+			MaxIdleConns:              int(config.MaxIdleConns.ValueInt64()),
+			MaxIdleConnsPerHost:       int(config.MaxIdleConnsPerHost.ValueInt64()),
+			IdleConnTimeout:           time.Duration(config.IdleConnTimeout.ValueInt64()) * time.Second,
 		},
-		WorkspaceName: config.WorkspaceName.ValueString(),
+		WorkspaceName:            config.WorkspaceName.ValueString(),
+		NamePrefix:               config.NamePrefix.ValueString(),
+		ManagedByNote:            config.ManagedByNote.ValueString(),
+		DefaultNotes:             config.DefaultNotes.ValueString(),
+		RequireExistingWorkspace: config.RequireExistingWorkspace.ValueBool(),
+		DefaultFiringTriggerId:   config.DefaultFiringTriggerId,
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to Create GTM Client", err.Error())
+		addWorkspaceCreateError(&resp.Diagnostics, "Unable to Create GTM Client", err)
 		return
 	}
 	resp.DataSourceData = client
 	resp.ResourceData = client
 }
 
+var (
+	numericIdPattern         = regexp.MustCompile(`^[0-9]+$`)
+	containerPublicIdPattern = regexp.MustCompile(`^GTM-[A-Z0-9]+$`)
+)
+
+// validateProviderConfig checks the format of the configured IDs so that a typo
+// is reported against the attribute instead of as a 400 from the first API call.
+// IDs that are not known yet, e.g. taken from another resource, are not checked.
+func validateProviderConfig(config gtmProviderModel, diags *diag.Diagnostics) {
+	if accountId := config.AccountId.ValueString(); !config.AccountId.IsUnknown() && !numericIdPattern.MatchString(accountId) {
+		diags.AddAttributeError(path.Root("account_id"), "Invalid Account ID",
+			fmt.Sprintf("account_id must be a numeric GTM account ID, got %q.", accountId))
+	}
+
+	if containerId := config.ContainerId.ValueString(); !config.ContainerId.IsUnknown() &&
+		!numericIdPattern.MatchString(containerId) && !containerPublicIdPattern.MatchString(containerId) {
+		diags.AddAttributeError(path.Root("container_id"), "Invalid Container ID",
+			fmt.Sprintf("container_id must be a numeric GTM container ID or a public ID like GTM-XXXXXX, got %q.", containerId))
+	}
+
+	addIgnoredCredentialsWarnings(config, diags)
+}
+
+// credentialAttributes are the attributes configuring a credential source, in the
+// order of precedence of api.ClientOptions.CredentialSource.
+var credentialAttributes = []string{"credentials_json", "credential_file", "impersonate_service_account"}
+
+// addIgnoredCredentialsWarnings warns about credential attributes that are set but
+// ignored because a credential source of higher precedence is set too.
+func addIgnoredCredentialsWarnings(config gtmProviderModel, diags *diag.Diagnostics) {
+	var values = []types.String{config.CredentialsJson, config.CredentialFile, config.ImpersonateServiceAccount}
+
+	var used string
+	for i, value := range values {
+		if value.ValueString() == "" {
+			continue
+		}
+
+		if used == "" {
+			used = credentialAttributes[i]
+			continue
+		}
+
+		diags.AddAttributeWarning(path.Root(credentialAttributes[i]), "Ignored Credentials",
+			fmt.Sprintf("%s is ignored because %s is set, which takes precedence.", credentialAttributes[i], used))
+	}
+}
+
 // DataSources defines the data sources implemented in the provider.
 func (p *gtmProvider) DataSources(_ context.Context) []func() datasource.DataSource {
-	return nil
+	return []func() datasource.DataSource{
+		NewLatestVersionDataSource,
+		NewEnvironmentsDataSource,
+		NewContainersDataSource,
+		NewVariableDataSource,
+		NewTagDataSource,
+		NewTriggerDataSource,
+		NewTriggersDataSource,
+		NewVariablesDataSource,
+		NewWorkspaceCompilerStatusDataSource,
+		NewContainerExportDiffDataSource,
+	}
 }
 
 // Resources defines the resources implemented in the provider.
@@ -108,5 +267,14 @@ func (p *gtmProvider) Resources(_ context.Context) []func() resource.Resource {
 		NewTagResource,
 		NewVariableResource,
 		NewTriggerResource,
+		NewContainerConfigResource,
+		NewCustomTemplateResource,
+		NewGtagConfigResource,
+		NewClientResource,
+		NewDestinationResource,
+		NewBuiltInVariableResource,
+		NewFolderResource,
+		NewEnvironmentResource,
+		NewVersionResource,
 	}
 }
@@ -4,11 +4,18 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 const (
@@ -60,6 +67,91 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+// TestProviderConfig_nonNumericAccountId checks that a malformed account ID is reported against the attribute
+func TestProviderConfig_nonNumericAccountId(t *testing.T) {
+	var diags diag.Diagnostics
+
+	validateProviderConfig(gtmProviderModel{
+		AccountId:   types.StringValue("my-account"),
+		ContainerId: types.StringValue("224654212"),
+	}, &diags)
+
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("Expected 1 error, got %d: %v", diags.ErrorsCount(), diags)
+	}
+	if d, ok := diags[0].(diag.DiagnosticWithPath); !ok || !d.Path().Equal(path.Root("account_id")) {
+		t.Fatalf("Expected error on account_id, got %v", diags[0])
+	}
+}
+
+// TestProviderConfig_unknownIds checks that IDs not known yet, e.g. taken from another resource, are not rejected
+func TestProviderConfig_unknownIds(t *testing.T) {
+	var diags diag.Diagnostics
+
+	validateProviderConfig(gtmProviderModel{
+		AccountId:   types.StringUnknown(),
+		ContainerId: types.StringUnknown(),
+	}, &diags)
+
+	if diags.HasError() {
+		t.Fatalf("Expected unknown IDs to be accepted, got %v", diags)
+	}
+}
+
+// TestProviderConfig_publicContainerId checks that the GTM- public container ID form is accepted
+func TestProviderConfig_publicContainerId(t *testing.T) {
+	var diags diag.Diagnostics
+
+	validateProviderConfig(gtmProviderModel{
+		AccountId:   types.StringValue("6303442487"),
+		ContainerId: types.StringValue("GTM-ABC123"),
+	}, &diags)
+
+	if diags.HasError() {
+		t.Fatalf("Expected GTM- container ID to be valid, got %v", diags)
+	}
+
+	validateProviderConfig(gtmProviderModel{
+		AccountId:   types.StringValue("6303442487"),
+		ContainerId: types.StringValue("GTM_ABC123"),
+	}, &diags)
+
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("Expected malformed container ID to be rejected, got %v", diags)
+	}
+}
+
+// TestProviderConfig_ignoredCredentials checks that credentials overridden by a source of higher precedence are warned about
+func TestProviderConfig_ignoredCredentials(t *testing.T) {
+	var diags diag.Diagnostics
+
+	validateProviderConfig(gtmProviderModel{
+		AccountId:                 types.StringValue("6303442487"),
+		ContainerId:               types.StringValue("224654212"),
+		CredentialFile:            types.StringValue("key.json"),
+		ImpersonateServiceAccount: types.StringValue("sa@project.iam.gserviceaccount.com"),
+	}, &diags)
+
+	if diags.WarningsCount() != 1 {
+		t.Fatalf("Expected 1 warning, got %d: %v", diags.WarningsCount(), diags)
+	}
+	if d, ok := diags[0].(diag.DiagnosticWithPath); !ok || !d.Path().Equal(path.Root("impersonate_service_account")) {
+		t.Fatalf("Expected warning on impersonate_service_account, got %v", diags[0])
+	}
+
+	diags = nil
+	validateProviderConfig(gtmProviderModel{
+		AccountId:       types.StringValue("6303442487"),
+		ContainerId:     types.StringValue("224654212"),
+		CredentialsJson: types.StringValue("{}"),
+		CredentialFile:  types.StringValue("key.json"),
+	}, &diags)
+
+	if diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), "credentials_json is set") {
+		t.Fatalf("Expected credential_file to be ignored for credentials_json, got %v", diags)
+	}
+}
+
 // Test workspace creation and reading
 func TestAccWorkspaceResource_createAndRead(t *testing.T) {
 	testAccPreCheck(t)
@@ -123,6 +215,50 @@ func TestAccWorkspaceResource_update(t *testing.T) {
 	})
 }
 
+// Test that a description-only change is applied in place
+func TestAccWorkspaceResource_updateDescription(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWorkspaceResourceConfig(),
+				Check: func(s *terraform.State) error {
+					id = s.RootModule().Resources["gtm_workspace.test"].Primary.ID
+					return nil
+				},
+			},
+			{
+				Config: strings.Replace(testAccWorkspaceResourceConfig(), "Created by Terraform", "Described by Terraform", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gtm_workspace.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_workspace.test", "name", "tf-test-workspace"),
+					resource.TestCheckResourceAttr("gtm_workspace.test", "description", "Described by Terraform"),
+					func(s *terraform.State) error {
+						if got := s.RootModule().Resources["gtm_workspace.test"].Primary.ID; got != id {
+							return fmt.Errorf("expected workspace %s to be updated in place, got %s", id, got)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// The description reads back from GTM without a diff.
+				Config:   strings.Replace(testAccWorkspaceResourceConfig(), "Created by Terraform", "Described by Terraform", 1),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 // Test tag creation and reading
 func TestAccTagResource_createAndRead(t *testing.T) {
 	testAccPreCheck(t)
@@ -138,6 +274,11 @@ func TestAccTagResource_createAndRead(t *testing.T) {
 					resource.TestCheckResourceAttr("gtm_tag.test", "name", "tf-test-tag"),
 					resource.TestCheckResourceAttr("gtm_tag.test", "type", "gaawe"),
 					resource.TestCheckResourceAttr("gtm_tag.test", "notes", "Created by Terraform"),
+					resource.TestCheckResourceAttr("gtm_tag.test", "account_id", os.Getenv("GTM_ACCOUNT_ID")),
+					resource.TestCheckResourceAttr("gtm_tag.test", "container_id", os.Getenv("GTM_CONTAINER_ID")),
+					resource.TestMatchResourceAttr("gtm_tag.test", "container_public_id", regexp.MustCompile("^GTM-")),
+					resource.TestCheckResourceAttrSet("gtm_tag.test", "workspace_id"),
+					resource.TestMatchResourceAttr("gtm_tag.test", "path", regexp.MustCompile(`^accounts/\d+/containers/\d+/workspaces/\d+/tags/\d+$`)),
 					// Check parameters
 					resource.TestCheckResourceAttr("gtm_tag.test", "parameter.0.key", "eventName"),
 					resource.TestCheckResourceAttr("gtm_tag.test", "parameter.0.value", "test_event"),
@@ -233,6 +374,11 @@ func TestAccVariableResource_createAndRead(t *testing.T) {
 					resource.TestCheckResourceAttr("gtm_variable.test", "name", "tf-test-variable"),
 					resource.TestCheckResourceAttr("gtm_variable.test", "type", "v"),
 					resource.TestCheckResourceAttr("gtm_variable.test", "notes", "Created by Terraform"),
+					resource.TestCheckResourceAttr("gtm_variable.test", "account_id", os.Getenv("GTM_ACCOUNT_ID")),
+					resource.TestCheckResourceAttr("gtm_variable.test", "container_id", os.Getenv("GTM_CONTAINER_ID")),
+					resource.TestMatchResourceAttr("gtm_variable.test", "container_public_id", regexp.MustCompile("^GTM-")),
+					resource.TestCheckResourceAttrSet("gtm_variable.test", "workspace_id"),
+					resource.TestMatchResourceAttr("gtm_variable.test", "path", regexp.MustCompile(`^accounts/\d+/containers/\d+/workspaces/\d+/variables/\d+$`)),
 					// Check parameters
 					resource.TestCheckResourceAttr("gtm_variable.test", "parameter.0.key", "name"),
 					resource.TestCheckResourceAttr("gtm_variable.test", "parameter.0.value", "test-param"),
@@ -307,6 +453,11 @@ func TestAccTriggerResource_createAndRead(t *testing.T) {
 					resource.TestCheckResourceAttr("gtm_trigger.test", "name", "tf-test-trigger"),
 					resource.TestCheckResourceAttr("gtm_trigger.test", "type", "customEvent"),
 					resource.TestCheckResourceAttr("gtm_trigger.test", "notes", "Created by Terraform"),
+					resource.TestCheckResourceAttr("gtm_trigger.test", "account_id", os.Getenv("GTM_ACCOUNT_ID")),
+					resource.TestCheckResourceAttr("gtm_trigger.test", "container_id", os.Getenv("GTM_CONTAINER_ID")),
+					resource.TestMatchResourceAttr("gtm_trigger.test", "container_public_id", regexp.MustCompile("^GTM-")),
+					resource.TestCheckResourceAttrSet("gtm_trigger.test", "workspace_id"),
+					resource.TestMatchResourceAttr("gtm_trigger.test", "path", regexp.MustCompile(`^accounts/\d+/containers/\d+/workspaces/\d+/triggers/\d+$`)),
 					// Check custom event filter
 					resource.TestCheckResourceAttr("gtm_trigger.test", "custom_event_filter.0.type", "equals"),
 					resource.TestCheckResourceAttr("gtm_trigger.test", "custom_event_filter.0.parameter.0.key", "arg0"),
@@ -319,6 +470,27 @@ func TestAccTriggerResource_createAndRead(t *testing.T) {
 	})
 }
 
+// Test a custom event filter using a regular expression
+func TestAccTriggerResource_matchRegexFilter(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTriggerResourceMatchRegexConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gtm_trigger.regex", "id"),
+					resource.TestCheckResourceAttr("gtm_trigger.regex", "custom_event_filter.0.type", "matchRegex"),
+					resource.TestCheckResourceAttr("gtm_trigger.regex", "custom_event_filter.0.parameter.1.value", "^(purchase|refund)$"),
+					resource.TestCheckResourceAttr("gtm_trigger.regex", "custom_event_filter.0.parameter.2.key", "ignore_case"),
+				),
+			},
+		},
+	})
+}
+
 // Test trigger import
 func TestAccTriggerResource_import(t *testing.T) {
 	testAccPreCheck(t)
@@ -366,6 +538,109 @@ func TestAccTriggerResource_update(t *testing.T) {
 	})
 }
 
+// Test that the unique trigger id GTM assigns to a timer trigger is populated and stays stable across updates
+func TestAccTriggerResource_uniqueTriggerId(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	var uniqueTriggerId string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTriggerResourceTimerConfig("Created by Terraform"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_trigger.timer", "interval", "5000"),
+					resource.TestCheckResourceAttr("gtm_trigger.timer", "limit", "3"),
+					resource.TestCheckResourceAttrWith("gtm_trigger.timer", "unique_trigger_id", func(value string) error {
+						if value == "" {
+							return fmt.Errorf("expected unique_trigger_id to be populated")
+						}
+						uniqueTriggerId = value
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccTriggerResourceTimerConfig("Updated by Terraform"),
+				Check: resource.TestCheckResourceAttrWith("gtm_trigger.timer", "unique_trigger_id", func(value string) error {
+					if value != uniqueTriggerId {
+						return fmt.Errorf("expected unique_trigger_id to stay %s, got %s", uniqueTriggerId, value)
+					}
+					return nil
+				}),
+			},
+			{
+				Config:   testAccTriggerResourceTimerConfig("Updated by Terraform"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// Test that a trigger type configured in another casing than GTM stores shows no diff
+func TestAccTriggerResource_typeCasing(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: strings.Replace(testAccTriggerResourceConfig(), `type  = "customEvent"`, `type  = "customevent"`, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_trigger.test", "type", "customevent"),
+				),
+			},
+			{
+				Config:   strings.Replace(testAccTriggerResourceConfig(), `type  = "customEvent"`, `type  = "customevent"`, 1),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// Test that the configured casing of a trigger type is kept only when GTM stores the same type
+func TestWithoutTypeCasing(t *testing.T) {
+	if v := withoutTypeCasing("customEvent", types.StringValue("customevent")); v.ValueString() != "customevent" {
+		t.Fatalf("expected the configured casing to be kept, got %s", v)
+	}
+
+	if v := withoutTypeCasing("click", types.StringValue("customEvent")); v.ValueString() != "click" {
+		t.Fatalf("expected the remote type when it differs, got %s", v)
+	}
+
+	if v := withoutTypeCasing("customEvent", types.StringNull()); v.ValueString() != "customEvent" {
+		t.Fatalf("expected the remote type on import, got %s", v)
+	}
+}
+
+// TestAccProvider_validateAccess checks that a container the credentials cannot access fails during configure
+func TestAccProvider_validateAccess(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "gtm" {
+  credential_file = %q
+  account_id      = %q
+  container_id    = "1"
+  validate_access = true
+}
+
+data "gtm_latest_version" "test" {}
+`, os.Getenv("GTM_CREDENTIAL_FILE"), os.Getenv("GTM_ACCOUNT_ID")),
+				ExpectError: regexp.MustCompile(`container 1 of account`),
+			},
+		},
+	})
+}
+
 // Test configurations for each resource type
 func testAccProviderConfig() string {
 	retryLimit := 15
@@ -533,6 +808,50 @@ resource "gtm_trigger" "test" {
 `
 }
 
+func testAccTriggerResourceMatchRegexConfig() string {
+	return testAccProviderConfig() + `
+resource "gtm_trigger" "regex" {
+  name = "tf-test-trigger-regex"
+  type = "customEvent"
+
+  custom_event_filter = [
+    {
+      type = "matchRegex"
+      parameter = [
+        {
+          type  = "template"
+          key   = "arg0"
+          value = "{{_event}}"
+        },
+        {
+          type  = "template"
+          key   = "arg1"
+          value = "^(purchase|refund)$"
+        },
+        {
+          type  = "boolean"
+          key   = "ignore_case"
+          value = "true"
+        }
+      ]
+    }
+  ]
+}
+`
+}
+
+func testAccTriggerResourceTimerConfig(notes string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "gtm_trigger" "timer" {
+  name     = "tf-test-trigger-timer"
+  type     = "timer"
+  notes    = %q
+  interval = "5000"
+  limit    = "3"
+}
+`, notes)
+}
+
 func testAccTriggerResourceUpdateConfig() string {
 	return testAccProviderConfig() + `
 resource "gtm_trigger" "test" {
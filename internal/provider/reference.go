@@ -0,0 +1,261 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// referenceResolver resolves the values of triggerReference and tagReference
+// parameters, which hold the id of another entity of the workspace. A value may also
+// be the name of the entity, which is resolved to its id before it is sent to GTM.
+// The entities are only listed once a reference has to be resolved.
+type referenceResolver struct {
+	client *api.ClientInWorkspace
+
+	// ids maps each parameter type to the ids of the entities, keyed by id and name.
+	ids map[string]map[string]string
+}
+
+func newReferenceResolver(client *api.ClientInWorkspace) *referenceResolver {
+	return &referenceResolver{client: client, ids: map[string]map[string]string{}}
+}
+
+func isReferenceParameter(parameterType string) bool {
+	return parameterType == "triggerReference" || parameterType == "tagReference"
+}
+
+func (r *referenceResolver) load(parameterType string) (map[string]string, error) {
+	if ids, ok := r.ids[parameterType]; ok {
+		return ids, nil
+	}
+
+	var ids = map[string]string{}
+
+	switch parameterType {
+	case "triggerReference":
+		triggers, err := r.client.ListTriggers()
+		if err != nil {
+			return nil, err
+		}
+		for _, trigger := range triggers {
+			ids[trigger.TriggerId] = trigger.TriggerId
+			ids[trigger.Name] = trigger.TriggerId
+		}
+	case "tagReference":
+		tags, err := r.client.ListTags()
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			ids[tag.TagId] = tag.TagId
+			ids[tag.Name] = tag.TagId
+		}
+	case variableReference:
+		variables, err := r.client.ListVariables()
+		if err != nil {
+			return nil, err
+		}
+		for _, variable := range variables {
+			ids[variable.Name] = variable.VariableId
+		}
+	}
+
+	r.ids[parameterType] = ids
+	return ids, nil
+}
+
+// resolve returns the id of the entity a reference parameter value refers to by id
+// or by name.
+func (r *referenceResolver) resolve(parameterType string, value string) (string, error) {
+	ids, err := r.load(parameterType)
+	if err != nil {
+		return "", err
+	}
+
+	if id, ok := ids[value]; ok {
+		return id, nil
+	}
+
+	if id, ok := ids[withNamePrefix(r.client.Options.NamePrefix, value)]; ok {
+		return id, nil
+	}
+
+	entity := strings.TrimSuffix(parameterType, "Reference")
+	return "", fmt.Errorf("%s parameter value %q does not match the id or name of any %s in the workspace", parameterType, value, entity)
+}
+
+// resolveReferences returns a copy of parameter in which the values of reference
+// parameters are resolved to entity ids. Values holding variable references are
+// left to GTM.
+func (r *referenceResolver) resolveReferences(parameter []ResourceParameterModel) ([]ResourceParameterModel, error) {
+	if parameter == nil {
+		return nil, nil
+	}
+
+	var resolved = make([]ResourceParameterModel, len(parameter))
+
+	for i, p := range parameter {
+		var err error
+
+		if isReferenceParameter(p.Type.ValueString()) && p.Value.ValueString() != "" && !strings.Contains(p.Value.ValueString(), "{{") {
+			var id string
+			if id, err = r.resolve(p.Type.ValueString(), p.Value.ValueString()); err != nil {
+				return nil, err
+			}
+			p.Value = types.StringValue(id)
+		}
+
+		if p.List, err = r.resolveReferences(p.List); err != nil {
+			return nil, err
+		}
+
+		if p.Map, err = r.resolveReferences(p.Map); err != nil {
+			return nil, err
+		}
+
+		resolved[i] = p
+	}
+
+	return resolved, nil
+}
+
+// keepReferenceNames puts back the entity names used in reference in place of the
+// ids GTM returns for them, so that referring to an entity by name shows no diff.
+// Parameters are matched by position and key like in alignParameterOrder.
+func (r *referenceResolver) keepReferenceNames(parameter []ResourceParameterModel, reference []ResourceParameterModel) []ResourceParameterModel {
+	for i := range parameter {
+		if i >= len(reference) || !parameter[i].Key.Equal(reference[i].Key) {
+			continue
+		}
+
+		p, ref := &parameter[i], reference[i]
+		if isReferenceParameter(p.Type.ValueString()) && !p.Value.Equal(ref.Value) && ref.Value.ValueString() != "" {
+			if id, err := r.resolve(p.Type.ValueString(), ref.Value.ValueString()); err == nil && id == p.Value.ValueString() {
+				p.Value = ref.Value
+			}
+		}
+
+		p.List = r.keepReferenceNames(p.List, ref.List)
+		p.Map = r.keepReferenceNames(p.Map, ref.Map)
+	}
+
+	return parameter
+}
+
+// variableReference is the key under which the resolver keeps the ids of the custom
+// variables by name. Variables are referenced by name in {{...}} rather than by a
+// parameter type of their own.
+const variableReference = "variable"
+
+var variableReferenceNamePattern = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+
+// builtInVariableNames are the names GTM gives the built-in variables of web and
+// server containers, which are referenced like custom variables, e.g. {{Page URL}}.
+var builtInVariableNames = []string{
+	// Pages
+	"Page URL", "Page Hostname", "Page Path", "Referrer",
+	// Utilities
+	"Event", "Environment Name", "Container ID", "Container Version", "Random Number", "HTML ID", "Debug Mode",
+	// Errors
+	"Error Message", "Error URL", "Error Line",
+	// Clicks
+	"Click Element", "Click Classes", "Click ID", "Click Target", "Click URL", "Click Text",
+	// Forms
+	"Form Element", "Form Classes", "Form ID", "Form Target", "Form URL", "Form Text",
+	// History
+	"New History Fragment", "Old History Fragment", "New History State", "Old History State", "History Source",
+	// Videos
+	"Video Provider", "Video Status", "Video URL", "Video Title", "Video Duration", "Video Current Time", "Video Percent", "Video Visible",
+	// Scrolling and visibility
+	"Scroll Depth Threshold", "Scroll Depth Units", "Scroll Direction", "Percent Visible", "On-Screen Duration",
+	// Server containers
+	"Client Name", "Event Name", "Query String", "Request Method", "Request Path", "Request Host", "Request Port", "Request Scheme",
+}
+
+// isBuiltInVariableName reports whether name is the name of a built-in variable or of
+// an internal variable like _event, which GTM provides in every container.
+func isBuiltInVariableName(name string) bool {
+	if strings.HasPrefix(name, "_") {
+		return true
+	}
+
+	for _, builtIn := range builtInVariableNames {
+		if name == builtIn {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unknownVariableReferences returns the names referenced as {{name}} in the values of
+// parameter that are neither a built-in variable nor a custom variable of the
+// workspace. The custom variables are only listed when a value references a variable
+// that is not built in.
+func (r *referenceResolver) unknownVariableReferences(parameter []ResourceParameterModel) ([]string, error) {
+	var unknown []string
+
+	for _, p := range parameter {
+		for _, match := range variableReferenceNamePattern.FindAllStringSubmatch(p.Value.ValueString(), -1) {
+			name := match[1]
+			if isBuiltInVariableName(name) || slices.Contains(unknown, name) {
+				continue
+			}
+
+			ids, err := r.load(variableReference)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := ids[name]; ok {
+				continue
+			}
+
+			if _, ok := ids[withNamePrefix(r.client.Options.NamePrefix, name)]; ok {
+				continue
+			}
+
+			unknown = append(unknown, name)
+		}
+
+		for _, nested := range [][]ResourceParameterModel{p.List, p.Map} {
+			names, err := r.unknownVariableReferences(nested)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, name := range names {
+				if !slices.Contains(unknown, name) {
+					unknown = append(unknown, name)
+				}
+			}
+		}
+	}
+
+	return unknown, nil
+}
+
+// addUnknownVariableReferenceWarnings warns about parameter values referencing
+// variables that do not exist in the workspace, which GTM only reports when the
+// workspace is compiled. The check is best effort, so a failure to list the variables
+// is reported as a warning too.
+func addUnknownVariableReferenceWarnings(resolver *referenceResolver, parameter []ResourceParameterModel, diags *diag.Diagnostics) {
+	unknown, err := resolver.unknownVariableReferences(parameter)
+	if err != nil {
+		diags.AddAttributeWarning(path.Root("parameter"), "Unable to Check Variable References", err.Error())
+		return
+	}
+
+	for _, name := range unknown {
+		diags.AddAttributeWarning(path.Root("parameter"), "Unknown Variable Reference",
+			fmt.Sprintf("{{%s}} is neither a built-in variable nor a variable of the workspace. "+
+				"GTM will fail to compile the workspace unless the variable is created.", name))
+	}
+}
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testReferenceResolver() *referenceResolver {
+	var resolver = newReferenceResolver(testClientInWorkspace())
+	resolver.ids["triggerReference"] = map[string]string{"7": "7", "checkout": "7"}
+
+	return resolver
+}
+
+// Test that trigger names are resolved to ids, including in nested parameters
+func TestResolveReferences(t *testing.T) {
+	parameter := []ResourceParameterModel{
+		{Key: types.StringValue("trigger"), Type: types.StringValue("triggerReference"), Value: types.StringValue("checkout")},
+		{Key: types.StringValue("triggers"), Type: types.StringValue("list"), List: []ResourceParameterModel{
+			{Type: types.StringValue("triggerReference"), Value: types.StringValue("7")},
+		}},
+	}
+
+	resolved, err := testReferenceResolver().resolveReferences(parameter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resolved[0].Value.ValueString() != "7" || resolved[1].List[0].Value.ValueString() != "7" {
+		t.Fatalf("expected references to resolve to 7, got %v", resolved)
+	}
+
+	if parameter[0].Value.ValueString() != "checkout" {
+		t.Fatalf("expected the planned parameters to be left unchanged, got %s", parameter[0].Value)
+	}
+
+	_, err = testReferenceResolver().resolveReferences([]ResourceParameterModel{
+		{Key: types.StringValue("trigger"), Type: types.StringValue("triggerReference"), Value: types.StringValue("missing")},
+	})
+	if err == nil || !strings.Contains(err.Error(), "does not match the id or name of any trigger") {
+		t.Fatalf("expected an unknown trigger to be rejected, got %v", err)
+	}
+}
+
+// Test that a trigger name in state is kept when GTM returns the id it resolves to
+func TestKeepReferenceNames(t *testing.T) {
+	remote := []ResourceParameterModel{
+		{Key: types.StringValue("trigger"), Type: types.StringValue("triggerReference"), Value: types.StringValue("7")},
+	}
+	state := []ResourceParameterModel{
+		{Key: types.StringValue("trigger"), Type: types.StringValue("triggerReference"), Value: types.StringValue("checkout")},
+	}
+
+	kept := testReferenceResolver().keepReferenceNames(remote, state)
+	if kept[0].Value.ValueString() != "checkout" {
+		t.Fatalf("expected the trigger name to be kept, got %s", kept[0].Value)
+	}
+
+	state[0].Value = types.StringValue("other")
+	kept = testReferenceResolver().keepReferenceNames([]ResourceParameterModel{
+		{Key: types.StringValue("trigger"), Type: types.StringValue("triggerReference"), Value: types.StringValue("7")},
+	}, state)
+	if kept[0].Value.ValueString() != "7" {
+		t.Fatalf("expected the remote id when the state does not resolve to it, got %s", kept[0].Value)
+	}
+}
+
+// Test that a weak tag reference keeps both its value and flag through name resolution
+// and the round trip to GTM
+func TestResolveReferences_weakReference(t *testing.T) {
+	var resolver = testReferenceResolver()
+	resolver.ids["tagReference"] = map[string]string{"4": "4", "setup": "4"}
+
+	planned := []ResourceParameterModel{
+		{Key: types.StringValue("setupTag"), Type: types.StringValue("list"), List: []ResourceParameterModel{
+			{Type: types.StringValue("map"), Map: []ResourceParameterModel{
+				{Key: types.StringValue("tagName"), Type: types.StringValue("tagReference"), Value: types.StringValue("setup"), IsWeakReference: types.BoolValue(true)},
+			}},
+		}},
+	}
+
+	resolved, err := resolver.resolveReferences(planned)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	api := toApiParameter(resolved)[0].List[0].Map[0]
+	if api.Value != "4" || !api.IsWeakReference {
+		t.Fatalf("expected a weak reference to tag 4, got %+v", api)
+	}
+
+	remote := resolver.keepReferenceNames(toResourceParameter(toApiParameter(resolved)), planned)
+	if !remote[0].Equal(planned[0]) {
+		t.Fatalf("expected the weak reference to round-trip, got %v", remote[0])
+	}
+}
+
+// Test that a parameter wired to a built-in variable round-trips unchanged and passes
+// the variable reference check without listing the variables of the workspace
+func TestBuiltInVariableReference(t *testing.T) {
+	parameter := []ResourceParameterModel{
+		testParameter("url", "{{Page URL}}"),
+		{Key: types.StringValue("fields"), Type: types.StringValue("map"), Map: []ResourceParameterModel{
+			testParameter("event", "{{_event}}"),
+		}},
+	}
+
+	resolved, err := testReferenceResolver().resolveReferences(parameter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	remote := toResourceParameter(toApiParameter(resolved))
+	if remote[0].Value.ValueString() != "{{Page URL}}" || !remote[0].Equal(parameter[0]) || !remote[1].Equal(parameter[1]) {
+		t.Fatalf("expected the built-in reference to round-trip, got %v", remote)
+	}
+
+	// The test client has no service, so listing the variables would panic
+	var diags diag.Diagnostics
+	addUnknownVariableReferenceWarnings(newReferenceResolver(testClientInWorkspace()), parameter, &diags)
+	if len(diags) != 0 {
+		t.Fatalf("expected no warning for built-in variables, got %v", diags)
+	}
+}
+
+// Test that references to variables missing from the workspace are reported once,
+// including in nested parameters
+func TestUnknownVariableReferences(t *testing.T) {
+	resolver := testReferenceResolver()
+	resolver.ids[variableReference] = map[string]string{"Order Total": "9"}
+
+	parameter := []ResourceParameterModel{
+		testParameter("html", "<script>track({{Order Total}}, {{Click Text}}, {{Missing}})</script>"),
+		{Key: types.StringValue("items"), Type: types.StringValue("list"), List: []ResourceParameterModel{
+			{Type: types.StringValue("template"), Value: types.StringValue("{{Missing}} {{Also Missing}}")},
+		}},
+		testParameter("literal", `\{\{Not A Reference\}\}`),
+	}
+
+	unknown, err := resolver.unknownVariableReferences(parameter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(unknown, ",") != "Missing,Also Missing" {
+		t.Fatalf("expected Missing and Also Missing, got %v", unknown)
+	}
+
+	var diags diag.Diagnostics
+	addUnknownVariableReferenceWarnings(resolver, parameter, &diags)
+	if diags.HasError() || diags.WarningsCount() != 2 {
+		t.Fatalf("expected two warnings, got %v", diags)
+	}
+}
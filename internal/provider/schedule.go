@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// toApiScheduleMs converts an RFC3339 schedule timestamp into the milliseconds since
+// the epoch GTM stores. An unset or invalid timestamp is 0, which GTM reads as no
+// schedule; invalid ones are rejected by scheduleTimestampValidator beforehand.
+func toApiScheduleMs(timestamp types.String) int64 {
+	t, err := time.Parse(time.RFC3339, timestamp.ValueString())
+	if err != nil {
+		return 0
+	}
+
+	return t.UnixMilli()
+}
+
+// toResourceSchedule converts the milliseconds since the epoch stored by GTM into an
+// RFC3339 timestamp in UTC. The current timestamp is kept when it denotes the same
+// instant, so that one written with another offset shows no diff.
+func toResourceSchedule(ms int64, current types.String) types.String {
+	if ms == 0 {
+		return types.StringNull()
+	}
+
+	if toApiScheduleMs(current) == ms {
+		return current
+	}
+
+	return types.StringValue(time.UnixMilli(ms).UTC().Format(time.RFC3339Nano))
+}
+
+// validateSchedule reports a schedule that ends before it starts. Timestamps that are
+// unset, unknown or invalid are left to the other validators.
+func validateSchedule(start types.String, end types.String, diags *diag.Diagnostics) {
+	if start.IsNull() || start.IsUnknown() || end.IsNull() || end.IsUnknown() {
+		return
+	}
+
+	startTime, startErr := time.Parse(time.RFC3339, start.ValueString())
+	endTime, endErr := time.Parse(time.RFC3339, end.ValueString())
+	if startErr != nil || endErr != nil || startTime.Before(endTime) {
+		return
+	}
+
+	diags.AddAttributeError(path.Root("schedule_end"), "Invalid Schedule",
+		fmt.Sprintf("schedule_end %s must be after schedule_start %s.", end.ValueString(), start.ValueString()))
+}
+
+// scheduleTimestampValidator requires schedule timestamps in RFC3339 form, e.g.
+// 2030-01-01T00:00:00Z, including the offset that makes the instant unambiguous.
+type scheduleTimestampValidator struct{}
+
+func (v scheduleTimestampValidator) Description(_ context.Context) string {
+	return "value must be an RFC3339 timestamp such as 2030-01-01T00:00:00Z"
+}
+
+func (v scheduleTimestampValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v scheduleTimestampValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	t, err := time.Parse(time.RFC3339, req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Timestamp",
+			fmt.Sprintf("%q is not an RFC3339 timestamp such as 2030-01-01T00:00:00Z.", req.ConfigValue.ValueString()))
+		return
+	}
+
+	if t.UnixMilli() <= 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Timestamp",
+			fmt.Sprintf("%q is not after 1970-01-01T00:00:00Z, which GTM reads as no schedule.", req.ConfigValue.ValueString()))
+	}
+}
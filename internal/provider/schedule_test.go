@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Test that RFC3339 timestamps round trip through the milliseconds GTM stores
+func TestSchedule_validTimestamps(t *testing.T) {
+	start := types.StringValue("2030-01-01T00:00:00Z")
+	if ms := toApiScheduleMs(start); ms != 1893456000000 {
+		t.Fatalf("expected 1893456000000, got %d", ms)
+	}
+
+	if read := toResourceSchedule(1893456000000, types.StringNull()); read.ValueString() != "2030-01-01T00:00:00Z" {
+		t.Fatalf("expected the timestamp in UTC, got %s", read)
+	}
+
+	offset := types.StringValue("2030-01-01T01:00:00+01:00")
+	if read := toResourceSchedule(1893456000000, offset); !read.Equal(offset) {
+		t.Fatalf("expected the configured offset to be kept for the same instant, got %s", read)
+	}
+
+	if read := toResourceSchedule(1893456000500, types.StringNull()); read.ValueString() != "2030-01-01T00:00:00.5Z" {
+		t.Fatalf("expected milliseconds to be kept, got %s", read)
+	}
+
+	if read := toResourceSchedule(0, start); !read.IsNull() {
+		t.Fatalf("expected no schedule to be read as null, got %s", read)
+	}
+
+	for _, value := range []string{"2030-01-01T00:00:00Z", "2030-06-15T12:30:00.250+02:00"} {
+		resp := &validator.StringResponse{}
+		scheduleTimestampValidator{}.ValidateString(context.Background(), validator.StringRequest{
+			Path:        path.Root("schedule_start"),
+			ConfigValue: types.StringValue(value),
+		}, resp)
+
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("expected %s to be valid, got %v", value, resp.Diagnostics)
+		}
+	}
+
+	var diags diag.Diagnostics
+	validateSchedule(start, types.StringValue("2030-02-01T00:00:00Z"), &diags)
+	if diags.HasError() {
+		t.Fatalf("expected a schedule ending after its start to be valid, got %v", diags)
+	}
+}
+
+// Test that malformed timestamps and schedules ending before they start are rejected
+func TestSchedule_invalid(t *testing.T) {
+	for _, value := range []string{"2030-01-01", "2030-01-01 00:00:00", "1893456000000", "1969-12-31T00:00:00Z"} {
+		resp := &validator.StringResponse{}
+		scheduleTimestampValidator{}.ValidateString(context.Background(), validator.StringRequest{
+			Path:        path.Root("schedule_start"),
+			ConfigValue: types.StringValue(value),
+		}, resp)
+
+		if resp.Diagnostics.ErrorsCount() != 1 {
+			t.Fatalf("expected %s to be rejected, got %v", value, resp.Diagnostics)
+		}
+	}
+
+	var diags diag.Diagnostics
+	validateSchedule(types.StringValue("2030-02-01T00:00:00Z"), types.StringValue("2030-01-01T00:00:00Z"), &diags)
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("expected a schedule ending before its start to be rejected, got %v", diags)
+	}
+	if d, ok := diags[0].(diag.DiagnosticWithPath); !ok || !d.Path().Equal(path.Root("schedule_end")) {
+		t.Fatalf("expected the error on schedule_end, got %v", diags[0])
+	}
+
+	diags = nil
+	validateSchedule(types.StringValue("2030-01-01T01:00:00+01:00"), types.StringValue("2030-01-01T00:00:00Z"), &diags)
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("expected a schedule ending at its start to be rejected, got %v", diags)
+	}
+}
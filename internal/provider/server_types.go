@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// knownServerClientTypes are the built-in client types of server containers.
+var knownServerClientTypes = []string{
+	"gaaw_client",
+	"gtm_client",
+	"mp_client",
+}
+
+// knownServerTransformationTypes are the built-in transformation types of server
+// containers. serverTransformationTypeValidator validates them for the type of a
+// transformation resource, which the provider does not have yet.
+var knownServerTransformationTypes = []string{
+	"tf_allow_params",
+	"tf_augment_event",
+	"tf_exclude_params",
+}
+
+// customTemplateTypePrefix starts the type of entities built from a custom template.
+const customTemplateTypePrefix = "cvt_"
+
+var (
+	serverClientTypeValidator         = serverTypeValidator{kind: "client", known: knownServerClientTypes}
+	serverTransformationTypeValidator = serverTypeValidator{kind: "transformation", known: knownServerTransformationTypes}
+)
+
+// serverTypeValidator warns about type values of server container entities that are
+// neither a known built-in type nor a custom template type. GTM only rejects an
+// unknown type when the workspace is compiled, so a typo would otherwise surface late.
+type serverTypeValidator struct {
+	kind  string
+	known []string
+}
+
+func (v serverTypeValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("type should be a known %s type or a custom template type", v.kind)
+}
+
+func (v serverTypeValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v serverTypeValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if strings.HasPrefix(value, customTemplateTypePrefix) {
+		return
+	}
+
+	for _, known := range v.known {
+		if value == known {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeWarning(req.Path, "Unknown Type",
+		fmt.Sprintf("%q is not a known %s type. Known types are %s, or a custom template type starting with %q.",
+			value, v.kind, strings.Join(v.known, ", "), customTemplateTypePrefix))
+}
@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func validateServerType(v serverTypeValidator, value string) *validator.StringResponse {
+	resp := &validator.StringResponse{}
+	v.ValidateString(context.Background(), validator.StringRequest{
+		Path:        path.Root("type"),
+		ConfigValue: types.StringValue(value),
+	}, resp)
+
+	return resp
+}
+
+// Test that known and custom template client and transformation types pass without warnings
+func TestServerType_known(t *testing.T) {
+	for _, value := range []string{"gaaw_client", "cvt_123_45"} {
+		if resp := validateServerType(serverClientTypeValidator, value); resp.Diagnostics.WarningsCount() != 0 {
+			t.Fatalf("expected no warning for client type %q, got %v", value, resp.Diagnostics)
+		}
+	}
+
+	for _, value := range []string{"tf_allow_params", "cvt_123_46"} {
+		if resp := validateServerType(serverTransformationTypeValidator, value); resp.Diagnostics.WarningsCount() != 0 {
+			t.Fatalf("expected no warning for transformation type %q, got %v", value, resp.Diagnostics)
+		}
+	}
+}
+
+// Test that unknown client and transformation types produce a warning but no error
+func TestServerType_unknown(t *testing.T) {
+	for _, resp := range []*validator.StringResponse{
+		validateServerType(serverClientTypeValidator, "gaaw_clinet"),
+		validateServerType(serverClientTypeValidator, "tf_allow_params"),
+		validateServerType(serverTransformationTypeValidator, "gaaw_client"),
+	} {
+		if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+			t.Fatalf("expected a single warning, got %v", resp.Diagnostics)
+		}
+	}
+}
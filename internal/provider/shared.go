@@ -1,9 +1,344 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"terraform-provider-google-tag-manager/internal/api"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// maxNotesLength is the longest notes value GTM accepts on an entity.
+const maxNotesLength = 4096
+
+// notesValidators reject over-length notes at plan time instead of failing in the API.
+var notesValidators = []validator.String{
+	stringvalidator.LengthAtMost(maxNotesLength),
+}
+
+// recordCreatedId stores the id of a freshly created entity before the rest of the
+// state is mapped, so that a later failure leaves a tracked resource instead of an
+// orphan that the next apply would create again.
+func recordCreatedId(ctx context.Context, state *tfsdk.State, id string) diag.Diagnostics {
+	return state.SetAttribute(ctx, path.Root("id"), types.StringValue(id))
+}
+
+// requireWorkspace reports an error when a workspace scoped resource or data source is
+// used with a provider configured without workspace_name.
+func requireWorkspace(client *api.ClientInWorkspace, diags *diag.Diagnostics) {
+	if !client.HasWorkspace() {
+		diags.AddError("Missing Workspace Name",
+			"This resource operates in a workspace. Set workspace_name in the provider configuration to use it.")
+	}
+}
+
+// requireServerContainer reports an error at plan time when a resource that only exists
+// in server containers is used with another kind of container.
+func requireServerContainer(client *api.ClientInWorkspace, resourceType string, diags *diag.Diagnostics) {
+	features, err := client.ContainerFeatures()
+	if err != nil {
+		diags.AddError("Error Reading Container Features", err.Error())
+		return
+	}
+
+	checkServerContainer(features, resourceType, client.Client.Options.ContainerId, diags)
+}
+
+// checkServerContainer tells server containers apart by their support for clients,
+// which no other kind of container has.
+func checkServerContainer(features *tagmanager.ContainerFeatures, resourceType string, containerId string, diags *diag.Diagnostics) {
+	if !features.SupportClients {
+		diags.AddError("Server Container Required",
+			fmt.Sprintf("%s can only be used with a server container, but container %s is not one. "+
+				"Configure the provider with the ID of a server container.", resourceType, containerId))
+	}
+}
+
+// warnWorkspaceEmptied warns when an entity is gone from a workspace that has no
+// pending changes, as happens when the changes of the workspace were published and it
+// was reset. The entity is removed from the state and planned to be created again, so
+// the warning explains the plan instead of leaving it to look like churn. Nothing is
+// reported when the status of the workspace cannot be read.
+func warnWorkspaceEmptied(client *api.ClientInWorkspace, entity string, id string, diags *diag.Diagnostics) {
+	status, err := client.WorkspaceStatus()
+	if err != nil || len(status.WorkspaceChange) > 0 {
+		return
+	}
+
+	diags.AddWarning("Workspace Emptied",
+		fmt.Sprintf("%s %s no longer exists in workspace %s, which has no pending changes. "+
+			"The workspace was likely published and reset, or its changes were discarded. "+
+			"The %s will be created again on the next apply; remove it from the configuration if it is no longer wanted.",
+			entity, id, client.Options.WorkspaceId, strings.ToLower(entity)))
+}
+
+// addWorkspaceCreateError reports a failed workspace creation, advising how to free up
+// a workspace when the container has reached its workspace limit.
+func addWorkspaceCreateError(diags *diag.Diagnostics, summary string, err error) {
+	if errors.Is(err, api.ErrWorkspaceLimitReached) {
+		diags.AddError("Workspace Limit Reached",
+			"The container already has the maximum number of workspaces GTM allows. Delete an unused workspace or publish an existing one, then retry.")
+		return
+	}
+
+	diags.AddError(summary, err.Error())
+}
+
+// workspaceEntityAttributes are the computed attributes locating a workspace entity in GTM.
+var workspaceEntityAttributes = map[string]schema.Attribute{
+	"account_id": schema.StringAttribute{
+		Description: "The ID of the account the entity belongs to.",
+		Computed:    true,
+	},
+	"container_id": schema.StringAttribute{
+		Description: "The ID of the container the entity belongs to.",
+		Computed:    true,
+	},
+	"container_public_id": schema.StringAttribute{
+		Description: "The public ID of the container the entity belongs to, e.g. GTM-XXXXXX, as used in the container snippet.",
+		Computed:    true,
+	},
+	"workspace_id": schema.StringAttribute{
+		Description: "The ID of the workspace the entity belongs to.",
+		Computed:    true,
+	},
+	"path": schema.StringAttribute{
+		Description: "The full GTM path of the entity.",
+		Computed:    true,
+	},
+}
+
+// withWorkspaceEntityAttributes adds the account_id, container_id, container_public_id,
+// workspace_id and path attributes to a schema.
+func withWorkspaceEntityAttributes(attributes map[string]schema.Attribute) map[string]schema.Attribute {
+	for name, attribute := range workspaceEntityAttributes {
+		attributes[name] = attribute
+	}
+
+	return attributes
+}
+
+// retryLimitAttribute overrides the provider retry_limit for the operations of a
+// resource, e.g. one that is more sensitive to rate limits than the others.
+var retryLimitAttribute = schema.Int64Attribute{
+	Description: "Number of times to retry the requests of this resource when rate-limited, overriding the provider retry_limit. Set to 0 to disable retries.",
+	Optional:    true,
+	Validators:  []validator.Int64{int64validator.AtLeast(0)},
+}
+
+// withRetryLimit returns the client to use for the operations of a resource, which
+// retries rate-limited requests retryLimit times when it is set.
+func withRetryLimit(client *api.ClientInWorkspace, retryLimit types.Int64) *api.ClientInWorkspace {
+	if retryLimit.IsNull() || retryLimit.IsUnknown() {
+		return client
+	}
+
+	return client.WithRetryLimit(int(retryLimit.ValueInt64()))
+}
+
+// withRequestLog returns a client that logs every attempt of its API requests at debug
+// level to the tflog subsystem of a resource. The request id shared by the retries of
+// a request correlates the logs of a failed apply with the GTM requests it made. The
+// masked values, e.g. the sensitive parameter values sent, are redacted from the logs.
+func withRequestLog(ctx context.Context, client *api.ClientInWorkspace, subsystem string, masked ...string) *api.ClientInWorkspace {
+	ctx = tflog.NewSubsystem(ctx, subsystem)
+	ctx = tflog.SubsystemMaskMessageStrings(ctx, subsystem, masked...)
+	ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, subsystem, masked...)
+
+	return client.WithRequestLogger(func(entry api.RequestLog) {
+		fields := map[string]any{
+			"request_id":  entry.RequestId,
+			"attempt":     entry.Attempt,
+			"duration_ms": entry.Duration.Milliseconds(),
+		}
+		if entry.Err != nil {
+			fields["error"] = entry.Err.Error()
+		}
+
+		tflog.SubsystemDebug(ctx, subsystem, "GTM API request", fields)
+	})
+}
+
+// workspaceEntityModel holds the workspaceEntityAttributes in the models of workspace
+// entity resources, which embed it.
+type workspaceEntityModel struct {
+	AccountId         types.String `tfsdk:"account_id"`
+	ContainerId       types.String `tfsdk:"container_id"`
+	ContainerPublicId types.String `tfsdk:"container_public_id"`
+	WorkspaceId       types.String `tfsdk:"workspace_id"`
+	Path              types.String `tfsdk:"path"`
+}
+
+// workspaceEntityLocation returns the location of an entity of the client's workspace.
+// The public id of the container is read once per client; it is null when the
+// container cannot be read, which is retried on the next refresh.
+func workspaceEntityLocation(client *api.ClientInWorkspace, collection string, id string) workspaceEntityModel {
+	var publicId = types.StringNull()
+	if value, err := client.ContainerPublicId(); err == nil {
+		publicId = nullableStringValue(value)
+	}
+
+	return workspaceEntityModel{
+		AccountId:         types.StringValue(client.Client.Options.AccountId),
+		ContainerId:       types.StringValue(client.Client.Options.ContainerId),
+		ContainerPublicId: publicId,
+		WorkspaceId:       types.StringValue(client.Options.WorkspaceId),
+		Path:              types.StringValue(client.EntityPath(collection, id)),
+	}
+}
+
+// nameImportPrefix starts an import id that names the entity instead of giving its id.
+const nameImportPrefix = "name:"
+
+// workspaceImportId returns the entity id for an import id, which is either the id
+// itself or the full GTM path of the entity, e.g. accounts/1/containers/2/workspaces/3/tags/4.
+// A path pointing at another account, container or workspace than the one the provider
+// is configured for is rejected, since the entity would otherwise be read from the
+// wrong place.
+func workspaceImportId(client *api.ClientInWorkspace, collection string, importId string) (string, error) {
+	if !strings.Contains(importId, "/") {
+		return importId, nil
+	}
+
+	id := importId[strings.LastIndex(importId, "/")+1:]
+	if expected := client.EntityPath(collection, id); importId != expected {
+		return "", fmt.Errorf("%s does not belong to the configured workspace, expected a path like %s. "+
+			"Configure the provider for the account, container and workspace of the entity to import it.", importId, expected)
+	}
+
+	return id, nil
+}
+
+// withManagedByNote returns the notes stored in GTM for configured notes. Entities
+// without notes get the provider's managed_by_note, user-provided notes are kept.
+func withManagedByNote(note string, notes string) string {
+	if notes == "" {
+		return note
+	}
+
+	return notes
+}
+
+// withoutManagedByNote returns the notes to keep in state for notes stored in GTM.
+// The managed_by_note added by withManagedByNote is read back as the null it
+// replaced, so that it shows no diff and is not added twice. Notes explicitly set to
+// an empty string stay empty rather than becoming null. Other notes are kept byte for
+// byte, including line breaks and the trailing spaces of markdown line breaks.
+func withoutManagedByNote(note string, remote string, current types.String) types.String {
+	if note != "" && remote == note && (current.IsNull() || current.IsUnknown()) {
+		return types.StringNull()
+	}
+
+	if (remote == "" || remote == note) && !current.IsNull() && !current.IsUnknown() && current.ValueString() == "" {
+		return current
+	}
+
+	return nullableStringValue(remote)
+}
+
+// The placeholders of the provider's default_notes template.
+const (
+	defaultNotesResourceType = "{resource_type}"
+	defaultNotesTimestamp    = "{timestamp}"
 )
 
+// defaultNotesValidators reject a default_notes template that renders over-length notes.
+var defaultNotesValidators = []validator.String{defaultNotesLengthValidator{}}
+
+// defaultNotesLengthValidator rejects a default_notes template whose rendered notes
+// would exceed the notes length GTM accepts, which only the API would report, on
+// every entity the provider writes without notes.
+type defaultNotesLengthValidator struct{}
+
+func (v defaultNotesLengthValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("rendered notes must be at most %d characters", maxNotesLength)
+}
+
+func (v defaultNotesLengthValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v defaultNotesLengthValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	// variable is the longest resource type, and RFC 3339 UTC timestamps have a fixed length.
+	note := managedByNote(&api.ClientInWorkspaceOptions{DefaultNotes: req.ConfigValue.ValueString()}, "variable", time.Time{})
+	if length := len(note); length > maxNotesLength {
+		resp.Diagnostics.AddAttributeError(req.Path, "Default Notes Too Long",
+			fmt.Sprintf("The notes rendered from default_notes are up to %d characters long, but GTM accepts at most %d.", length, maxNotesLength))
+	}
+}
+
+// managedByNote returns the notes the provider sets on an entity of resourceType
+// written at now without notes: the default_notes template with its placeholders
+// replaced, or else the managed_by_note.
+func managedByNote(options *api.ClientInWorkspaceOptions, resourceType string, now time.Time) string {
+	if options.DefaultNotes == "" {
+		return options.ManagedByNote
+	}
+
+	return strings.NewReplacer(
+		defaultNotesResourceType, resourceType,
+		defaultNotesTimestamp, now.UTC().Format(time.RFC3339),
+	).Replace(options.DefaultNotes)
+}
+
+// remoteManagedByNote returns the note managedByNote set on an entity of
+// resourceType whose notes in GTM are remote. Notes rendered from default_notes at
+// any time are returned as is, so that withoutManagedByNote reads them back as null.
+func remoteManagedByNote(options *api.ClientInWorkspaceOptions, resourceType string, remote string) string {
+	if options.DefaultNotes == "" {
+		return options.ManagedByNote
+	}
+
+	pattern := regexp.QuoteMeta(options.DefaultNotes)
+	pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta(defaultNotesResourceType), regexp.QuoteMeta(resourceType))
+	pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta(defaultNotesTimestamp), `\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z`)
+
+	if remote != "" && regexp.MustCompile("^"+pattern+"$").MatchString(remote) {
+		return remote
+	}
+
+	return ""
+}
+
+// withNamePrefix returns the name stored in GTM for a configured name. A name that
+// already starts with the prefix is kept as is instead of being prefixed twice.
+func withNamePrefix(prefix string, name string) string {
+	if strings.HasPrefix(name, prefix) {
+		return name
+	}
+
+	return prefix + name
+}
+
+// withoutNamePrefix returns the name to keep in state for a name stored in GTM. The
+// current name is kept when it maps to the same remote name, so that a configured
+// name that already carries the prefix does not show a diff.
+func withoutNamePrefix(prefix string, remote string, current types.String) types.String {
+	if !current.IsNull() && !current.IsUnknown() && withNamePrefix(prefix, current.ValueString()) == remote {
+		return current
+	}
+
+	return types.StringValue(strings.TrimPrefix(remote, prefix))
+}
+
 func nullableStringValue(s string) types.String {
 	if s != "" {
 		return types.StringValue(s)
@@ -12,6 +347,24 @@ func nullableStringValue(s string) types.String {
 	}
 }
 
+func nullableInt64Value(i int64) types.Int64 {
+	if i != 0 {
+		return types.Int64Value(i)
+	} else {
+		return types.Int64Null()
+	}
+}
+
+// nullableBoolValue returns null for false when the current value is null, so that an
+// unset flag does not show a diff while a flag turned on outside Terraform does.
+func nullableBoolValue(b bool, current types.Bool) types.Bool {
+	if !b && current.IsNull() {
+		return types.BoolNull()
+	}
+
+	return types.BoolValue(b)
+}
+
 func toResourceStringArray(list []string) []types.String {
 	var rv []types.String
 
@@ -31,3 +384,53 @@ func unwrapStringArray(list []types.String) []string {
 
 	return rv
 }
+
+// alignStringOrder returns reference when it holds the same values as list in a
+// different order, so that a reordering done by GTM does not show up as a diff.
+func alignStringOrder(list []types.String, reference []types.String) []types.String {
+	if len(list) != len(reference) {
+		return list
+	}
+
+	used := make([]bool, len(list))
+	for _, ref := range reference {
+		found := false
+		for j, v := range list {
+			if !used[j] && v.Equal(ref) {
+				used[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return list
+		}
+	}
+
+	return reference
+}
+
+// exportJson serializes a workspace entity in the format of a GTM container export,
+// which leaves out where the entity is stored.
+func exportJson(entity any) (string, error) {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return "", err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", err
+	}
+
+	delete(fields, "path")
+	delete(fields, "tagManagerUrl")
+	delete(fields, "workspaceId")
+
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,381 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"terraform-provider-google-tag-manager/internal/api"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"google.golang.org/api/option"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that the id recorded after create survives a failing state mapping
+func TestRecordCreatedId_survivesStateSetFailure(t *testing.T) {
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	NewTagResource().Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+
+	if diags := recordCreatedId(ctx, &state, "42"); diags.HasError() {
+		t.Fatalf("unexpected error recording id: %v", diags)
+	}
+
+	// A model that does not match the schema makes the full state set fail.
+	if diags := state.Set(ctx, struct{ Unknown types.String }{}); !diags.HasError() {
+		t.Fatal("expected the mismatched state set to fail")
+	}
+
+	var id types.String
+	if diags := state.GetAttribute(ctx, path.Root("id"), &id); diags.HasError() {
+		t.Fatalf("unexpected error reading id: %v", diags)
+	}
+
+	if id.ValueString() != "42" {
+		t.Fatalf("expected id 42 to be kept in state, got %s", id)
+	}
+}
+
+// Test that the name prefix is added once in GTM and stripped again for state
+func TestNamePrefix(t *testing.T) {
+	if name := withNamePrefix("tf-", "purchase"); name != "tf-purchase" {
+		t.Fatalf("expected tf-purchase, got %s", name)
+	}
+
+	if name := withNamePrefix("tf-", "tf-purchase"); name != "tf-purchase" {
+		t.Fatalf("expected an already prefixed name to be kept, got %s", name)
+	}
+
+	if name := withoutNamePrefix("tf-", "tf-purchase", types.StringValue("purchase")); name.ValueString() != "purchase" {
+		t.Fatalf("expected purchase, got %s", name)
+	}
+
+	if name := withoutNamePrefix("tf-", "tf-purchase", types.StringValue("tf-purchase")); name.ValueString() != "tf-purchase" {
+		t.Fatalf("expected the configured prefixed name to be kept, got %s", name)
+	}
+
+	if name := withoutNamePrefix("tf-", "tf-purchase", types.StringNull()); name.ValueString() != "purchase" {
+		t.Fatalf("expected an imported name to be stripped, got %s", name)
+	}
+
+	if name := withoutNamePrefix("", "purchase", types.StringNull()); name.ValueString() != "purchase" {
+		t.Fatalf("expected an empty prefix to keep the name, got %s", name)
+	}
+}
+
+// Test that the account_id, container_id, workspace_id and path are derived from the client's workspace
+func TestWorkspaceEntityLocation(t *testing.T) {
+	tag := toResourceTag(&tagmanager.Tag{TagId: "4", Name: "tag", Type: "html"}, testClientInWorkspace())
+
+	if tag.AccountId.ValueString() != "1" || tag.ContainerId.ValueString() != "2" {
+		t.Fatalf("expected account_id 1 and container_id 2, got %s and %s", tag.AccountId, tag.ContainerId)
+	}
+
+	if tag.ContainerPublicId.ValueString() != "GTM-TEST" {
+		t.Fatalf("expected container_public_id GTM-TEST, got %s", tag.ContainerPublicId)
+	}
+
+	if tag.WorkspaceId.ValueString() != "3" {
+		t.Fatalf("expected workspace_id 3, got %s", tag.WorkspaceId)
+	}
+
+	if tag.Path.ValueString() != "accounts/1/containers/2/workspaces/3/tags/4" {
+		t.Fatalf("unexpected path %s", tag.Path)
+	}
+
+	template := toResourceCustomTemplate(&tagmanager.CustomTemplate{TemplateId: "5", Name: "template"}, testClientInWorkspace())
+
+	if template.TemplateId.ValueString() != "5" || template.Path.ValueString() != "accounts/1/containers/2/workspaces/3/templates/5" {
+		t.Fatalf("unexpected template_id %s and path %s", template.TemplateId, template.Path)
+	}
+}
+
+// Test that entities carrying fields the client library does not know yet convert
+// like entities without them, since the converters copy the modeled fields one by one
+func TestUnknownApiFields(t *testing.T) {
+	var tag tagmanager.Tag
+	if err := json.Unmarshal([]byte(`{"tagId": "4", "name": "tag", "type": "html", "revision": 7,
+		"parameter": [{"key": "html", "type": "template", "value": "<p></p>", "origin": "template"}]}`), &tag); err != nil {
+		t.Fatalf("unexpected error decoding the tag: %v", err)
+	}
+
+	if read := toResourceTag(&tag, testClientInWorkspace()); read.Id.ValueString() != "4" || read.Parameter[0].Value.ValueString() != "<p></p>" {
+		t.Fatalf("unexpected tag %+v", read)
+	}
+
+	var trigger tagmanager.Trigger
+	if err := json.Unmarshal([]byte(`{"triggerId": "5", "name": "trigger", "type": "pageview", "sampling": {"rate": 0.5}}`), &trigger); err != nil {
+		t.Fatalf("unexpected error decoding the trigger: %v", err)
+	}
+
+	if read := toResourceTrigger(&trigger, testClientInWorkspace()); read.Id.ValueString() != "5" || read.Type.ValueString() != "pageview" {
+		t.Fatalf("unexpected trigger %+v", read)
+	}
+
+	var variable tagmanager.Variable
+	if err := json.Unmarshal([]byte(`{"variableId": "6", "name": "variable", "type": "c", "scope": {"server": true}}`), &variable); err != nil {
+		t.Fatalf("unexpected error decoding the variable: %v", err)
+	}
+
+	if read := toResourceVariable(&variable, testClientInWorkspace()); read.Id.ValueString() != "6" || read.Type.ValueString() != "c" {
+		t.Fatalf("unexpected variable %+v", read)
+	}
+}
+
+// Test that a flag turned on outside Terraform reads back as drift while an unset false
+// flag stays null
+func TestNullableBoolValue(t *testing.T) {
+	if read := nullableBoolValue(false, types.BoolNull()); !read.IsNull() {
+		t.Fatalf("expected an unset false flag to stay null, got %s", read)
+	}
+
+	if read := nullableBoolValue(true, types.BoolNull()); !read.Equal(types.BoolValue(true)) {
+		t.Fatalf("expected a flag turned on remotely to read true, got %s", read)
+	}
+
+	if read := nullableBoolValue(false, types.BoolValue(true)); !read.Equal(types.BoolValue(false)) {
+		t.Fatalf("expected a flag turned off remotely to read false, got %s", read)
+	}
+}
+
+// Test that the workspace limit error is reported with advice instead of the raw API error
+func TestAddWorkspaceCreateError(t *testing.T) {
+	var diags diag.Diagnostics
+	addWorkspaceCreateError(&diags, "Error Creating Workspace", fmt.Errorf("%w: limit", api.ErrWorkspaceLimitReached))
+
+	if len(diags) != 1 || diags[0].Summary() != "Workspace Limit Reached" {
+		t.Fatalf("expected a workspace limit diagnostic, got %v", diags)
+	}
+
+	if !strings.Contains(diags[0].Detail(), "Delete an unused workspace or publish an existing one") {
+		t.Fatalf("expected advice in the detail, got %s", diags[0].Detail())
+	}
+
+	diags = nil
+	addWorkspaceCreateError(&diags, "Error Creating Workspace", errors.New("boom"))
+
+	if len(diags) != 1 || diags[0].Summary() != "Error Creating Workspace" || diags[0].Detail() != "boom" {
+		t.Fatalf("expected the generic error, got %v", diags)
+	}
+}
+
+// testMarkdownNotes are multi-line markdown notes with whitespace that matters: a
+// markdown line break made of two trailing spaces, an indented code block and a
+// trailing newline.
+const testMarkdownNotes = "# Purchase tag\n\nOwner: **analytics**  \nSee `docs/tags.md`.\n\n    fires on /checkout\n\t- tabbed item\n"
+
+// Test that multi-line markdown notes round-trip byte for byte and explicitly empty
+// notes stay empty
+func TestNotesFidelity(t *testing.T) {
+	for _, note := range []string{"", "Managed by Terraform"} {
+		notes := types.StringValue(testMarkdownNotes)
+		remote := withManagedByNote(note, toApiTag(resourceTagModel{Notes: notes}, false).Notes)
+
+		if read := withoutManagedByNote(note, remote, notes); read.ValueString() != testMarkdownNotes {
+			t.Fatalf("expected the notes to round-trip byte for byte, got %q", read.ValueString())
+		}
+
+		empty := types.StringValue("")
+		remote = withManagedByNote(note, toApiTag(resourceTagModel{Notes: empty}, false).Notes)
+
+		if read := withoutManagedByNote(note, remote, empty); read.IsNull() || read.ValueString() != "" {
+			t.Fatalf("expected explicitly empty notes to stay empty with managed_by_note %q, got %s", note, read)
+		}
+	}
+}
+
+// Test that the managed_by_note only fills empty notes and is not stored in state
+func TestManagedByNote(t *testing.T) {
+	const note = "Managed by Terraform"
+
+	if notes := withManagedByNote(note, ""); notes != note {
+		t.Fatalf("expected empty notes to get the note, got %s", notes)
+	}
+
+	if notes := withManagedByNote(note, "Owned by analytics"); notes != "Owned by analytics" {
+		t.Fatalf("expected configured notes to be kept, got %s", notes)
+	}
+
+	// Updating an entity that already carries the note sets it again instead of appending.
+	remote := withManagedByNote(note, withoutManagedByNote(note, note, types.StringNull()).ValueString())
+	if remote != note {
+		t.Fatalf("expected the note not to be duplicated on update, got %s", remote)
+	}
+
+	if notes := withoutManagedByNote(note, note, types.StringNull()); !notes.IsNull() {
+		t.Fatalf("expected the note to be read back as null, got %s", notes)
+	}
+
+	if notes := withoutManagedByNote(note, note, types.StringValue(note)); notes.ValueString() != note {
+		t.Fatalf("expected notes configured to the note to be kept, got %s", notes)
+	}
+
+	if notes := withoutManagedByNote("", "", types.StringNull()); !notes.IsNull() {
+		t.Fatalf("expected empty notes to stay null without a note, got %s", notes)
+	}
+}
+
+// Test that the templated default_notes only fill empty notes and are read back as
+// null whatever their timestamp
+func TestDefaultNotes(t *testing.T) {
+	options := &api.ClientInWorkspaceOptions{
+		ManagedByNote: "Managed by Terraform",
+		DefaultNotes:  "Created by Terraform as a {resource_type} on {timestamp}",
+	}
+	created := time.Date(2024, 5, 1, 12, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	note := managedByNote(options, "tag", created)
+	if note != "Created by Terraform as a tag on 2024-05-01T10:30:00Z" {
+		t.Fatalf("expected the placeholders to be replaced, got %s", note)
+	}
+
+	if notes := withManagedByNote(note, "Owned by analytics"); notes != "Owned by analytics" {
+		t.Fatalf("expected configured notes to be kept, got %s", notes)
+	}
+
+	remote := withManagedByNote(note, "")
+	if remote != note {
+		t.Fatalf("expected empty notes to get the note, got %s", remote)
+	}
+
+	if notes := withoutManagedByNote(remoteManagedByNote(options, "tag", remote), remote, types.StringNull()); !notes.IsNull() {
+		t.Fatalf("expected the note to be read back as null, got %s", notes)
+	}
+
+	// Updating the entity renders the note again instead of appending to it.
+	updated := withManagedByNote(managedByNote(options, "tag", created.Add(time.Hour)), "")
+	if updated != "Created by Terraform as a tag on 2024-05-01T11:30:00Z" {
+		t.Fatalf("expected the note not to be duplicated on update, got %s", updated)
+	}
+
+	if notes := withoutManagedByNote(remoteManagedByNote(options, "trigger", remote), remote, types.StringNull()); notes.ValueString() != remote {
+		t.Fatalf("expected the note of another resource type to be kept, got %s", notes)
+	}
+
+	if notes := withoutManagedByNote(remoteManagedByNote(options, "tag", "Owned by analytics"), "Owned by analytics", types.StringNull()); notes.ValueString() != "Owned by analytics" {
+		t.Fatalf("expected other notes to be kept, got %s", notes)
+	}
+}
+
+// Test that imports by path are only accepted for the configured workspace
+func TestWorkspaceImportId(t *testing.T) {
+	client := testClientInWorkspace()
+
+	if id, err := workspaceImportId(client, "tags", "4"); err != nil || id != "4" {
+		t.Fatalf("expected a plain id to be kept, got %s and %v", id, err)
+	}
+
+	if id, err := workspaceImportId(client, "tags", "accounts/1/containers/2/workspaces/3/tags/4"); err != nil || id != "4" {
+		t.Fatalf("expected the id to be taken from the path, got %s and %v", id, err)
+	}
+
+	_, err := workspaceImportId(client, "tags", "accounts/1/containers/9/workspaces/3/tags/4")
+	if err == nil || !strings.Contains(err.Error(), "does not belong to the configured workspace") {
+		t.Fatalf("expected a path in another container to be rejected, got %v", err)
+	}
+
+	if _, err := workspaceImportId(client, "tags", "accounts/1/containers/2/workspaces/3/triggers/4"); err == nil {
+		t.Fatal("expected a path to another kind of entity to be rejected")
+	}
+}
+
+// testClientInWorkspace returns a client for account 1, container 2 and workspace 3
+// that is only used to derive paths and never calls the API.
+func testClientInWorkspace() *api.ClientInWorkspace {
+	return &api.ClientInWorkspace{
+		Client:  &api.Client{Options: &api.ClientOptions{AccountId: "1", ContainerId: "2", ContainerPublicId: "GTM-TEST"}},
+		Options: &api.ClientInWorkspaceOptions{WorkspaceId: "3"},
+	}
+}
+
+// Test that a resource retry_limit overrides the provider retry limit only when set
+func TestWithRetryLimit(t *testing.T) {
+	client := testClientInWorkspace()
+
+	if withRetryLimit(client, types.Int64Null()) != client {
+		t.Fatal("expected the provider client when retry_limit is not set")
+	}
+
+	if override := withRetryLimit(client, types.Int64Value(20)); override.Client.Options.RetryLimit != 20 {
+		t.Fatalf("expected a retry limit of 20, got %d", override.Client.Options.RetryLimit)
+	}
+}
+
+// Test that creating a tag logs the request id of its API request to the tag subsystem
+func TestWithRequestLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"tagId": "4", "name": "tag", "type": "html"}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := testClientInWorkspace()
+	client.Client.Service = srv
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	if _, err := withRequestLog(ctx, client, "tag").CreateTag(&tagmanager.Tag{Name: "tag", Type: "html"}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestIdPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	if len(entries) != 1 || entries[0]["@message"] != "GTM API request" || entries[0]["@level"] != "debug" ||
+		!strings.HasSuffix(fmt.Sprint(entries[0]["@module"]), ".tag") || !requestIdPattern.MatchString(fmt.Sprint(entries[0]["request_id"])) {
+		t.Fatalf("expected one debug entry with a request id in the tag subsystem, got %v", entries)
+	}
+}
+
+// Test that a default_notes template is rejected when its rendered notes exceed the
+// GTM notes limit, even though the template itself is short enough
+func TestDefaultNotes_length(t *testing.T) {
+	for template, valid := range map[string]bool{
+		strings.Repeat("a", maxNotesLength-20) + "{timestamp}":     true,
+		strings.Repeat("a", maxNotesLength-19) + "{timestamp}":     false,
+		strings.Repeat("a", maxNotesLength-15) + "{resource_type}": true,
+		strings.Repeat("a", maxNotesLength+1):                      false,
+	} {
+		resp := &validator.StringResponse{}
+		for _, v := range defaultNotesValidators {
+			v.ValidateString(context.Background(), validator.StringRequest{
+				Path:        path.Root("default_notes"),
+				ConfigValue: types.StringValue(template),
+			}, resp)
+		}
+
+		if resp.Diagnostics.HasError() == valid {
+			t.Fatalf("expected a template of %d characters to be valid: %t, got %v", len(template), valid, resp.Diagnostics)
+		}
+	}
+}
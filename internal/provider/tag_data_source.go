@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ datasource.DataSource              = &tagDataSource{}
+	_ datasource.DataSourceWithConfigure = &tagDataSource{}
+)
+
+type tagDataSource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewTagDataSource() datasource.DataSource {
+	return &tagDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *tagDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(d.client, &resp.Diagnostics)
+}
+
+// Metadata returns the data source type name.
+func (d *tagDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tag"
+}
+
+// Schema defines the schema for the data source.
+func (d *tagDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up a tag of the workspace by name. Reading fails if no tag or several tags have the name.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the tag.",
+				Required:    true,
+			},
+			"id": schema.StringAttribute{
+				Description: "The ID of the tag.",
+				Computed:    true,
+			},
+			"type": schema.StringAttribute{
+				Description: "The type of the tag.",
+				Computed:    true,
+			},
+			"notes": schema.StringAttribute{
+				Description: "The notes of the tag.",
+				Computed:    true,
+			},
+			"parameter": dataSourceParameterSchema,
+			"json": schema.StringAttribute{
+				Description: "The tag serialized in the format of a GTM container export.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type dataSourceTagModel struct {
+	Name      types.String               `tfsdk:"name"`
+	Id        types.String               `tfsdk:"id"`
+	Type      types.String               `tfsdk:"type"`
+	Notes     types.String               `tfsdk:"notes"`
+	Parameter []DataSourceParameterModel `tfsdk:"parameter"`
+	Json      types.String               `tfsdk:"json"`
+}
+
+// findTagsByName returns the tags stored under name, with or without the configured
+// name prefix.
+func findTagsByName(tags []*tagmanager.Tag, prefix string, name string) []*tagmanager.Tag {
+	var found []*tagmanager.Tag
+
+	for _, tag := range tags {
+		if tag.Name == name || tag.Name == withNamePrefix(prefix, name) {
+			found = append(found, tag)
+		}
+	}
+
+	return found
+}
+
+// tagIds returns the IDs of tags.
+func tagIds(tags []*tagmanager.Tag) []string {
+	var ids = make([]string, 0, len(tags))
+	for _, tag := range tags {
+		ids = append(ids, tag.TagId)
+	}
+
+	return ids
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *tagDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config dataSourceTagModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tags, err := d.client.ListTags()
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Tag", err.Error())
+		return
+	}
+
+	found := findTagsByName(tags, d.client.Options.NamePrefix, config.Name.ValueString())
+	if len(found) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Tag Not Found",
+			"No tag named "+config.Name.ValueString()+" exists in the workspace.")
+		return
+	} else if len(found) > 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Multiple Tags Found",
+			fmt.Sprintf("%d tags named %s exist in the workspace, with the IDs %s. Rename all but one of them to look it up by name.",
+				len(found), config.Name.ValueString(), strings.Join(tagIds(found), ", ")))
+		return
+	}
+
+	tag := found[0]
+
+	json, err := exportJson(tag)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Tag", err.Error())
+		return
+	}
+
+	var state = dataSourceTagModel{
+		Name:      config.Name,
+		Id:        types.StringValue(tag.TagId),
+		Type:      types.StringValue(tag.Type),
+		Notes:     nullableStringValue(tag.Notes),
+		Parameter: toDataSourceParameter(tag.Parameter, parameterSchemaDepth),
+		Json:      types.StringValue(json),
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
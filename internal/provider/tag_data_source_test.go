@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"google.golang.org/api/option"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that a tag is looked up by name and exported to JSON
+func TestAccTagDataSource_json(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagDataSourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.gtm_tag.lookup", "id", "gtm_tag.lookup", "id"),
+					resource.TestCheckResourceAttr("data.gtm_tag.lookup", "type", "html"),
+					resource.TestCheckResourceAttr("data.gtm_tag.lookup", "parameter.0.key", "html"),
+					resource.TestMatchResourceAttr("data.gtm_tag.lookup", "json", regexp.MustCompile(`"name":"tf-test-tag-lookup"`)),
+					resource.TestMatchResourceAttr("data.gtm_tag.lookup", "json", regexp.MustCompile(`"parameter":\[`)),
+				),
+			},
+		},
+	})
+}
+
+// Test that a tag is looked up by name with its notes and nested parameters, and that
+// reading fails with the matching IDs when the name is not unique or with an error when
+// no tag has it
+func TestTagDataSource_lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tagmanager/v2/accounts/1/containers/2/workspaces/3/tags" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"tag": [
+			{"tagId": "4", "name": "GA4 Event", "type": "gaawe", "notes": "Managed in the UI",
+				"parameter": [{"key": "eventName", "type": "template", "value": "purchase"},
+					{"key": "eventParameters", "type": "list", "list": [{"type": "map", "map": [
+						{"key": "name", "type": "template", "value": "currency"},
+						{"key": "value", "type": "template", "value": "EUR"}]}]}]},
+			{"tagId": "5", "name": "Duplicate", "type": "html"},
+			{"tagId": "6", "name": "Duplicate", "type": "html"}]}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := testClientInWorkspace()
+	client.Client.Service = srv
+
+	ctx := context.Background()
+	d := &tagDataSource{client: client}
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	read := func(name string) (dataSourceTagModel, datasource.ReadResponse) {
+		config := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+		if diags := config.SetAttribute(ctx, path.Root("name"), name); diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+
+		resp := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: config.Raw.Copy()}}
+		d.Read(ctx, datasource.ReadRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config.Raw}}, &resp)
+
+		var state dataSourceTagModel
+		if !resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+		}
+
+		return state, resp
+	}
+
+	state, resp := read("GA4 Event")
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if state.Id.ValueString() != "4" || state.Type.ValueString() != "gaawe" || state.Notes.ValueString() != "Managed in the UI" {
+		t.Fatalf("unexpected tag: %+v", state)
+	}
+
+	if len(state.Parameter) != 2 || state.Parameter[1].List[0].Map[1].Value.ValueString() != "EUR" {
+		t.Fatalf("expected the nested parameters to be read, got %+v", state.Parameter)
+	}
+
+	if _, resp = read("Duplicate"); !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "5, 6") {
+		t.Fatalf("expected an error listing the matching IDs, got %v", resp.Diagnostics)
+	}
+
+	if _, resp = read("Missing"); !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Tag Not Found" {
+		t.Fatalf("expected a not found error, got %v", resp.Diagnostics)
+	}
+}
+
+// Test that the exported JSON keeps the keys of a GTM export and leaves out the
+// location of the entity
+func TestExportJson(t *testing.T) {
+	exported, err := exportJson(&tagmanager.Tag{
+		AccountId:       "1",
+		ContainerId:     "2",
+		WorkspaceId:     "3",
+		TagId:           "4",
+		Name:            "purchase",
+		Type:            "html",
+		Path:            "accounts/1/containers/2/workspaces/3/tags/4",
+		TagManagerUrl:   "https://tagmanager.google.com/#/container/accounts/1/containers/2/workspaces/3/tags/4",
+		FiringTriggerId: []string{"7"},
+		Parameter:       []*tagmanager.Parameter{{Key: "html", Type: "template", Value: "<script></script>"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(exported), &fields); err != nil {
+		t.Fatalf("expected valid JSON, got %s", exported)
+	}
+
+	for _, key := range []string{"accountId", "containerId", "tagId", "name", "type", "firingTriggerId", "parameter"} {
+		if _, ok := fields[key]; !ok {
+			t.Fatalf("expected key %s in %s", key, exported)
+		}
+	}
+
+	for _, key := range []string{"path", "tagManagerUrl", "workspaceId"} {
+		if _, ok := fields[key]; ok {
+			t.Fatalf("expected key %s to be left out of %s", key, exported)
+		}
+	}
+}
+
+func testAccTagDataSourceConfig() string {
+	return testAccProviderConfig() + `
+resource "gtm_tag" "lookup" {
+  name = "tf-test-tag-lookup"
+  type = "html"
+
+  parameter = [{
+    key   = "html"
+    type  = "template"
+    value = "<script></script>"
+  }]
+}
+
+data "gtm_tag" "lookup" {
+  name = gtm_tag.lookup.name
+}
+`
+}
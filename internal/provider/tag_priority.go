@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// GTM stores the firing priority of a tag as a 32-bit signed integer and rejects
+// values outside of its range.
+const (
+	minTagPriority = math.MinInt32
+	maxTagPriority = math.MaxInt32
+)
+
+var tagPrioritySchema = schema.Int64Attribute{
+	Description: "The firing priority of the tag. Tags with a higher priority fire first among the tags fired by the same trigger. Must be a 32-bit signed integer.",
+	Optional:    true,
+	Validators:  []validator.Int64{int64validator.Between(minTagPriority, maxTagPriority)},
+}
+
+// compileTagPriority returns the integer parameter GTM stores the priority of a tag in,
+// or nil when the priority is unset.
+func compileTagPriority(priority types.Int64) *tagmanager.Parameter {
+	if priority.IsNull() || priority.IsUnknown() {
+		return nil
+	}
+
+	return &tagmanager.Parameter{Type: "integer", Value: strconv.FormatInt(priority.ValueInt64(), 10)}
+}
+
+// decompileTagPriority reads the priority parameter of a tag back. A parameter without
+// an integer value is read as null like an unset priority.
+func decompileTagPriority(parameter *tagmanager.Parameter) types.Int64 {
+	if parameter == nil {
+		return types.Int64Null()
+	}
+
+	priority, err := strconv.ParseInt(parameter.Value, 10, 64)
+	if err != nil {
+		return types.Int64Null()
+	}
+
+	return types.Int64Value(priority)
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Test that priorities within the 32-bit range GTM accepts pass validation and that
+// those outside of it are rejected at plan time
+func TestTagPriority_bounds(t *testing.T) {
+	for _, tc := range []struct {
+		priority int64
+		valid    bool
+	}{
+		{priority: 0, valid: true},
+		{priority: -10, valid: true},
+		{priority: minTagPriority, valid: true},
+		{priority: maxTagPriority, valid: true},
+		{priority: maxTagPriority + 1, valid: false},
+		{priority: minTagPriority - 1, valid: false},
+	} {
+		var resp validator.Int64Response
+		for _, v := range tagPrioritySchema.Validators {
+			v.ValidateInt64(context.Background(), validator.Int64Request{
+				Path:        path.Root("priority"),
+				ConfigValue: types.Int64Value(tc.priority),
+			}, &resp)
+		}
+
+		if resp.Diagnostics.HasError() == tc.valid {
+			t.Fatalf("expected priority %d to be valid: %t, got %v", tc.priority, tc.valid, resp.Diagnostics)
+		}
+	}
+}
+
+// Test that the priority compiles to an integer parameter and reads back, including a
+// priority of 0, and that an unset priority stays unset
+func TestTagPriority_roundTrip(t *testing.T) {
+	for _, priority := range []int64{0, 5, -3} {
+		compiled := compileTagPriority(types.Int64Value(priority))
+		if compiled.Type != "integer" {
+			t.Fatalf("expected an integer parameter, got %+v", compiled)
+		}
+
+		if read := decompileTagPriority(compiled); read.ValueInt64() != priority || read.IsNull() {
+			t.Fatalf("expected priority %d to round-trip, got %s", priority, read)
+		}
+	}
+
+	if compiled := compileTagPriority(types.Int64Null()); compiled != nil {
+		t.Fatalf("expected an unset priority to be omitted, got %+v", compiled)
+	}
+
+	if read := decompileTagPriority(nil); !read.IsNull() {
+		t.Fatalf("expected a missing priority to be read as null, got %s", read)
+	}
+}
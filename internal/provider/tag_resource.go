@@ -3,20 +3,24 @@ package provider
 import (
 	"context"
 	"terraform-provider-google-tag-manager/internal/api"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/tagmanager/v2"
 )
 
 // Interace adoption checks
 var (
-	_ resource.Resource                = &tagResource{}
-	_ resource.ResourceWithConfigure   = &tagResource{}
-	_ resource.ResourceWithImportState = &tagResource{}
+	_ resource.Resource                   = &tagResource{}
+	_ resource.ResourceWithConfigure      = &tagResource{}
+	_ resource.ResourceWithImportState    = &tagResource{}
+	_ resource.ResourceWithValidateConfig = &tagResource{}
 )
 
 type tagResource struct {
@@ -28,12 +32,13 @@ func NewTagResource() resource.Resource {
 }
 
 // Configure adds the provider configured client to the resource.
-func (r *tagResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+func (r *tagResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
 	r.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(r.client, &resp.Diagnostics)
 }
 
 // Metadata returns the resource type name.
@@ -41,7 +46,7 @@ func (r *tagResource) Metadata(_ context.Context, req resource.MetadataRequest,
 	resp.TypeName = req.ProviderTypeName + "_tag"
 }
 
-var tagResourceSchemaAttributes = map[string]schema.Attribute{
+var tagResourceSchemaAttributes = withWorkspaceEntityAttributes(map[string]schema.Attribute{
 	"name": schema.StringAttribute{
 		Description: "The name of the tag.",
 		Required:    true},
@@ -53,14 +58,44 @@ var tagResourceSchemaAttributes = map[string]schema.Attribute{
 		Computed:    true},
 	"notes": schema.StringAttribute{
 		Description: "The notes associated with the tag.",
-		Optional:    true},
-	"parameter": parameterSchema,
+		Optional:    true,
+		Validators:  notesValidators},
+	"parameter":      parameterSchema,
+	"parameters_map": parametersMapSchema,
+	"user_property":  userPropertySchema,
+	"retry_limit":    retryLimitAttribute,
 	"firing_trigger_id": schema.ListAttribute{
-		Description: "The ID of the firing triggers associated with the tag.",
+		Description: "The ID of the firing triggers associated with the tag. Defaults to the provider default_firing_trigger_id when omitted.",
 		Optional:    true,
 		ElementType: types.StringType,
 	},
-}
+	"blocking_trigger_id": schema.ListAttribute{
+		Description: "The ID of the blocking triggers associated with the tag.",
+		Optional:    true,
+		ElementType: types.StringType,
+	},
+	"schedule_start": schema.StringAttribute{
+		Description: "The start of the period in which the tag fires, as an RFC3339 timestamp, e.g. 2030-01-01T00:00:00Z.",
+		Optional:    true,
+		Validators:  []validator.String{scheduleTimestampValidator{}},
+	},
+	"schedule_end": schema.StringAttribute{
+		Description: "The end of the period in which the tag fires, as an RFC3339 timestamp after schedule_start.",
+		Optional:    true,
+		Validators:  []validator.String{scheduleTimestampValidator{}},
+	},
+	"paused": schema.BoolAttribute{
+		Description: "Whether the tag is paused, which prevents it from firing.",
+		Optional:    true,
+	},
+	"live_only": schema.BoolAttribute{
+		Description: "Whether the tag only fires in the live environment, e.g. not in preview or debug mode.",
+		Optional:    true,
+	},
+	"priority":                         tagPrioritySchema,
+	"monitoring_metadata":              monitoringMetadataSchema,
+	"monitoring_metadata_tag_name_key": monitoringMetadataTagNameKeySchema,
+})
 
 // Schema defines the schema for the resource.
 func (r *tagResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -68,12 +103,74 @@ func (r *tagResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 }
 
 type resourceTagModel struct {
-	Name            types.String             `tfsdk:"name"`
-	Type            types.String             `tfsdk:"type"`
-	Id              types.String             `tfsdk:"id"`
-	Notes           types.String             `tfsdk:"notes"`
-	Parameter       []ResourceParameterModel `tfsdk:"parameter"`
-	FiringTriggerId []types.String           `tfsdk:"firing_trigger_id"`
+	Name              types.String                `tfsdk:"name"`
+	Type              types.String                `tfsdk:"type"`
+	Id                types.String                `tfsdk:"id"`
+	Notes             types.String                `tfsdk:"notes"`
+	Parameter         []ResourceParameterModel    `tfsdk:"parameter"`
+	ParametersMap     map[string]types.String     `tfsdk:"parameters_map"`
+	UserProperty      []ResourceUserPropertyModel `tfsdk:"user_property"`
+	FiringTriggerId   []types.String              `tfsdk:"firing_trigger_id"`
+	BlockingTriggerId []types.String              `tfsdk:"blocking_trigger_id"`
+	ScheduleStart     types.String                `tfsdk:"schedule_start"`
+	ScheduleEnd       types.String                `tfsdk:"schedule_end"`
+	Paused            types.Bool                  `tfsdk:"paused"`
+	LiveOnly          types.Bool                  `tfsdk:"live_only"`
+	Priority          types.Int64                 `tfsdk:"priority"`
+
+	MonitoringMetadata           map[string]types.String `tfsdk:"monitoring_metadata"`
+	MonitoringMetadataTagNameKey types.String            `tfsdk:"monitoring_metadata_tag_name_key"`
+	workspaceEntityModel
+	RetryLimit types.Int64 `tfsdk:"retry_limit"`
+}
+
+// deprecatedTagTypes are the tag types GTM has deprecated, with the replacement
+// suggested for them. Existing tags of these types are still read, imported and
+// updated as usual.
+var deprecatedTagTypes = map[string]string{
+	"ua": "Universal Analytics no longer processes data, use GA4 tags of type googtag and gaawe instead.",
+}
+
+// ValidateConfig checks that the schedule ends after it starts, and that user_property
+// is only used on GA4 event tags that do not also set the userProperties parameter
+// directly. Deprecated tag types only get a warning.
+func (r *tagResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var tagType, scheduleStart, scheduleEnd types.String
+	var userProperty, parameter types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &tagType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("user_property"), &userProperty)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("parameter"), &parameter)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("schedule_start"), &scheduleStart)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("schedule_end"), &scheduleEnd)...)
+
+	validateSchedule(scheduleStart, scheduleEnd, &resp.Diagnostics)
+
+	if replacement, ok := deprecatedTagTypes[tagType.ValueString()]; ok {
+		resp.Diagnostics.AddAttributeWarning(path.Root("type"), "Deprecated Tag Type",
+			"GTM has deprecated tags of type "+tagType.ValueString()+" and may no longer accept new ones. "+replacement)
+	}
+
+	if resp.Diagnostics.HasError() || userProperty.IsNull() {
+		return
+	}
+
+	if !tagType.IsUnknown() && tagType.ValueString() != "gaawe" {
+		resp.Diagnostics.AddAttributeError(path.Root("user_property"), "Unsupported User Properties",
+			"user_property is only supported on GA4 event tags of type gaawe, got "+tagType.ValueString()+".")
+	}
+
+	for _, element := range parameter.Elements() {
+		object, ok := element.(types.Object)
+		if !ok {
+			continue
+		}
+
+		if key, ok := object.Attributes()["key"].(types.String); ok && key.ValueString() == userPropertiesKey {
+			resp.Diagnostics.AddAttributeError(path.Root("user_property"), "Conflicting User Properties",
+				"user_property cannot be combined with a parameter with key "+userPropertiesKey+".")
+		}
+	}
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -87,13 +184,38 @@ func (r *tagResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
-	tag, err := r.client.CreateTag(toApiTag(plan, false))
+	client := withRequestLog(ctx, withRetryLimit(r.client, plan.RetryLimit), "tag", sensitiveValues(plan.Parameter)...)
+
+	resolver := newReferenceResolver(client)
+	parameter, err := resolver.resolveReferences(withParametersMap(plan.Parameter, plan.ParametersMap))
 	if err != nil {
-		resp.Diagnostics.AddError("Error Creating Tag", err.Error())
+		resp.Diagnostics.AddAttributeError(path.Root("parameter"), "Error Creating Tag", err.Error())
+		return
+	}
+	addUnknownVariableReferenceWarnings(resolver, parameter, &resp.Diagnostics)
+
+	resolved := plan
+	resolved.Parameter, resolved.ParametersMap = parameter, nil
+
+	dto := toApiTag(resolved, false)
+	dto.Name = withNamePrefix(client.Options.NamePrefix, dto.Name)
+	dto.Notes = withManagedByNote(managedByNote(client.Options, "tag", time.Now()), dto.Notes)
+	dto.FiringTriggerId = withDefaultFiringTrigger(client.Options.DefaultFiringTriggerId, plan.FiringTriggerId)
+
+	tag, err := client.CreateTag(dto)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating Tag", redactSensitiveValues(err.Error(), plan.Parameter))
+		return
+	}
+
+	diags = recordCreatedId(ctx, &resp.State, tag.TagId)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	plan.Id = types.StringValue(tag.TagId)
+	plan.workspaceEntityModel = workspaceEntityLocation(client, "tags", tag.TagId)
 
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -110,16 +232,41 @@ func (r *tagResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		return
 	}
 
-	tag, err := r.client.Tag(state.Id.ValueString())
+	client := withRequestLog(ctx, withRetryLimit(r.client, state.RetryLimit), "tag", sensitiveValues(state.Parameter)...)
+
+	tag, err := client.Tag(state.Id.ValueString(), tagReadFields)
 	if err == api.ErrNotExist {
 		resp.State.RemoveResource(ctx)
+		warnWorkspaceEmptied(client, "Tag", state.Id.ValueString(), &resp.Diagnostics)
 		return
 	} else if err != nil {
 		resp.Diagnostics.AddError("Error Reading Tag", err.Error())
 		return
 	}
 
-	var resource = toResourceTag(tag)
+	var resource = toResourceTag(tag, client)
+	resource.Name = withoutNamePrefix(client.Options.NamePrefix, tag.Name, state.Name)
+	resource.Notes = withoutManagedByNote(remoteManagedByNote(client.Options, "tag", tag.Notes), tag.Notes, state.Notes)
+	if !hasParameter(state.Parameter, userPropertiesKey) {
+		resource.Parameter, resource.UserProperty = decompileUserProperties(resource.Parameter)
+	}
+	if state.ParametersMap != nil {
+		if parametersMap, ok := decompileParametersMap(resource.Parameter); ok {
+			resource.Parameter, resource.ParametersMap = nil, parametersMap
+		}
+	}
+	resource.Parameter = alignParameterOrder(resource.Parameter, state.Parameter)
+	resource.Parameter = keepEmptyValues(resource.Parameter, state.Parameter)
+	resource.Parameter = newReferenceResolver(client).keepReferenceNames(resource.Parameter, state.Parameter)
+	resource.Parameter = keepSensitiveValues(resource.Parameter, state.Parameter)
+	resource.FiringTriggerId = withoutDefaultFiringTrigger(client.Options.DefaultFiringTriggerId, tag.FiringTriggerId, state.FiringTriggerId)
+	resource.FiringTriggerId = alignStringOrder(resource.FiringTriggerId, state.FiringTriggerId)
+	resource.BlockingTriggerId = alignStringOrder(resource.BlockingTriggerId, state.BlockingTriggerId)
+	resource.ScheduleStart = toResourceSchedule(tag.ScheduleStartMs, state.ScheduleStart)
+	resource.ScheduleEnd = toResourceSchedule(tag.ScheduleEndMs, state.ScheduleEnd)
+	resource.Paused = nullableBoolValue(tag.Paused, state.Paused)
+	resource.LiveOnly = nullableBoolValue(tag.LiveOnly, state.LiveOnly)
+	resource.RetryLimit = state.RetryLimit
 
 	diags = resp.State.Set(ctx, &resource)
 	resp.Diagnostics.Append(diags...)
@@ -139,20 +286,69 @@ func (r *tagResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		return
 	}
 
-	tag, err := r.client.UpdateTag(state.Id.ValueString(), toApiTag(plan, true))
+	client := withRequestLog(ctx, withRetryLimit(r.client, plan.RetryLimit), "tag", sensitiveValues(plan.Parameter)...)
+
+	resolver := newReferenceResolver(client)
+	parameter, err := resolver.resolveReferences(withParametersMap(plan.Parameter, plan.ParametersMap))
 	if err != nil {
-		resp.Diagnostics.AddError("Error Updating Tag", err.Error())
+		resp.Diagnostics.AddAttributeError(path.Root("parameter"), "Error Updating Tag", err.Error())
+		return
+	}
+	addUnknownVariableReferenceWarnings(resolver, parameter, &resp.Diagnostics)
+
+	resolved := plan
+	resolved.Parameter, resolved.ParametersMap = parameter, nil
+
+	dto := toApiTag(resolved, true)
+	dto.Name = withNamePrefix(client.Options.NamePrefix, dto.Name)
+	dto.Notes = withManagedByNote(managedByNote(client.Options, "tag", time.Now()), dto.Notes)
+	dto.FiringTriggerId = withDefaultFiringTrigger(client.Options.DefaultFiringTriggerId, plan.FiringTriggerId)
+
+	tag, err := client.UpdateTag(state.Id.ValueString(), dto)
+	if err != nil {
+		detail := redactSensitiveValues(err.Error(), plan.Parameter)
+		if replacement, ok := deprecatedTagTypes[plan.Type.ValueString()]; ok {
+			detail += "\n\nThe tag is of the deprecated type " + plan.Type.ValueString() + ". " + replacement
+		}
+		resp.Diagnostics.AddError("Error Updating Tag", detail)
 		return
 	}
 
 	plan.Id = types.StringValue(tag.TagId)
+	plan.workspaceEntityModel = workspaceEntityLocation(client, "tags", tag.TagId)
 
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 }
 
+// ImportState reads the whole tag so that firing_trigger_id and blocking_trigger_id
+// are populated with the raw trigger ids right away. They are the correct state;
+// configurations are expected to replace them with references to the matching
+// gtm_trigger resources after import. The import id is the tag id or the full GTM path
+// of the tag.
 func (r *tagResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	id, err := workspaceImportId(r.client, "tags", req.ID)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("id"), "Error Importing Tag", err.Error())
+		return
+	}
+
+	tag, err := r.client.Tag(id)
+	if err == api.ErrNotExist {
+		resp.Diagnostics.AddAttributeError(path.Root("id"), "Error Importing Tag", "Tag "+id+" does not exist in the workspace.")
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Importing Tag", err.Error())
+		return
+	}
+
+	var resource = toResourceTag(tag, r.client)
+	resource.Name = withoutNamePrefix(r.client.Options.NamePrefix, tag.Name, types.StringNull())
+	resource.Notes = withoutManagedByNote(remoteManagedByNote(r.client.Options, "tag", tag.Notes), tag.Notes, types.StringNull())
+	resource.Parameter, resource.UserProperty = decompileUserProperties(resource.Parameter)
+
+	diags := resp.State.Set(ctx, &resource)
+	resp.Diagnostics.Append(diags...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -166,11 +362,13 @@ func (r *tagResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		return
 	}
 
+	client := withRequestLog(ctx, withRetryLimit(r.client, state.RetryLimit), "tag", sensitiveValues(state.Parameter)...)
+
 	if state.Id.IsNull() || state.Id.IsUnknown() {
 		resp.Diagnostics.AddError("Invalid Id state", state.Id.String())
 	}
 
-	err := r.client.DeleteTag(state.Id.ValueString())
+	err := client.DeleteTag(state.Id.ValueString())
 	if err == api.ErrNotExist {
 		return
 	} else if err != nil {
@@ -185,8 +383,18 @@ func (m resourceTagModel) Equal(o resourceTagModel) bool {
 		!m.Type.Equal(o.Type) ||
 		(!m.Id.IsUnknown() && !m.Id.Equal(o.Id)) ||
 		!m.Notes.Equal(o.Notes) ||
+		!m.ScheduleStart.Equal(o.ScheduleStart) ||
+		!m.ScheduleEnd.Equal(o.ScheduleEnd) ||
+		!m.Paused.Equal(o.Paused) ||
+		!m.LiveOnly.Equal(o.LiveOnly) ||
+		!m.Priority.Equal(o.Priority) ||
+		!m.MonitoringMetadataTagNameKey.Equal(o.MonitoringMetadataTagNameKey) ||
+		len(m.MonitoringMetadata) != len(o.MonitoringMetadata) ||
+		!equalParametersMap(m.ParametersMap, o.ParametersMap) ||
 		len(m.Parameter) != len(o.Parameter) ||
-		len(m.FiringTriggerId) != len(o.FiringTriggerId) {
+		len(m.UserProperty) != len(o.UserProperty) ||
+		len(m.FiringTriggerId) != len(o.FiringTriggerId) ||
+		len(m.BlockingTriggerId) != len(o.BlockingTriggerId) {
 		return false
 	}
 
@@ -196,44 +404,149 @@ func (m resourceTagModel) Equal(o resourceTagModel) bool {
 		}
 	}
 
+	for i := range m.UserProperty {
+		if !m.UserProperty[i].Name.Equal(o.UserProperty[i].Name) || !m.UserProperty[i].Value.Equal(o.UserProperty[i].Value) {
+			return false
+		}
+	}
+
 	for i := range m.FiringTriggerId {
 		if !m.FiringTriggerId[i].Equal(o.FiringTriggerId[i]) {
 			return false
 		}
 	}
 
+	for i := range m.BlockingTriggerId {
+		if !m.BlockingTriggerId[i].Equal(o.BlockingTriggerId[i]) {
+			return false
+		}
+	}
+
+	for key, value := range m.MonitoringMetadata {
+		if other, ok := o.MonitoringMetadata[key]; !ok || !value.Equal(other) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// withDefaultFiringTrigger returns the firing triggers sent to GTM for the configured
+// ones. A tag that omits firing_trigger_id gets the provider's default_firing_trigger_id,
+// an explicit empty list is kept.
+func withDefaultFiringTrigger(defaults []string, configured []types.String) []string {
+	if configured == nil {
+		return defaults
+	}
+
+	return unwrapStringArray(configured)
+}
+
+// withoutDefaultFiringTrigger returns the firing triggers to keep in state for the ones
+// stored in GTM. The default_firing_trigger_id added by withDefaultFiringTrigger is read
+// back as the null it replaced and an explicit empty list stays empty, so that neither
+// shows a diff.
+func withoutDefaultFiringTrigger(defaults []string, remote []string, current []types.String) []types.String {
+	if current == nil && len(defaults) > 0 && sameStringSet(remote, defaults) {
+		return nil
+	}
+
+	if current != nil && len(current) == 0 && len(remote) == 0 {
+		return current
+	}
+
+	return toResourceStringArray(remote)
+}
+
+// sameStringSet reports whether a and b hold the same values, in any order.
+func sameStringSet(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	var counts = map[string]int{}
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		if counts[v] == 0 {
+			return false
+		}
+		counts[v]--
+	}
+
 	return true
 }
 
-func toResourceTag(tag *tagmanager.Tag) resourceTagModel {
+// tagReadFields are the fields of a tag that toResourceTag maps, the only
+// ones requested when the tag is read.
+const tagReadFields googleapi.Field = "tagId,name,type,notes,parameter,firingTriggerId,blockingTriggerId,scheduleStartMs,scheduleEndMs,paused,liveOnly,priority,monitoringMetadata,monitoringMetadataTagNameKey"
+
+func toResourceTag(tag *tagmanager.Tag, client *api.ClientInWorkspace) resourceTagModel {
 	return resourceTagModel{
-		Name:            types.StringValue(tag.Name),
-		Type:            types.StringValue(tag.Type),
-		Id:              types.StringValue(tag.TagId),
-		Notes:           nullableStringValue(tag.Notes),
-		Parameter:       toResourceParameter(tag.Parameter),
-		FiringTriggerId: toResourceStringArray(tag.FiringTriggerId),
+		Name:              types.StringValue(tag.Name),
+		Type:              types.StringValue(tag.Type),
+		Id:                types.StringValue(tag.TagId),
+		Notes:             nullableStringValue(tag.Notes),
+		Parameter:         toResourceParameter(tag.Parameter),
+		FiringTriggerId:   toResourceStringArray(tag.FiringTriggerId),
+		BlockingTriggerId: toResourceStringArray(tag.BlockingTriggerId),
+		ScheduleStart:     toResourceSchedule(tag.ScheduleStartMs, types.StringNull()),
+		ScheduleEnd:       toResourceSchedule(tag.ScheduleEndMs, types.StringNull()),
+		Paused:            nullableBoolValue(tag.Paused, types.BoolNull()),
+		LiveOnly:          nullableBoolValue(tag.LiveOnly, types.BoolNull()),
+		Priority:          decompileTagPriority(tag.Priority),
+
+		MonitoringMetadata:           decompileMonitoringMetadata(tag.MonitoringMetadata),
+		MonitoringMetadataTagNameKey: nullableStringValue(tag.MonitoringMetadataTagNameKey),
+		workspaceEntityModel:         workspaceEntityLocation(client, "tags", tag.TagId),
 	}
 
 }
 
 func toApiTag(resource resourceTagModel, id bool) *tagmanager.Tag {
+	var parameter = resource.Parameter
+	if resource.ParametersMap != nil {
+		parameter = compileParametersMap(resource.ParametersMap)
+	}
+	if len(resource.UserProperty) > 0 {
+		parameter = append(append([]ResourceParameterModel{}, parameter...), compileUserProperties(resource.UserProperty))
+	}
+
 	if !id {
 		return &tagmanager.Tag{
-			Name:            resource.Name.ValueString(),
-			Type:            resource.Type.ValueString(),
-			Notes:           resource.Notes.ValueString(),
-			Parameter:       toApiParameter(resource.Parameter),
-			FiringTriggerId: unwrapStringArray(resource.FiringTriggerId),
+			Name:              resource.Name.ValueString(),
+			Type:              resource.Type.ValueString(),
+			Notes:             resource.Notes.ValueString(),
+			Parameter:         toApiParameter(parameter),
+			FiringTriggerId:   unwrapStringArray(resource.FiringTriggerId),
+			BlockingTriggerId: unwrapStringArray(resource.BlockingTriggerId),
+			ScheduleStartMs:   toApiScheduleMs(resource.ScheduleStart),
+			ScheduleEndMs:     toApiScheduleMs(resource.ScheduleEnd),
+			Paused:            resource.Paused.ValueBool(),
+			LiveOnly:          resource.LiveOnly.ValueBool(),
+			Priority:          compileTagPriority(resource.Priority),
+
+			MonitoringMetadata:           compileMonitoringMetadata(resource.MonitoringMetadata),
+			MonitoringMetadataTagNameKey: resource.MonitoringMetadataTagNameKey.ValueString(),
 		}
 	}
 
 	return &tagmanager.Tag{
-		Name:            resource.Name.ValueString(),
-		Type:            resource.Type.ValueString(),
-		TagId:           resource.Id.String(),
-		Notes:           resource.Notes.ValueString(),
-		Parameter:       toApiParameter(resource.Parameter),
-		FiringTriggerId: unwrapStringArray(resource.FiringTriggerId),
+		Name:              resource.Name.ValueString(),
+		Type:              resource.Type.ValueString(),
+		TagId:             resource.Id.String(),
+		Notes:             resource.Notes.ValueString(),
+		Parameter:         toApiParameter(parameter),
+		FiringTriggerId:   unwrapStringArray(resource.FiringTriggerId),
+		BlockingTriggerId: unwrapStringArray(resource.BlockingTriggerId),
+		ScheduleStartMs:   toApiScheduleMs(resource.ScheduleStart),
+		ScheduleEndMs:     toApiScheduleMs(resource.ScheduleEnd),
+		Paused:            resource.Paused.ValueBool(),
+		LiveOnly:          resource.LiveOnly.ValueBool(),
+		Priority:          compileTagPriority(resource.Priority),
+
+		MonitoringMetadata:           compileMonitoringMetadata(resource.MonitoringMetadata),
+		MonitoringMetadataTagNameKey: resource.MonitoringMetadataTagNameKey.ValueString(),
 	}
 }
@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"terraform-provider-google-tag-manager/internal/api"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"google.golang.org/api/option"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// TestAccTagResource_driftDetection tests that changes made outside Terraform show up in the next plan
+func TestAccTagResource_driftDetection(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	var tagId string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagResourceDriftConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCaptureTagId("gtm_tag.drift", &tagId),
+					resource.TestCheckResourceAttr("gtm_tag.drift", "notes", "Managed by Terraform"),
+				),
+			},
+			{
+				PreConfig: func() {
+					testAccMutateTagNotes(t, tagId, "Changed in the GTM UI")
+				},
+				Config:             testAccTagResourceDriftConfig(),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccTagResourceDriftConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_tag.drift", "notes", "Managed by Terraform"),
+				),
+			},
+		},
+	})
+}
+
+// Test that a tag gone from a workspace without pending changes, as after the workspace
+// was published and reset, is removed from the state with a warning, and without one
+// when the workspace has other changes
+func TestTagResource_workspaceEmptied(t *testing.T) {
+	for _, tc := range []struct {
+		status   string
+		warnings int
+	}{
+		{status: `{}`, warnings: 1},
+		{status: `{"workspaceChange": [{"changeStatus": "added", "tag": {"tagId": "5"}}]}`, warnings: 0},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/tagmanager/v2/accounts/1/containers/2/workspaces/3/tags/4":
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error": {"code": 404, "message": "Not found"}}`))
+			case "/tagmanager/v2/accounts/1/containers/2/workspaces/3/status":
+				_, _ = w.Write([]byte(tc.status))
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			}
+		}))
+
+		srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		client := testClientInWorkspace()
+		client.Client.Service = srv
+
+		ctx := context.Background()
+		r := &tagResource{client: client}
+
+		var schemaResp fwresource.SchemaResponse
+		r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+		state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+		if diags := state.SetAttribute(ctx, path.Root("id"), "4"); diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+
+		resp := fwresource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: state.Raw.Copy()}}
+		r.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+		server.Close()
+
+		if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != tc.warnings {
+			t.Fatalf("expected %d warnings for status %s, got %v", tc.warnings, tc.status, resp.Diagnostics)
+		}
+
+		if !resp.State.Raw.IsNull() {
+			t.Fatalf("expected the tag to be removed from the state, got %v", resp.State.Raw)
+		}
+	}
+}
+
+// TestAccTagResource_pausedDrift tests that pausing a tag in the GTM UI shows up in the next plan
+func TestAccTagResource_pausedDrift(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	var tagId string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagResourceDriftConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCaptureTagId("gtm_tag.drift", &tagId),
+					resource.TestCheckNoResourceAttr("gtm_tag.drift", "paused"),
+				),
+			},
+			{
+				PreConfig: func() {
+					testAccMutateTag(t, tagId, func(tag *tagmanager.Tag) { tag.Paused = true })
+				},
+				Config:             testAccTagResourceDriftConfig(),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccTagResourceDriftConfig(),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gtm_tag.drift", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("gtm_tag.drift", "paused"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCaptureTagId stores the ID of the tag in state for use in later steps
+func testAccCaptureTagId(resourceName string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Tag resource not found: %s", resourceName)
+		}
+
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
+// testAccMutateTagNotes changes the notes of a tag directly through the GTM API
+func testAccMutateTagNotes(t *testing.T, tagId string, notes string) {
+	testAccMutateTag(t, tagId, func(tag *tagmanager.Tag) { tag.Notes = notes })
+}
+
+// testAccMutateTag changes a tag directly through the GTM API
+func testAccMutateTag(t *testing.T, tagId string, mutate func(tag *tagmanager.Tag)) {
+	client, err := api.NewClientInWorkspaceFromEnv()
+	if err != nil {
+		t.Fatalf("Failed to create client in workspace: %v", err)
+	}
+
+	tag, err := client.Tag(tagId)
+	if err != nil {
+		t.Fatalf("Failed to read tag %s: %v", tagId, err)
+	}
+
+	mutate(tag)
+	if _, err := client.UpdateTag(tagId, tag); err != nil {
+		t.Fatalf("Failed to update tag %s: %v", tagId, err)
+	}
+}
+
+func testAccTagResourceDriftConfig() string {
+	return testAccProviderConfig() + `
+resource "gtm_tag" "drift" {
+  name  = "tf-test-tag-drift"
+  type  = "html"
+  notes = "Managed by Terraform"
+
+  parameter = [
+    {
+      key   = "html"
+      type  = "template"
+      value = "<p>Drift</p>"
+    }
+  ]
+}
+`
+}
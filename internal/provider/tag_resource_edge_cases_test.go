@@ -1,6 +1,9 @@
 package provider
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -42,6 +45,42 @@ func TestAccTagResource_emptyParameters(t *testing.T) {
 	})
 }
 
+// TestAccTagResource_emptyParameterValue tests that a parameter value toggled between
+// empty and absent shows no diff once applied, although GTM omits empty values
+func TestAccTagResource_emptyParameterValue(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagResourceEmptyParameterValueConfig(`value = ""`),
+				Check:  resource.TestCheckResourceAttr("gtm_tag.empty_value", "parameter.2.value", ""),
+			},
+			{
+				Config:   testAccTagResourceEmptyParameterValueConfig(`value = ""`),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccTagResourceEmptyParameterValueConfig(""),
+				Check:  resource.TestCheckNoResourceAttr("gtm_tag.empty_value", "parameter.2.value"),
+			},
+			{
+				Config:   testAccTagResourceEmptyParameterValueConfig(""),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccTagResourceEmptyParameterValueConfig(`value = ""`),
+			},
+			{
+				Config:   testAccTagResourceEmptyParameterValueConfig(`value = ""`),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 // TestAccTagResource_longName tests tag with maximum length name
 func TestAccTagResource_longName(t *testing.T) {
 	testAccPreCheck(t)
@@ -147,6 +186,52 @@ func TestAccTagResource_removeAllParameters(t *testing.T) {
 	})
 }
 
+// TestAccTagResource_notesTooLong tests that over-length notes are rejected at plan time
+func TestAccTagResource_notesTooLong(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccTagResourceNotesTooLongConfig(),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Length`),
+			},
+		},
+	})
+}
+
+// TestAccTagResource_markdownNotes tests that multi-line markdown notes are stored and
+// read back byte for byte
+func TestAccTagResource_markdownNotes(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagResourceNotesConfig(testMarkdownNotes),
+				Check:  resource.TestCheckResourceAttr("gtm_tag.notes", "notes", testMarkdownNotes),
+			},
+			{
+				Config:   testAccTagResourceNotesConfig(testMarkdownNotes),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccTagResourceNotesConfig(""),
+				Check:  resource.TestCheckResourceAttr("gtm_tag.notes", "notes", ""),
+			},
+			{
+				Config:   testAccTagResourceNotesConfig(""),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 // Configuration functions
 
 func testAccTagResourceInvalidTypeConfig() string {
@@ -176,6 +261,33 @@ resource "gtm_tag" "empty_params" {
 `
 }
 
+func testAccTagResourceEmptyParameterValueConfig(value string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "gtm_tag" "empty_value" {
+  name = "tf-test-tag-empty-value"
+  type = "img"
+
+  parameter = [
+    {
+      key   = "url"
+      type  = "template"
+      value = "https://example.com/pixel.gif"
+    },
+    {
+      key   = "useCacheBuster"
+      type  = "boolean"
+      value = "false"
+    },
+    {
+      key  = "cacheBusterQueryParam"
+      type = "template"
+      %s
+    }
+  ]
+}
+`, value)
+}
+
 func testAccTagResourceLongNameConfig() string {
 	return testAccProviderConfig() + `
 resource "gtm_tag" "long_name" {
@@ -331,3 +443,31 @@ resource "gtm_tag" "multiple_triggers" {
 }
 `
 }
+
+func testAccTagResourceNotesTooLongConfig() string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "gtm_tag" "notes_too_long" {
+  name  = "tf-test-tag-notes-too-long"
+  type  = "html"
+  notes = "%s"
+}
+`, strings.Repeat("a", maxNotesLength+1))
+}
+
+func testAccTagResourceNotesConfig(notes string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "gtm_tag" "notes" {
+  name  = "tf-test-tag-notes"
+  type  = "html"
+  notes = %q
+
+  parameter = [
+    {
+      key   = "html"
+      type  = "template"
+      value = "<script>console.log('notes');</script>"
+    }
+  ]
+}
+`, notes)
+}
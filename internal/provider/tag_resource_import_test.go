@@ -2,6 +2,9 @@ package provider
 
 import (
 	"fmt"
+	"os"
+	"regexp"
+	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -109,10 +112,36 @@ func TestAccTagResource_importComplexTag(t *testing.T) {
 				ResourceName:      "gtm_tag.complex_original",
 				ImportState:       true,
 				ImportStateVerify: true,
-				ImportStateVerifyIgnore: []string{
-					// Complex nested parameters might have ordering differences
-					"parameter",
-				},
+			},
+		},
+	})
+}
+
+// TestAccTagResource_importMonitoringMetadata tests that the monitoring metadata of a
+// tag survives import and shows no drift afterwards
+func TestAccTagResource_importMonitoringMetadata(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagResourceMonitoringMetadataConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_tag.monitored", "monitoring_metadata.%", "2"),
+					resource.TestCheckResourceAttr("gtm_tag.monitored", "monitoring_metadata.team", "analytics"),
+					resource.TestCheckResourceAttr("gtm_tag.monitored", "monitoring_metadata_tag_name_key", "tag_name"),
+				),
+			},
+			{
+				ResourceName:      "gtm_tag.monitored",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:   testAccTagResourceMonitoringMetadataConfig(),
+				PlanOnly: true,
 			},
 		},
 	})
@@ -139,8 +168,66 @@ func TestAccTagResource_importWithTriggers(t *testing.T) {
 				ResourceName:      "gtm_tag.with_triggers_original",
 				ImportState:       true,
 				ImportStateVerify: true,
-				ImportStateVerifyIgnore: []string{
-					"firing_trigger_id", // Trigger IDs might be referenced differently after import
+				ImportStateCheck:  testAccCheckImportedTriggerIds("firing_trigger_id", 1),
+			},
+		},
+	})
+}
+
+// TestAccTagResource_importFromOtherContainer tests that importing a tag by the path of another container fails
+func TestAccTagResource_importFromOtherContainer(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagResourceBasicConfig(),
+			},
+			{
+				ResourceName: "gtm_tag.basic",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["gtm_tag.basic"]
+					if !ok {
+						return "", fmt.Errorf("Tag resource not found: gtm_tag.basic")
+					}
+
+					return fmt.Sprintf("accounts/%s/containers/1/workspaces/%s/tags/%s",
+						os.Getenv("GTM_ACCOUNT_ID"), rs.Primary.Attributes["workspace_id"], rs.Primary.ID), nil
+				},
+				ExpectError: regexp.MustCompile("does not belong to the configured workspace"),
+			},
+		},
+	})
+}
+
+// TestAccTagResource_importWithBlockingTriggers tests that importing a tag keeps its firing and blocking triggers apart
+func TestAccTagResource_importWithBlockingTriggers(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagResourceWithBlockingTriggersForImportConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("gtm_tag.with_blocking_triggers", "firing_trigger_id.0", "gtm_trigger.import_firing", "id"),
+					resource.TestCheckResourceAttrPair("gtm_tag.with_blocking_triggers", "blocking_trigger_id.0", "gtm_trigger.import_blocking", "id"),
+				),
+			},
+			{
+				ResourceName:      "gtm_tag.with_blocking_triggers",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if err := testAccCheckImportedTriggerIds("firing_trigger_id", 1)(states); err != nil {
+						return err
+					}
+
+					return testAccCheckImportedTriggerIds("blocking_trigger_id", 1)(states)
 				},
 			},
 		},
@@ -352,11 +439,23 @@ resource "gtm_tag" "complex_original" {
         type = "map"
         
         map = [{
-          key   = "custom_parameter_1"
+          key   = "name"
+          type  = "template"
+          value = "custom_parameter_1"
+        }, {
+          key   = "value"
           type  = "template"
           value = "import_value_1"
+        }]
+      }, {
+        type = "map"
+        
+        map = [{
+          key   = "name"
+          type  = "template"
+          value = "custom_parameter_2"
         }, {
-          key   = "custom_parameter_2"
+          key   = "value"
           type  = "template"
           value = "import_value_2"
         }]
@@ -367,6 +466,29 @@ resource "gtm_tag" "complex_original" {
 `
 }
 
+func testAccTagResourceMonitoringMetadataConfig() string {
+	return testAccProviderConfig() + `
+resource "gtm_tag" "monitored" {
+  name = "tf-test-monitored-tag"
+  type = "html"
+
+  parameter = [
+    {
+      key   = "html"
+      type  = "template"
+      value = "<script>console.log('monitored');</script>"
+    }
+  ]
+
+  monitoring_metadata = {
+    team     = "analytics"
+    page_url = "{{Page URL}}"
+  }
+  monitoring_metadata_tag_name_key = "tag_name"
+}
+`
+}
+
 func testAccTagResourceWithTriggersForImportConfig() string {
 	return testAccProviderConfig() + `
 # Create a trigger first
@@ -409,6 +531,36 @@ resource "gtm_tag" "with_triggers_original" {
 `
 }
 
+func testAccTagResourceWithBlockingTriggersForImportConfig() string {
+	return testAccProviderConfig() + `
+resource "gtm_trigger" "import_firing" {
+  name = "tf-test-trigger-firing-for-import"
+  type = "pageview"
+}
+
+resource "gtm_trigger" "import_blocking" {
+  name = "tf-test-trigger-blocking-for-import"
+  type = "pageview"
+}
+
+resource "gtm_tag" "with_blocking_triggers" {
+  name = "tf-test-tag-with-blocking-triggers-for-import"
+  type = "html"
+
+  parameter = [
+    {
+      key   = "html"
+      type  = "template"
+      value = "<script>console.log('Tag with blocking triggers');</script>"
+    }
+  ]
+
+  firing_trigger_id   = [gtm_trigger.import_firing.id]
+  blocking_trigger_id = [gtm_trigger.import_blocking.id]
+}
+`
+}
+
 func testAccTagResourceInitialForLifecycleConfig() string {
 	return testAccProviderConfig() + `
 resource "gtm_tag" "lifecycle_test" {
@@ -467,3 +619,25 @@ resource "gtm_tag" "lifecycle_test" {
 }
 `
 }
+
+// testAccCheckImportedTriggerIds checks that the imported tag carries the raw ids of its triggers in attribute
+func testAccCheckImportedTriggerIds(attribute string, count int) resource.ImportStateCheckFunc {
+	return func(states []*terraform.InstanceState) error {
+		if len(states) != 1 {
+			return fmt.Errorf("expected 1 imported state, got %d", len(states))
+		}
+
+		attributes := states[0].Attributes
+		if attributes[attribute+".#"] != strconv.Itoa(count) {
+			return fmt.Errorf("expected %d %s values, got %s", count, attribute, attributes[attribute+".#"])
+		}
+
+		for i := 0; i < count; i++ {
+			if attributes[fmt.Sprintf("%s.%d", attribute, i)] == "" {
+				return fmt.Errorf("expected %s.%d to be populated", attribute, i)
+			}
+		}
+
+		return nil
+	}
+}
@@ -1,11 +1,25 @@
 package provider
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"terraform-provider-google-tag-manager/internal/api"
 	"testing"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"google.golang.org/api/option"
+	"google.golang.org/api/tagmanager/v2"
 )
 
 // Test basic tag creation and reading
@@ -283,6 +297,81 @@ func TestAccTagResource_parameterUpdate(t *testing.T) {
 	})
 }
 
+// Test that the provider name prefix is applied in GTM but kept out of state
+func TestAccTagResource_namePrefix(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagResourceNamePrefixConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_tag.prefixed", "name", "tf-test-tag-prefixed"),
+					testAccCheckTagRemoteName("gtm_tag.prefixed", "tf-prefix-tf-test-tag-prefixed"),
+				),
+			},
+		},
+	})
+}
+
+// Test that managed_by_note is set on tags without notes, once, and never replaces configured notes
+func TestAccTagResource_managedByNote(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagResourceManagedByNoteConfig("<p>Managed</p>", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("gtm_tag.managed", "notes"),
+					testAccCheckTagRemoteNotes("gtm_tag.managed", "Managed by Terraform"),
+				),
+			},
+			{
+				Config: testAccTagResourceManagedByNoteConfig("<p>Updated</p>", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("gtm_tag.managed", "notes"),
+					testAccCheckTagRemoteNotes("gtm_tag.managed", "Managed by Terraform"),
+				),
+			},
+			{
+				Config: testAccTagResourceManagedByNoteConfig("<p>Updated</p>", `notes = "Owned by analytics"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_tag.managed", "notes", "Owned by analytics"),
+					testAccCheckTagRemoteNotes("gtm_tag.managed", "Owned by analytics"),
+				),
+			},
+		},
+	})
+}
+
+// Test that retry_limit on one tag overrides the provider retry limit without a diff
+func TestAccTagResource_retryLimit(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagResourceRetryLimitConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckTagExists("gtm_tag.retry_limit"),
+					resource.TestCheckResourceAttr("gtm_tag.retry_limit", "retry_limit", "20"),
+				),
+			},
+			{
+				Config:   testAccTagResourceRetryLimitConfig(),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 // Helper functions for testing
 
 // testAccCheckTagExists verifies a tag exists in GTM
@@ -313,6 +402,58 @@ func testAccCheckTagDestroy(resourceName string) resource.TestCheckFunc {
 	}
 }
 
+// testAccCheckTagRemoteName verifies the name of the tag as stored in GTM
+func testAccCheckTagRemoteName(resourceName string, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Tag resource not found: %s", resourceName)
+		}
+
+		client, err := api.NewClientInWorkspaceFromEnv()
+		if err != nil {
+			return err
+		}
+
+		tag, err := client.Tag(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if tag.Name != name {
+			return fmt.Errorf("expected tag name %q in GTM, got %q", name, tag.Name)
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckTagRemoteNotes verifies the notes of the tag as stored in GTM
+func testAccCheckTagRemoteNotes(resourceName string, notes string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Tag resource not found: %s", resourceName)
+		}
+
+		client, err := api.NewClientInWorkspaceFromEnv()
+		if err != nil {
+			return err
+		}
+
+		tag, err := client.Tag(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if tag.Notes != notes {
+			return fmt.Errorf("expected tag notes %q in GTM, got %q", notes, tag.Notes)
+		}
+
+		return nil
+	}
+}
+
 // Configuration functions
 
 func testAccTagResourceBasicConfig() string {
@@ -612,3 +753,151 @@ resource "gtm_tag" "minimal" {
 }
 `
 }
+
+func testAccTagResourceNamePrefixConfig() string {
+	return strings.Replace(testAccProviderConfig(), "provider \"gtm\" {", "provider \"gtm\" {\n  name_prefix     = \"tf-prefix-\"", 1) + `
+resource "gtm_tag" "prefixed" {
+  name = "tf-test-tag-prefixed"
+  type = "html"
+
+  parameter = [
+    {
+      key   = "html"
+      type  = "template"
+      value = "<p>Prefixed</p>"
+    }
+  ]
+}
+`
+}
+
+func testAccTagResourceManagedByNoteConfig(html string, notes string) string {
+	return strings.Replace(testAccProviderConfig(), "provider \"gtm\" {", "provider \"gtm\" {\n  managed_by_note = \"Managed by Terraform\"", 1) + fmt.Sprintf(`
+resource "gtm_tag" "managed" {
+  name = "tf-test-tag-managed"
+  type = "html"
+  %s
+
+  parameter = [
+    {
+      key   = "html"
+      type  = "template"
+      value = %q
+    }
+  ]
+}
+`, notes, html)
+}
+
+func testAccTagResourceRetryLimitConfig() string {
+	return strings.Replace(testAccProviderConfig(), "provider \"gtm\" {", "provider \"gtm\" {\n  retry_limit     = 1", 1) + `
+resource "gtm_tag" "retry_limit" {
+  name        = "tf-test-tag-retry-limit"
+  type        = "html"
+  retry_limit = 20
+
+  parameter = [
+    {
+      key   = "html"
+      type  = "template"
+      value = "<p>Retried</p>"
+    }
+  ]
+}
+`
+}
+
+// Test that default_firing_trigger_id only applies to tags that omit firing_trigger_id
+// and is read back as null, while an explicit empty list overrides it
+func TestDefaultFiringTrigger(t *testing.T) {
+	defaults := []string{"2147479553"}
+
+	if sent := withDefaultFiringTrigger(defaults, nil); len(sent) != 1 || sent[0] != defaults[0] {
+		t.Fatalf("expected an omitted firing_trigger_id to get the default, got %v", sent)
+	}
+
+	if sent := withDefaultFiringTrigger(defaults, []types.String{}); len(sent) != 0 {
+		t.Fatalf("expected an explicit empty firing_trigger_id to override the default, got %v", sent)
+	}
+
+	if sent := withDefaultFiringTrigger(defaults, []types.String{types.StringValue("7")}); len(sent) != 1 || sent[0] != "7" {
+		t.Fatalf("expected a configured firing_trigger_id to be kept, got %v", sent)
+	}
+
+	if sent := withDefaultFiringTrigger(nil, nil); sent != nil {
+		t.Fatalf("expected no firing triggers without a default, got %v", sent)
+	}
+
+	if read := withoutDefaultFiringTrigger(defaults, defaults, nil); read != nil {
+		t.Fatalf("expected the default to be read back as null, got %v", read)
+	}
+
+	if read := withoutDefaultFiringTrigger(defaults, nil, []types.String{}); read == nil || len(read) != 0 {
+		t.Fatalf("expected an explicit empty list to stay empty, got %v", read)
+	}
+
+	if read := withoutDefaultFiringTrigger(defaults, []string{"7"}, nil); len(read) != 1 || read[0].ValueString() != "7" {
+		t.Fatalf("expected triggers changed outside Terraform to show up, got %v", read)
+	}
+}
+
+// Test that a sensitive_value is sent to GTM as the parameter value, but is redacted
+// from the request logs and from the error GTM returns quoting it
+func TestTagResource_sensitiveValue(t *testing.T) {
+	const secret = "sk-live-0123456789"
+	var sent tagmanager.Tag
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Errorf("unexpected body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"code": 400, "message": "Invalid API key ` + secret + `"}}`))
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := testClientInWorkspace()
+	client.Client.Service = srv
+
+	var logs bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &logs)
+	r := &tagResource{client: client}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	null := tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)
+
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: null}
+	diags := plan.Set(ctx, resourceTagModel{
+		Name: types.StringValue("Conversion"),
+		Type: types.StringValue("html"),
+		Parameter: []ResourceParameterModel{
+			testParameter("html", "<script>track()</script>"),
+			{Key: types.StringValue("apiKey"), Type: types.StringValue("template"), SensitiveValue: types.StringValue(secret)},
+		},
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	resp := fwresource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: null}}
+	r.Create(ctx, fwresource.CreateRequest{Plan: plan}, &resp)
+
+	if len(sent.Parameter) != 2 || sent.Parameter[1].Value != secret {
+		t.Fatalf("expected the sensitive value to be sent to GTM, got %+v", sent.Parameter)
+	}
+
+	if !resp.Diagnostics.HasError() || strings.Contains(resp.Diagnostics[0].Detail(), secret) {
+		t.Fatalf("expected the error to be redacted, got %v", resp.Diagnostics)
+	}
+
+	if !strings.Contains(logs.String(), "GTM API request") || strings.Contains(logs.String(), secret) {
+		t.Fatalf("expected the request log to be redacted, got %s", logs.String())
+	}
+}
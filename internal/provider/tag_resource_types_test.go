@@ -1,9 +1,21 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"google.golang.org/api/option"
+	"google.golang.org/api/tagmanager/v2"
 )
 
 // TestAccTagResource_universalAnalytics tests Universal Analytics tag
@@ -27,6 +39,77 @@ func TestAccTagResource_universalAnalytics(t *testing.T) {
 	})
 }
 
+// Test that a tag of the deprecated ua type is imported and a notes-only change is
+// applied, with only a warning for its type
+func TestTagResource_deprecatedType(t *testing.T) {
+	var updated tagmanager.Tag
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tagmanager/v2/accounts/1/containers/2/workspaces/3/tags/4" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"tagId": "4", "name": "Pageview", "type": "ua", "notes": "Legacy",
+				"parameter": [{"key": "trackingId", "type": "template", "value": "UA-12345678-1"}],
+				"firingTriggerId": ["2147479553"]}`))
+		case http.MethodPut:
+			if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+				t.Errorf("unexpected body: %v", err)
+			}
+			updated.TagId = "4"
+			_ = json.NewEncoder(w).Encode(&updated)
+		}
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := testClientInWorkspace()
+	client.Client.Service = srv
+
+	ctx := context.Background()
+	r := &tagResource{client: client}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	imported := fwresource.ImportStateResponse{State: tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: "4"}, &imported)
+	if imported.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", imported.Diagnostics)
+	}
+
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: imported.State.Raw.Copy()}
+	if diags := plan.SetAttribute(ctx, path.Root("notes"), "Kept until the GA4 migration"); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	var validated fwresource.ValidateConfigResponse
+	r.ValidateConfig(ctx, fwresource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: plan.Raw}}, &validated)
+	if validated.Diagnostics.HasError() || validated.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("expected a single warning for the deprecated type, got %v", validated.Diagnostics)
+	}
+
+	resp := fwresource.UpdateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: plan.Raw.Copy()}}
+	r.Update(ctx, fwresource.UpdateRequest{Plan: plan, State: imported.State}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if updated.Type != "ua" || updated.Notes != "Kept until the GA4 migration" || len(updated.Parameter) != 1 {
+		t.Fatalf("expected the ua tag to be updated with the new notes only, got %+v", updated)
+	}
+}
+
 // TestAccTagResource_customHTML tests custom HTML tag
 func TestAccTagResource_customHTML(t *testing.T) {
 	testAccPreCheck(t)
@@ -108,6 +191,39 @@ func TestAccTagResource_twitterUniversalWebsiteTag(t *testing.T) {
 	})
 }
 
+// testJsTemplateLiteralsHtml is custom HTML mixing JS template literals, which GTM
+// must receive verbatim, with a GTM variable reference.
+const testJsTemplateLiteralsHtml = `<script>
+  (function() {
+    var pid = "{{LinkedIn Partner ID}}";
+    var src = ` + "`" + `https://px.ads.linkedin.com/collect/?pid=${pid}&fmt=gif&t=${Date.now()}` + "`" + `;
+    var msg = ` + "`" + `twq('event', '${"tw-" + pid}', {})` + "`" + `;
+    new Image().src = src;
+  })();
+</script>
+`
+
+// TestAccTagResource_jsTemplateLiterals tests that JS template literals in custom HTML
+// are passed through and read back byte-for-byte
+func TestAccTagResource_jsTemplateLiterals(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagResourceJsTemplateLiteralsConfig(),
+				Check:  resource.TestCheckResourceAttr("gtm_tag.template_literals", "parameter.0.value", testJsTemplateLiteralsHtml),
+			},
+			{
+				Config:   testAccTagResourceJsTemplateLiteralsConfig(),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 // TestAccTagResource_hotjarTracking tests Hotjar tracking tag
 func TestAccTagResource_hotjarTracking(t *testing.T) {
 	testAccPreCheck(t)
@@ -301,6 +417,25 @@ resource "gtm_tag" "twitter" {
 `
 }
 
+func testAccTagResourceJsTemplateLiteralsConfig() string {
+	// HCL interpolates ${...} in strings, so JS template literals are escaped as $${...}.
+	return testAccProviderConfig() + `
+resource "gtm_tag" "template_literals" {
+  name = "tf-test-js-template-literals"
+  type = "html"
+
+  parameter = [
+    {
+      key   = "html"
+      type  = "template"
+      value = <<EOT
+` + strings.ReplaceAll(testJsTemplateLiteralsHtml, "${", "$${") + `EOT
+    }
+  ]
+}
+`
+}
+
 func testAccTagResourceHotjarConfig() string {
 	return testAccProviderConfig() + `
 resource "gtm_tag" "hotjar" {
@@ -354,3 +489,135 @@ resource "gtm_tag" "optimize" {
 }
 `
 }
+
+// TestAccTagResource_ga4UserProperties tests GA4 user properties set with user_property
+func TestAccTagResource_ga4UserProperties(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagResourceGA4UserPropertiesConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_tag.ga4_user_properties", "user_property.#", "2"),
+					resource.TestCheckResourceAttr("gtm_tag.ga4_user_properties", "user_property.0.name", "plan"),
+					resource.TestCheckResourceAttr("gtm_tag.ga4_user_properties", "parameter.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "gtm_tag.ga4_user_properties",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccTagResourceGA4UserPropertiesConfig() string {
+	return testAccProviderConfig() + `
+resource "gtm_tag" "ga4_user_properties" {
+  name = "tf-test-ga4-user-properties"
+  type = "gaawe"
+
+  parameter = [
+    {
+      key   = "eventName"
+      type  = "template"
+      value = "sign_up"
+    },
+    {
+      key   = "measurementIdOverride"
+      type  = "template"
+      value = "G-XXXXXX"
+    }
+  ]
+
+  user_property = [
+    {
+      name  = "plan"
+      value = "premium"
+    },
+    {
+      name  = "tier"
+      value = "gold"
+    }
+  ]
+}
+`
+}
+
+// TestAccTagResource_triggerReference tests a triggerReference parameter given as a
+// trigger id, then as a trigger name resolved to the same id
+func TestAccTagResource_triggerReference(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTagResourceTriggerReferenceConfig("gtm_trigger.referenced.id"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("gtm_tag.trigger_reference", "parameter.1.value", "gtm_trigger.referenced", "id"),
+				),
+			},
+			{
+				Config: testAccTagResourceTriggerReferenceConfig("gtm_trigger.referenced.name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_tag.trigger_reference", "parameter.1.value", "tf-test-referenced-trigger"),
+				),
+			},
+			{
+				Config:      testAccTagResourceTriggerReferenceConfig(`"tf-test-missing-trigger"`),
+				ExpectError: regexp.MustCompile(`does not match the id or name of any trigger`),
+			},
+		},
+	})
+}
+
+func testAccTagResourceTriggerReferenceConfig(value string) string {
+	return testAccProviderConfig() + `
+resource "gtm_trigger" "referenced" {
+  name = "tf-test-referenced-trigger"
+  type = "customEvent"
+
+  custom_event_filter = [
+    {
+      type = "equals"
+      parameter = [
+        {
+          type  = "template"
+          key   = "arg0"
+          value = "{{_event}}"
+        },
+        {
+          type  = "template"
+          key   = "arg1"
+          value = "referenced-event"
+        }
+      ]
+    }
+  ]
+}
+
+resource "gtm_tag" "trigger_reference" {
+  name = "tf-test-trigger-reference"
+  type = "html"
+
+  parameter = [
+    {
+      key   = "html"
+      type  = "template"
+      value = "<script></script>"
+    },
+    {
+      key   = "trigger"
+      type  = "triggerReference"
+      value = ` + value + `
+    }
+  ]
+}
+`
+}
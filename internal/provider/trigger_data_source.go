@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ datasource.DataSource              = &triggerDataSource{}
+	_ datasource.DataSourceWithConfigure = &triggerDataSource{}
+)
+
+type triggerDataSource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewTriggerDataSource() datasource.DataSource {
+	return &triggerDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *triggerDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(d.client, &resp.Diagnostics)
+}
+
+// Metadata returns the data source type name.
+func (d *triggerDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_trigger"
+}
+
+// Schema defines the schema for the data source.
+func (d *triggerDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up a trigger of the workspace by name. Reading fails if the trigger does not exist.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the trigger.",
+				Required:    true,
+			},
+			"id": schema.StringAttribute{
+				Description: "The ID of the trigger.",
+				Computed:    true,
+			},
+			"type": schema.StringAttribute{
+				Description: "The type of the trigger.",
+				Computed:    true,
+			},
+			"json": schema.StringAttribute{
+				Description: "The trigger serialized in the format of a GTM container export.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type dataSourceTriggerModel struct {
+	Name types.String `tfsdk:"name"`
+	Id   types.String `tfsdk:"id"`
+	Type types.String `tfsdk:"type"`
+	Json types.String `tfsdk:"json"`
+}
+
+// findTriggerByName returns the trigger stored under name, with or without the
+// configured name prefix, or nil when there is none.
+func findTriggerByName(triggers []*tagmanager.Trigger, prefix string, name string) *tagmanager.Trigger {
+	for _, trigger := range triggers {
+		if trigger.Name == name || trigger.Name == withNamePrefix(prefix, name) {
+			return trigger
+		}
+	}
+
+	return nil
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *triggerDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config dataSourceTriggerModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	triggers, err := d.client.ListTriggers()
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Trigger", err.Error())
+		return
+	}
+
+	trigger := findTriggerByName(triggers, d.client.Options.NamePrefix, config.Name.ValueString())
+	if trigger == nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Trigger Not Found",
+			"No trigger named "+config.Name.ValueString()+" exists in the workspace.")
+		return
+	}
+
+	json, err := exportJson(trigger)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Trigger", err.Error())
+		return
+	}
+
+	var state = dataSourceTriggerModel{
+		Name: config.Name,
+		Id:   types.StringValue(trigger.TriggerId),
+		Type: types.StringValue(trigger.Type),
+		Json: types.StringValue(json),
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
@@ -2,19 +2,27 @@ package provider
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"terraform-provider-google-tag-manager/internal/api"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/tagmanager/v2"
 )
 
 var (
-	_ resource.Resource                = &triggerResource{}
-	_ resource.ResourceWithConfigure   = &triggerResource{}
-	_ resource.ResourceWithImportState = &triggerResource{}
+	_ resource.Resource                   = &triggerResource{}
+	_ resource.ResourceWithConfigure      = &triggerResource{}
+	_ resource.ResourceWithImportState    = &triggerResource{}
+	_ resource.ResourceWithValidateConfig = &triggerResource{}
 )
 
 type triggerResource struct {
@@ -26,12 +34,13 @@ func NewTriggerResource() resource.Resource {
 }
 
 // Configure adds the provider configured client to the resource.
-func (r *triggerResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+func (r *triggerResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
 	r.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(r.client, &resp.Diagnostics)
 }
 
 // Metadata returns the resource type name.
@@ -39,7 +48,7 @@ func (r *triggerResource) Metadata(_ context.Context, req resource.MetadataReque
 	resp.TypeName = req.ProviderTypeName + "_trigger"
 }
 
-var triggerResourceSchemaAttributes = map[string]schema.Attribute{
+var triggerResourceSchemaAttributes = withWorkspaceEntityAttributes(map[string]schema.Attribute{
 	"name": schema.StringAttribute{
 		Description: "The name of the trigger.",
 		Required:    true,
@@ -55,9 +64,39 @@ var triggerResourceSchemaAttributes = map[string]schema.Attribute{
 	"notes": schema.StringAttribute{
 		Description: "The notes of the trigger.",
 		Optional:    true,
+		Validators:  notesValidators,
 	},
 	"custom_event_filter": conditionSchema,
-}
+	"filter":              conditionSchema,
+	"retry_limit":         retryLimitAttribute,
+	"interval": schema.StringAttribute{
+		Description: "Time between the events of a timer trigger in milliseconds, or a variable reference. Only valid for timer triggers.",
+		Optional:    true,
+	},
+	"limit": schema.StringAttribute{
+		Description: "Maximum number of events fired by a timer trigger, or a variable reference. The events continue until the user leaves the page when omitted. Only valid for timer triggers.",
+		Optional:    true,
+	},
+	"wait_for_tags": schema.BoolAttribute{
+		Description: "Whether a form submission or link click trigger delays the form submission or link navigation until the tags it fires have fired.",
+		Optional:    true,
+	},
+	"wait_for_tags_timeout": schema.StringAttribute{
+		Description: "Longest delay in milliseconds, or a variable reference, before the form submission or link navigation continues. Requires wait_for_tags to be true.",
+		Optional:    true,
+	},
+	"check_validation": schema.BoolAttribute{
+		Description: "Whether a form submission or link click trigger only fires when the form submission or link navigation has not been cancelled, e.g. by form validation.",
+		Optional:    true,
+	},
+	"unique_trigger_id": schema.StringAttribute{
+		Description: "Globally unique ID GTM assigns to form submit, link click and timer triggers to identify the events they generate.",
+		Computed:    true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	},
+})
 
 // Schema defines the schema for the resource.
 func (r *triggerResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -65,11 +104,78 @@ func (r *triggerResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 }
 
 type resourceTriggerModel struct {
-	Name              types.String             `tfsdk:"name"`
-	Type              types.String             `tfsdk:"type"`
-	Id                types.String             `tfsdk:"id"`
-	Notes             types.String             `tfsdk:"notes"`
-	CustomEventFilter []ResourceConditionModel `tfsdk:"custom_event_filter"`
+	Name               types.String             `tfsdk:"name"`
+	Type               types.String             `tfsdk:"type"`
+	Id                 types.String             `tfsdk:"id"`
+	Notes              types.String             `tfsdk:"notes"`
+	CustomEventFilter  []ResourceConditionModel `tfsdk:"custom_event_filter"`
+	Filter             []ResourceConditionModel `tfsdk:"filter"`
+	Interval           types.String             `tfsdk:"interval"`
+	Limit              types.String             `tfsdk:"limit"`
+	WaitForTags        types.Bool               `tfsdk:"wait_for_tags"`
+	WaitForTagsTimeout types.String             `tfsdk:"wait_for_tags_timeout"`
+	CheckValidation    types.Bool               `tfsdk:"check_validation"`
+	UniqueTriggerId    types.String             `tfsdk:"unique_trigger_id"`
+	workspaceEntityModel
+	RetryLimit types.Int64 `tfsdk:"retry_limit"`
+}
+
+// customEventTriggerType is the type of the triggers that fire on a dataLayer event,
+// the only ones custom_event_filter applies to.
+const customEventTriggerType = "customEvent"
+
+// ValidateConfig checks that custom_event_filter is only used on custom event triggers
+// and filter only on the other types, since GTM silently ignores the one that does
+// not apply, and that wait_for_tags_timeout is only set when waiting for tags.
+func (r *triggerResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var triggerType, waitForTagsTimeout types.String
+	var customEventFilter, filter types.List
+	var waitForTags types.Bool
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &triggerType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("custom_event_filter"), &customEventFilter)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("filter"), &filter)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("wait_for_tags"), &waitForTags)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("wait_for_tags_timeout"), &waitForTagsTimeout)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	checkTriggerFilters(triggerType, customEventFilter, filter, &resp.Diagnostics)
+	checkWaitForTagsTimeout(waitForTags, waitForTagsTimeout, &resp.Diagnostics)
+}
+
+// checkWaitForTagsTimeout reports a wait_for_tags_timeout set without wait_for_tags,
+// which GTM ignores.
+func checkWaitForTagsTimeout(waitForTags types.Bool, waitForTagsTimeout types.String, diags *diag.Diagnostics) {
+	if waitForTagsTimeout.IsNull() || waitForTags.IsUnknown() || waitForTags.ValueBool() {
+		return
+	}
+
+	diags.AddAttributeError(path.Root("wait_for_tags_timeout"), "Unsupported Wait For Tags Timeout",
+		"wait_for_tags_timeout only applies when wait_for_tags is true.")
+}
+
+// checkTriggerFilters reports the filter attribute that does not apply to the trigger type.
+func checkTriggerFilters(triggerType types.String, customEventFilter types.List, filter types.List, diags *diag.Diagnostics) {
+	if triggerType.IsNull() || triggerType.IsUnknown() {
+		return
+	}
+
+	customEvent := strings.EqualFold(triggerType.ValueString(), customEventTriggerType)
+
+	if !customEvent && !customEventFilter.IsNull() {
+		diags.AddAttributeError(path.Root("custom_event_filter"), "Unsupported Custom Event Filter",
+			"custom_event_filter only applies to triggers of type customEvent, got "+triggerType.ValueString()+". "+
+				"Use filter to add conditions to this trigger.")
+	}
+
+	if customEvent && !filter.IsNull() {
+		diags.AddAttributeError(path.Root("filter"), "Unsupported Filter",
+			"filter does not apply to triggers of type customEvent. "+
+				"Use custom_event_filter to match the event name and filter conditions on the event.")
+	}
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -83,13 +189,27 @@ func (r *triggerResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	trigger, err := r.client.CreateTrigger(toApiTrigger(plan))
+	client := withRequestLog(ctx, withRetryLimit(r.client, plan.RetryLimit), "trigger")
+
+	dto := toApiTrigger(plan)
+	dto.Name = withNamePrefix(client.Options.NamePrefix, dto.Name)
+	dto.Notes = withManagedByNote(managedByNote(client.Options, "trigger", time.Now()), dto.Notes)
+
+	trigger, err := client.CreateTrigger(dto)
 	if err != nil {
 		resp.Diagnostics.AddError("Error Creating Trigger", err.Error())
 		return
 	}
 
+	diags = recordCreatedId(ctx, &resp.State, trigger.TriggerId)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	plan.Id = types.StringValue(trigger.TriggerId)
+	plan.UniqueTriggerId = toResourceTemplateParameter(trigger.UniqueTriggerId)
+	plan.workspaceEntityModel = workspaceEntityLocation(client, "triggers", trigger.TriggerId)
 
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -106,16 +226,25 @@ func (r *triggerResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	trigger, err := r.client.Trigger(state.Id.ValueString())
+	client := withRequestLog(ctx, withRetryLimit(r.client, state.RetryLimit), "trigger")
+
+	trigger, err := client.Trigger(state.Id.ValueString(), triggerReadFields)
 	if err == api.ErrNotExist {
 		resp.State.RemoveResource(ctx)
+		warnWorkspaceEmptied(client, "Trigger", state.Id.ValueString(), &resp.Diagnostics)
 		return
 	} else if err != nil {
 		resp.Diagnostics.AddError("Error Reading Trigger", err.Error())
 		return
 	}
 
-	var resource = toResourceTrigger(trigger)
+	var resource = toResourceTrigger(trigger, client)
+	resource.Name = withoutNamePrefix(client.Options.NamePrefix, trigger.Name, state.Name)
+	resource.Notes = withoutManagedByNote(remoteManagedByNote(client.Options, "trigger", trigger.Notes), trigger.Notes, state.Notes)
+	resource.Type = withoutTypeCasing(trigger.Type, state.Type)
+	resource.WaitForTags = nullableBoolValue(booleanParameterValue(trigger.WaitForTags), state.WaitForTags)
+	resource.CheckValidation = nullableBoolValue(booleanParameterValue(trigger.CheckValidation), state.CheckValidation)
+	resource.RetryLimit = state.RetryLimit
 
 	diags = resp.State.Set(ctx, &resource)
 	resp.Diagnostics.Append(diags...)
@@ -135,13 +264,25 @@ func (r *triggerResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
-	trigger, err := r.client.UpdateTrigger(state.Id.ValueString(), toApiTrigger(plan))
+	client := withRequestLog(ctx, withRetryLimit(r.client, plan.RetryLimit), "trigger")
+
+	dto := toApiTrigger(plan)
+	dto.Name = withNamePrefix(client.Options.NamePrefix, dto.Name)
+	dto.Notes = withManagedByNote(managedByNote(client.Options, "trigger", time.Now()), dto.Notes)
+	// GTM assigns the unique trigger id; sending the current one back keeps it stable.
+	dto.UniqueTriggerId = toApiTemplateParameter(state.UniqueTriggerId)
+
+	trigger, err := client.UpdateTrigger(state.Id.ValueString(), dto)
 	if err != nil {
 		resp.Diagnostics.AddError("Error Updating Trigger", err.Error())
 		return
 	}
 
 	plan.Id = types.StringValue(trigger.TriggerId)
+	if plan.UniqueTriggerId.IsUnknown() {
+		plan.UniqueTriggerId = toResourceTemplateParameter(trigger.UniqueTriggerId)
+	}
+	plan.workspaceEntityModel = workspaceEntityLocation(client, "triggers", trigger.TriggerId)
 
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -158,7 +299,9 @@ func (r *triggerResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	err := r.client.DeleteTrigger(state.Id.ValueString())
+	client := withRequestLog(ctx, withRetryLimit(r.client, state.RetryLimit), "trigger")
+
+	err := client.DeleteTrigger(state.Id.ValueString())
 	if err == api.ErrNotExist {
 		return
 	} else if err != nil {
@@ -167,19 +310,34 @@ func (r *triggerResource) Delete(ctx context.Context, req resource.DeleteRequest
 	}
 }
 
+// ImportState imports the trigger by its id or by its full GTM path.
 func (r *triggerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	id, err := workspaceImportId(r.client, "triggers", req.ID)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("id"), "Error Importing Trigger", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
 
 // Equal compares the trigger resource model with the given resource model
 func (m resourceTriggerModel) Equal(o resourceTriggerModel) bool {
 	if !m.Name.Equal(o.Name) ||
-		!m.Type.Equal(o.Type) ||
+		!strings.EqualFold(m.Type.ValueString(), o.Type.ValueString()) ||
 		(!m.Id.IsUnknown() && !m.Id.Equal(o.Id)) ||
 		!m.Notes.Equal(o.Notes) {
 		return false
 	}
 
+	if !m.Interval.Equal(o.Interval) || !m.Limit.Equal(o.Limit) {
+		return false
+	}
+
+	if !m.WaitForTags.Equal(o.WaitForTags) || !m.WaitForTagsTimeout.Equal(o.WaitForTagsTimeout) || !m.CheckValidation.Equal(o.CheckValidation) {
+		return false
+	}
+
 	if len(m.CustomEventFilter) != len(o.CustomEventFilter) {
 		return false
 	}
@@ -190,25 +348,99 @@ func (m resourceTriggerModel) Equal(o resourceTriggerModel) bool {
 		}
 	}
 
+	if len(m.Filter) != len(o.Filter) {
+		return false
+	}
+
+	for i := range m.Filter {
+		if !m.Filter[i].Equal(o.Filter[i]) {
+			return false
+		}
+	}
+
 	return true
 }
 
-func toResourceTrigger(trigger *tagmanager.Trigger) resourceTriggerModel {
+// withoutTypeCasing returns the type to keep in state for a trigger type stored in GTM.
+// GTM normalizes the casing of trigger types, e.g. customevent to customEvent, so a
+// configured type that only differs in casing is kept to avoid a diff.
+func withoutTypeCasing(remote string, current types.String) types.String {
+	if !current.IsNull() && !current.IsUnknown() && strings.EqualFold(current.ValueString(), remote) {
+		return current
+	}
+
+	return types.StringValue(remote)
+}
+
+// triggerReadFields are the fields of a trigger that toResourceTrigger maps, the only
+// ones requested when the trigger is read.
+const triggerReadFields googleapi.Field = "triggerId,name,type,notes,customEventFilter,filter,interval,limit,waitForTags,waitForTagsTimeout,checkValidation,uniqueTriggerId"
+
+func toResourceTrigger(trigger *tagmanager.Trigger, client *api.ClientInWorkspace) resourceTriggerModel {
 	return resourceTriggerModel{
-		Name:              types.StringValue(trigger.Name),
-		Type:              types.StringValue(trigger.Type),
-		Id:                types.StringValue(trigger.TriggerId),
-		Notes:             nullableStringValue(trigger.Notes),
-		CustomEventFilter: toResourceCondition(trigger.CustomEventFilter),
+		Name:                 types.StringValue(trigger.Name),
+		Type:                 types.StringValue(trigger.Type),
+		Id:                   types.StringValue(trigger.TriggerId),
+		Notes:                nullableStringValue(trigger.Notes),
+		CustomEventFilter:    toResourceCondition(trigger.CustomEventFilter),
+		Filter:               toResourceCondition(trigger.Filter),
+		Interval:             toResourceTemplateParameter(trigger.Interval),
+		Limit:                toResourceTemplateParameter(trigger.Limit),
+		WaitForTags:          nullableBoolValue(booleanParameterValue(trigger.WaitForTags), types.BoolNull()),
+		WaitForTagsTimeout:   toResourceTemplateParameter(trigger.WaitForTagsTimeout),
+		CheckValidation:      nullableBoolValue(booleanParameterValue(trigger.CheckValidation), types.BoolNull()),
+		UniqueTriggerId:      toResourceTemplateParameter(trigger.UniqueTriggerId),
+		workspaceEntityModel: workspaceEntityLocation(client, "triggers", trigger.TriggerId),
 	}
 }
 
 func toApiTrigger(resource resourceTriggerModel) *tagmanager.Trigger {
 	return &tagmanager.Trigger{
-		Name:              resource.Name.ValueString(),
-		Type:              resource.Type.ValueString(),
-		TriggerId:         resource.Id.ValueString(),
-		Notes:             resource.Notes.ValueString(),
-		CustomEventFilter: toApiCondition(resource.CustomEventFilter),
+		Name:               resource.Name.ValueString(),
+		Type:               resource.Type.ValueString(),
+		TriggerId:          resource.Id.ValueString(),
+		Notes:              resource.Notes.ValueString(),
+		CustomEventFilter:  toApiCondition(resource.CustomEventFilter),
+		Filter:             toApiCondition(resource.Filter),
+		Interval:           toApiTemplateParameter(resource.Interval),
+		Limit:              toApiTemplateParameter(resource.Limit),
+		WaitForTags:        toApiBooleanParameter(resource.WaitForTags),
+		WaitForTagsTimeout: toApiTemplateParameter(resource.WaitForTagsTimeout),
+		CheckValidation:    toApiBooleanParameter(resource.CheckValidation),
 	}
 }
+
+// toApiTemplateParameter returns the template parameter GTM uses for single valued
+// trigger fields such as interval, or nil when the value is not set.
+func toApiTemplateParameter(value types.String) *tagmanager.Parameter {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+
+	return &tagmanager.Parameter{Type: "template", Value: value.ValueString()}
+}
+
+// toResourceTemplateParameter returns the value of a single valued trigger field, null
+// when GTM returns none.
+func toResourceTemplateParameter(parameter *tagmanager.Parameter) types.String {
+	if parameter == nil {
+		return types.StringNull()
+	}
+
+	return nullableStringValue(parameter.Value)
+}
+
+// toApiBooleanParameter returns the boolean parameter GTM uses for trigger flags such
+// as waitForTags, or nil when the flag is not set.
+func toApiBooleanParameter(value types.Bool) *tagmanager.Parameter {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+
+	return &tagmanager.Parameter{Type: "boolean", Value: strconv.FormatBool(value.ValueBool())}
+}
+
+// booleanParameterValue reports whether a trigger flag is set to true.
+func booleanParameterValue(parameter *tagmanager.Parameter) bool {
+	return parameter != nil && parameter.Value == "true"
+}
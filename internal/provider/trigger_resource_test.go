@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// testConditionList returns a configured list of conditions, whose elements are not
+// inspected by checkTriggerFilters.
+func testConditionList() types.List {
+	return types.ListValueMust(types.StringType, []attr.Value{types.StringValue("condition")})
+}
+
+// Test that custom_event_filter on a trigger that is not a custom event trigger is rejected
+func TestCheckTriggerFilters_customEventFilterOnPageview(t *testing.T) {
+	var diags diag.Diagnostics
+	checkTriggerFilters(types.StringValue("pageview"), testConditionList(), types.ListNull(types.StringType), &diags)
+
+	if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != "Unsupported Custom Event Filter" {
+		t.Fatalf("expected an unsupported custom event filter error, got %v", diags)
+	}
+}
+
+// Test that filter on a custom event trigger is rejected, whatever the casing of the type
+func TestCheckTriggerFilters_filterOnCustomEvent(t *testing.T) {
+	for _, triggerType := range []string{"customEvent", "customevent"} {
+		var diags diag.Diagnostics
+		checkTriggerFilters(types.StringValue(triggerType), types.ListNull(types.StringType), testConditionList(), &diags)
+
+		if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != "Unsupported Filter" {
+			t.Fatalf("expected an unsupported filter error for %s, got %v", triggerType, diags)
+		}
+	}
+}
+
+// Test that each filter is accepted on the triggers it applies to
+func TestCheckTriggerFilters_matching(t *testing.T) {
+	var diags diag.Diagnostics
+	checkTriggerFilters(types.StringValue("customEvent"), testConditionList(), types.ListNull(types.StringType), &diags)
+	checkTriggerFilters(types.StringValue("pageview"), types.ListNull(types.StringType), testConditionList(), &diags)
+	checkTriggerFilters(types.StringUnknown(), testConditionList(), testConditionList(), &diags)
+
+	if diags.HasError() {
+		t.Fatalf("expected no error, got %v", diags)
+	}
+}
+
+// Test that the filter of a trigger is sent to GTM and an absent one is read back as null
+func TestTriggerFilter_roundTrip(t *testing.T) {
+	filter := []ResourceConditionModel{
+		{
+			Type:      types.StringValue("equals"),
+			Parameter: []ResourceParameterModel{testParameter("arg0", "{{Page URL}}"), testParameter("arg1", "https://example.com/")},
+		},
+	}
+
+	trigger := toApiTrigger(resourceTriggerModel{Name: types.StringValue("pageview"), Type: types.StringValue("pageview"), Filter: filter})
+	if len(trigger.Filter) != 1 || len(trigger.CustomEventFilter) != 0 {
+		t.Fatalf("expected the filter to be sent as filter, got %v and %v", trigger.Filter, trigger.CustomEventFilter)
+	}
+
+	resource := toResourceTrigger(trigger, testClientInWorkspace())
+	if len(resource.Filter) != 1 || !resource.Filter[0].Equal(filter[0]) {
+		t.Fatalf("expected the filter to round-trip, got %v", resource.Filter)
+	}
+
+	if resource.CustomEventFilter != nil {
+		t.Fatalf("expected an absent custom_event_filter to be null, got %v", resource.CustomEventFilter)
+	}
+}
+
+// Test that the timer fields are sent as template parameters and the unique trigger id GTM assigns is read back
+func TestTriggerTimer_roundTrip(t *testing.T) {
+	trigger := toApiTrigger(resourceTriggerModel{
+		Name:     types.StringValue("timer"),
+		Type:     types.StringValue("timer"),
+		Interval: types.StringValue("5000"),
+		Limit:    types.StringNull(),
+	})
+	if trigger.Interval == nil || trigger.Interval.Type != "template" || trigger.Interval.Value != "5000" {
+		t.Fatalf("expected interval to be sent as a template parameter, got %v", trigger.Interval)
+	}
+	if trigger.Limit != nil || trigger.UniqueTriggerId != nil {
+		t.Fatalf("expected unset limit and unique trigger id to be omitted, got %v and %v", trigger.Limit, trigger.UniqueTriggerId)
+	}
+
+	trigger.UniqueTriggerId = &tagmanager.Parameter{Type: "template", Value: "123"}
+	resource := toResourceTrigger(trigger, testClientInWorkspace())
+	if resource.Interval.ValueString() != "5000" || !resource.Limit.IsNull() || resource.UniqueTriggerId.ValueString() != "123" {
+		t.Fatalf("unexpected timer fields: %v, %v, %v", resource.Interval, resource.Limit, resource.UniqueTriggerId)
+	}
+
+	if empty := toResourceTemplateParameter(&tagmanager.Parameter{Type: "template"}); !empty.IsNull() {
+		t.Fatalf("expected a parameter without value to be null, got %v", empty)
+	}
+}
+
+// Test that a form trigger sends wait_for_tags, wait_for_tags_timeout and check_validation
+// as parameters and reads them back
+func TestTriggerFormSubmission_roundTrip(t *testing.T) {
+	planned := resourceTriggerModel{
+		Name:               types.StringValue("form"),
+		Type:               types.StringValue("formSubmission"),
+		Id:                 types.StringValue("7"),
+		WaitForTags:        types.BoolValue(true),
+		WaitForTagsTimeout: types.StringValue("2000"),
+		CheckValidation:    types.BoolValue(true),
+	}
+
+	trigger := toApiTrigger(planned)
+	if trigger.WaitForTags == nil || trigger.WaitForTags.Type != "boolean" || trigger.WaitForTags.Value != "true" {
+		t.Fatalf("expected wait_for_tags to be sent as a boolean parameter, got %v", trigger.WaitForTags)
+	}
+	if trigger.WaitForTagsTimeout == nil || trigger.WaitForTagsTimeout.Type != "template" || trigger.WaitForTagsTimeout.Value != "2000" {
+		t.Fatalf("expected wait_for_tags_timeout to be sent as a template parameter, got %v", trigger.WaitForTagsTimeout)
+	}
+	if trigger.CheckValidation == nil || trigger.CheckValidation.Value != "true" {
+		t.Fatalf("expected check_validation to be sent as a boolean parameter, got %v", trigger.CheckValidation)
+	}
+
+	if read := toResourceTrigger(trigger, testClientInWorkspace()); !read.Equal(planned) {
+		t.Fatalf("expected %+v, got %+v", planned, read)
+	}
+
+	// A flag GTM returns as false is read back as null unless it is configured
+	trigger.CheckValidation.Value = "false"
+	if read := toResourceTrigger(trigger, testClientInWorkspace()); !read.CheckValidation.IsNull() {
+		t.Fatalf("expected an unconfigured false flag to be null, got %v", read.CheckValidation)
+	}
+}
+
+// Test that wait_for_tags_timeout is rejected unless wait_for_tags is true
+func TestCheckWaitForTagsTimeout(t *testing.T) {
+	for _, waitForTags := range []types.Bool{types.BoolNull(), types.BoolValue(false)} {
+		var diags diag.Diagnostics
+		checkWaitForTagsTimeout(waitForTags, types.StringValue("2000"), &diags)
+
+		if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != "Unsupported Wait For Tags Timeout" {
+			t.Fatalf("expected an unsupported timeout error with wait_for_tags %s, got %v", waitForTags, diags)
+		}
+	}
+
+	var diags diag.Diagnostics
+	checkWaitForTagsTimeout(types.BoolValue(true), types.StringValue("2000"), &diags)
+	checkWaitForTagsTimeout(types.BoolUnknown(), types.StringValue("2000"), &diags)
+	checkWaitForTagsTimeout(types.BoolNull(), types.StringNull(), &diags)
+
+	if diags.HasError() {
+		t.Fatalf("expected no error, got %v", diags)
+	}
+}
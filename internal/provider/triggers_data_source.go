@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ datasource.DataSource              = &triggersDataSource{}
+	_ datasource.DataSourceWithConfigure = &triggersDataSource{}
+)
+
+type triggersDataSource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewTriggersDataSource() datasource.DataSource {
+	return &triggersDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *triggersDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(d.client, &resp.Diagnostics)
+}
+
+// Metadata returns the data source type name.
+func (d *triggersDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_triggers"
+}
+
+// Schema defines the schema for the data source.
+func (d *triggersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the triggers of the workspace.",
+		Attributes: map[string]schema.Attribute{
+			"triggers": entityListSchema("trigger"),
+		},
+	}
+}
+
+type dataSourceTriggersModel struct {
+	Triggers []dataSourceEntityModel `tfsdk:"triggers"`
+}
+
+func toDataSourceTriggers(triggers []*tagmanager.Trigger) dataSourceTriggersModel {
+	var entities = make([]dataSourceEntityModel, 0, len(triggers))
+
+	for _, trigger := range triggers {
+		entities = append(entities, dataSourceEntityModel{
+			Id:   types.StringValue(trigger.TriggerId),
+			Name: types.StringValue(trigger.Name),
+			Type: types.StringValue(trigger.Type),
+		})
+	}
+
+	return dataSourceTriggersModel{Triggers: sortEntities(entities)}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *triggersDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	triggers, err := d.client.ListTriggers()
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Triggers", err.Error())
+		return
+	}
+
+	var state = toDataSourceTriggers(triggers)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// userPropertiesKey is the parameter of GA4 event tags holding the user properties.
+const userPropertiesKey = "userProperties"
+
+var userPropertySchema = schema.ListNestedAttribute{
+	Description: "GA4 user properties set by the tag. Only supported on gaawe tags, where it is compiled to the userProperties parameter.",
+	Optional:    true,
+	Validators:  []validator.List{listvalidator.SizeAtLeast(1)},
+	NestedObject: schema.NestedAttributeObject{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the user property.",
+				Required:    true},
+			"value": schema.StringAttribute{
+				Description: "The value of the user property.",
+				Required:    true},
+		},
+	},
+}
+
+type ResourceUserPropertyModel struct {
+	Name  types.String `tfsdk:"name"`
+	Value types.String `tfsdk:"value"`
+}
+
+// compileUserProperties returns the userProperties parameter, a list of name/value
+// maps, for the given user properties.
+func compileUserProperties(properties []ResourceUserPropertyModel) ResourceParameterModel {
+	var list = make([]ResourceParameterModel, 0, len(properties))
+
+	for _, property := range properties {
+		list = append(list, ResourceParameterModel{
+			Type: types.StringValue("map"),
+			Map: []ResourceParameterModel{
+				{Key: types.StringValue("name"), Type: types.StringValue("template"), Value: property.Name},
+				{Key: types.StringValue("value"), Type: types.StringValue("template"), Value: property.Value},
+			},
+		})
+	}
+
+	return ResourceParameterModel{
+		Key:  types.StringValue(userPropertiesKey),
+		Type: types.StringValue("list"),
+		List: list,
+	}
+}
+
+// decompileUserProperties moves the userProperties parameter out of parameter into
+// user properties. The parameters are returned unchanged when there is no such
+// parameter or it does not have the shape compileUserProperties produces.
+func decompileUserProperties(parameter []ResourceParameterModel) ([]ResourceParameterModel, []ResourceUserPropertyModel) {
+	for i, p := range parameter {
+		if p.Key.ValueString() != userPropertiesKey {
+			continue
+		}
+
+		properties, ok := toUserProperties(p)
+		if !ok {
+			return parameter, nil
+		}
+
+		var rest = append(append([]ResourceParameterModel{}, parameter[:i]...), parameter[i+1:]...)
+		if len(rest) == 0 {
+			rest = nil
+		}
+
+		return rest, properties
+	}
+
+	return parameter, nil
+}
+
+func toUserProperties(parameter ResourceParameterModel) ([]ResourceUserPropertyModel, bool) {
+	if parameter.Type.ValueString() != "list" || len(parameter.List) == 0 {
+		return nil, false
+	}
+
+	var properties = make([]ResourceUserPropertyModel, 0, len(parameter.List))
+
+	for _, entry := range parameter.List {
+		if entry.Type.ValueString() != "map" || len(entry.Map) != 2 {
+			return nil, false
+		}
+
+		var property ResourceUserPropertyModel
+		var hasName, hasValue bool
+		for _, field := range entry.Map {
+			switch field.Key.ValueString() {
+			case "name":
+				property.Name, hasName = types.StringValue(field.Value.ValueString()), true
+			case "value":
+				property.Value, hasValue = types.StringValue(field.Value.ValueString()), true
+			}
+		}
+
+		if !hasName || !hasValue {
+			return nil, false
+		}
+
+		properties = append(properties, property)
+	}
+
+	return properties, true
+}
+
+// hasParameter reports whether parameter contains a top-level parameter with key.
+func hasParameter(parameter []ResourceParameterModel, key string) bool {
+	for _, p := range parameter {
+		if p.Key.ValueString() == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Test that user_property compiles to the same userProperties parameter as the manual nested maps
+func TestUserProperty_compilesToManualParameter(t *testing.T) {
+	properties := []ResourceUserPropertyModel{
+		{Name: types.StringValue("plan"), Value: types.StringValue("{{Plan}}")},
+		{Name: types.StringValue("tier"), Value: types.StringValue("gold")},
+	}
+
+	manual := ResourceParameterModel{
+		Key:  types.StringValue("userProperties"),
+		Type: types.StringValue("list"),
+		List: []ResourceParameterModel{
+			{
+				Type: types.StringValue("map"),
+				Map:  []ResourceParameterModel{testParameter("name", "plan"), testParameter("value", "{{Plan}}")},
+			},
+			{
+				Type: types.StringValue("map"),
+				Map:  []ResourceParameterModel{testParameter("name", "tier"), testParameter("value", "gold")},
+			},
+		},
+	}
+
+	compiled := toApiParameter([]ResourceParameterModel{compileUserProperties(properties)})
+	expected := toApiParameter([]ResourceParameterModel{manual})
+
+	if !reflect.DeepEqual(compiled, expected) {
+		t.Fatalf("expected the compiled parameter to match the manual one, got %+v", compiled[0])
+	}
+}
+
+// Test that the userProperties parameter read from GTM is decompiled into user properties
+func TestUserProperty_decompile(t *testing.T) {
+	properties := []ResourceUserPropertyModel{
+		{Name: types.StringValue("plan"), Value: types.StringValue("{{Plan}}")},
+	}
+
+	remote := toResourceParameter(toApiParameter([]ResourceParameterModel{
+		testParameter("eventName", "purchase"),
+		compileUserProperties(properties),
+	}))
+
+	parameter, decompiled := decompileUserProperties(remote)
+
+	if len(parameter) != 1 || parameter[0].Key.ValueString() != "eventName" {
+		t.Fatalf("expected only eventName to remain, got %+v", parameter)
+	}
+
+	if !reflect.DeepEqual(decompiled, properties) {
+		t.Fatalf("expected %+v, got %+v", properties, decompiled)
+	}
+
+	// A userProperties parameter of another shape is left as a raw parameter.
+	unexpected := []ResourceParameterModel{{
+		Key:   types.StringValue("userProperties"),
+		Type:  types.StringValue("template"),
+		Value: types.StringValue("{{Properties}}"),
+	}}
+
+	if parameter, decompiled := decompileUserProperties(unexpected); len(parameter) != 1 || decompiled != nil {
+		t.Fatalf("expected the parameter to be kept, got %+v and %+v", parameter, decompiled)
+	}
+}
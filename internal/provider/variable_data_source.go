@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ datasource.DataSource                     = &variableDataSource{}
+	_ datasource.DataSourceWithConfigure        = &variableDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &variableDataSource{}
+)
+
+type variableDataSource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewVariableDataSource() datasource.DataSource {
+	return &variableDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *variableDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(d.client, &resp.Diagnostics)
+}
+
+// Metadata returns the data source type name.
+func (d *variableDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_variable"
+}
+
+// Schema defines the schema for the data source.
+func (d *variableDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up a variable of the workspace by name or ID. Reading fails if the variable does not exist.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The name of the variable. Exactly one of name and id must be set.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"id": schema.StringAttribute{
+				Description: "The ID of the variable. Exactly one of name and id must be set.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"type": schema.StringAttribute{
+				Description: "The type of the variable.",
+				Computed:    true,
+			},
+			"notes": schema.StringAttribute{
+				Description: "The notes of the variable.",
+				Computed:    true,
+			},
+			"parameter": dataSourceParameterSchema,
+			"reference": schema.StringAttribute{
+				Description: "The reference to the variable for use in parameter values, e.g. {{Page URL}}.",
+				Computed:    true,
+			},
+			"json": schema.StringAttribute{
+				Description: "The variable serialized in the format of a GTM container export.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// ConfigValidators requires the variable to be looked up by either name or id.
+func (d *variableDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(path.MatchRoot("name"), path.MatchRoot("id")),
+	}
+}
+
+type dataSourceVariableModel struct {
+	Name      types.String               `tfsdk:"name"`
+	Id        types.String               `tfsdk:"id"`
+	Type      types.String               `tfsdk:"type"`
+	Notes     types.String               `tfsdk:"notes"`
+	Parameter []DataSourceParameterModel `tfsdk:"parameter"`
+	Reference types.String               `tfsdk:"reference"`
+	Json      types.String               `tfsdk:"json"`
+}
+
+// findVariableByName returns the variable stored under name, or else under name with
+// the configured name prefix, or nil when there is none. GTM keeps variable names
+// unique, so only a variable named exactly and a prefixed one can both match, and the
+// exact match wins.
+func findVariableByName(variables []*tagmanager.Variable, prefix string, name string) *tagmanager.Variable {
+	var prefixed *tagmanager.Variable
+
+	for _, variable := range variables {
+		if variable.Name == name {
+			return variable
+		} else if prefixed == nil && variable.Name == withNamePrefix(prefix, name) {
+			prefixed = variable
+		}
+	}
+
+	return prefixed
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *variableDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config dataSourceVariableModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	variable := d.lookup(config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	json, err := exportJson(variable)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Variable", err.Error())
+		return
+	}
+
+	var state = dataSourceVariableModel{
+		Name:      config.Name,
+		Id:        types.StringValue(variable.VariableId),
+		Type:      types.StringValue(variable.Type),
+		Notes:     nullableStringValue(variable.Notes),
+		Parameter: toDataSourceParameter(variable.Parameter, parameterSchemaDepth),
+		Reference: types.StringValue("{{" + variable.Name + "}}"),
+		Json:      types.StringValue(json),
+	}
+	if state.Name.IsNull() {
+		state.Name = withoutNamePrefix(d.client.Options.NamePrefix, variable.Name, types.StringNull())
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// lookup reads the variable with the configured id, or finds the one with the
+// configured name among the variables of the workspace.
+func (d *variableDataSource) lookup(config dataSourceVariableModel, diags *diag.Diagnostics) *tagmanager.Variable {
+	if !config.Id.IsNull() {
+		variable, err := d.client.Variable(config.Id.ValueString())
+		if err == api.ErrNotExist {
+			diags.AddAttributeError(path.Root("id"), "Variable Not Found",
+				"No variable with ID "+config.Id.ValueString()+" exists in the workspace.")
+		} else if err != nil {
+			diags.AddError("Error Reading Variable", err.Error())
+		}
+
+		return variable
+	}
+
+	variables, err := d.client.ListVariables()
+	if err != nil {
+		diags.AddError("Error Reading Variable", err.Error())
+		return nil
+	}
+
+	variable := findVariableByName(variables, d.client.Options.NamePrefix, config.Name.ValueString())
+	if variable == nil {
+		diags.AddAttributeError(path.Root("name"), "Variable Not Found",
+			"No variable named "+config.Name.ValueString()+" exists in the workspace.")
+	}
+
+	return variable
+}
@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"google.golang.org/api/option"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that a variable is resolved by name to its id and reference
+func TestAccVariableDataSource_byName(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVariableDataSourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.gtm_variable.lookup", "id", "gtm_variable.lookup", "id"),
+					resource.TestCheckResourceAttr("data.gtm_variable.lookup", "type", "v"),
+					resource.TestCheckResourceAttr("data.gtm_variable.lookup", "reference", "{{tf-test-variable-lookup}}"),
+					resource.TestCheckResourceAttr("data.gtm_variable.by_id", "name", "tf-test-variable-lookup"),
+					resource.TestCheckResourceAttr("data.gtm_variable.by_id", "parameter.0.value", "lookup"),
+				),
+			},
+		},
+	})
+}
+
+// Test that a prefixed variable is found under its configured name, unless a variable
+// has exactly that name
+func TestFindVariableByName(t *testing.T) {
+	variables := []*tagmanager.Variable{
+		{VariableId: "1", Name: "Page URL"},
+		{VariableId: "2", Name: "tf-Order ID"},
+	}
+
+	if v := findVariableByName(variables, "", "Page URL"); v == nil || v.VariableId != "1" {
+		t.Fatalf("expected to find Page URL, got %v", v)
+	}
+
+	if v := findVariableByName(variables, "tf-", "Order ID"); v == nil || v.VariableId != "2" {
+		t.Fatalf("expected to find the prefixed Order ID, got %v", v)
+	}
+
+	// A variable named exactly is preferred over one with the prefix, whatever the order.
+	variables = append(variables, &tagmanager.Variable{VariableId: "3", Name: "Order ID"})
+	if v := findVariableByName(variables, "tf-", "Order ID"); v == nil || v.VariableId != "3" {
+		t.Fatalf("expected to find the exactly named Order ID, got %v", v)
+	}
+
+	if v := findVariableByName(variables, "", "Missing"); v != nil {
+		t.Fatalf("expected no variable, got %v", v)
+	}
+}
+
+// Test that a variable is looked up by id with its parameters, that a missing id is
+// reported as not found, and that exactly one of name and id has to be set
+func TestVariableDataSource_byId(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/tagmanager/v2/accounts/1/containers/2/workspaces/3/variables/7":
+			_, _ = w.Write([]byte(`{"variableId": "7", "name": "Order ID", "type": "v",
+				"parameter": [{"key": "name", "type": "template", "value": "ecommerce.transaction_id"}]}`))
+		case "/tagmanager/v2/accounts/1/containers/2/workspaces/3/variables/8":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": 404, "message": "Not found"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	srv, err := tagmanager.NewService(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := testClientInWorkspace()
+	client.Client.Service = srv
+
+	ctx := context.Background()
+	d := &variableDataSource{client: client}
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	config := func(attributes map[string]string) tfsdk.Config {
+		state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+		for name, value := range attributes {
+			if diags := state.SetAttribute(ctx, path.Root(name), value); diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+		}
+
+		return tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}
+	}
+
+	read := func(id string) datasource.ReadResponse {
+		c := config(map[string]string{"id": id})
+		resp := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: c.Raw.Copy()}}
+		d.Read(ctx, datasource.ReadRequest{Config: c}, &resp)
+		return resp
+	}
+
+	resp := read("7")
+	var state dataSourceVariableModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if state.Name.ValueString() != "Order ID" || state.Reference.ValueString() != "{{Order ID}}" ||
+		len(state.Parameter) != 1 || state.Parameter[0].Value.ValueString() != "ecommerce.transaction_id" {
+		t.Fatalf("unexpected variable: %+v", state)
+	}
+
+	if resp = read("8"); !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Variable Not Found" {
+		t.Fatalf("expected a not found error, got %v", resp.Diagnostics)
+	}
+
+	for _, attributes := range []map[string]string{{}, {"id": "7", "name": "Order ID"}} {
+		var validated datasource.ValidateConfigResponse
+		for _, v := range d.ConfigValidators(ctx) {
+			v.ValidateDataSource(ctx, datasource.ValidateConfigRequest{Config: config(attributes)}, &validated)
+		}
+
+		if !validated.Diagnostics.HasError() {
+			t.Fatalf("expected %v to be rejected", attributes)
+		}
+	}
+}
+
+func testAccVariableDataSourceConfig() string {
+	return testAccProviderConfig() + `
+resource "gtm_variable" "lookup" {
+  name = "tf-test-variable-lookup"
+  type = "v"
+
+  parameter = [{
+    key   = "name"
+    type  = "template"
+    value = "lookup"
+  }]
+}
+
+data "gtm_variable" "lookup" {
+  name = gtm_variable.lookup.name
+}
+
+data "gtm_variable" "by_id" {
+  id = gtm_variable.lookup.id
+}
+`
+}
@@ -2,19 +2,26 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"terraform-provider-google-tag-manager/internal/api"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/tagmanager/v2"
 )
 
 var (
-	_ resource.Resource                = &variableResource{}
-	_ resource.ResourceWithConfigure   = &variableResource{}
-	_ resource.ResourceWithImportState = &variableResource{}
+	_ resource.Resource                   = &variableResource{}
+	_ resource.ResourceWithConfigure      = &variableResource{}
+	_ resource.ResourceWithImportState    = &variableResource{}
+	_ resource.ResourceWithValidateConfig = &variableResource{}
 )
 
 type variableResource struct {
@@ -26,12 +33,13 @@ func NewVariableResource() resource.Resource {
 }
 
 // Configure adds the provider configured client to the resource.
-func (r *variableResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+func (r *variableResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
 	r.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(r.client, &resp.Diagnostics)
 }
 
 // Metadata returns the resource type name.
@@ -39,7 +47,7 @@ func (r *variableResource) Metadata(_ context.Context, req resource.MetadataRequ
 	resp.TypeName = req.ProviderTypeName + "_variable"
 }
 
-var variableResourceSchemaAttributes = map[string]schema.Attribute{
+var variableResourceSchemaAttributes = withWorkspaceEntityAttributes(map[string]schema.Attribute{
 	"name": schema.StringAttribute{
 		Description: "The name of the variable.",
 		Required:    true,
@@ -55,9 +63,28 @@ var variableResourceSchemaAttributes = map[string]schema.Attribute{
 	"notes": schema.StringAttribute{
 		Description: "The notes of the variable.",
 		Optional:    true,
+		Validators:  notesValidators,
 	},
-	"parameter": parameterSchema,
-}
+	"parameter":      parameterSchema,
+	"parameters_map": parametersMapSchema,
+	"input":          lookupTableInputSchema,
+	"row":            lookupTableRowSchema,
+	"format_value":   formatValueSchema,
+	"retry_limit":    retryLimitAttribute,
+	"schedule_start_ms": schema.Int64Attribute{
+		Description: "The start of the period in which the variable is active, in milliseconds since the epoch. Only supported by some variable types and containers.",
+		Optional:    true,
+		Validators:  []validator.Int64{int64validator.AtLeast(1)},
+	},
+	"schedule_end_ms": schema.Int64Attribute{
+		Description: "The end of the period in which the variable is active, in milliseconds since the epoch. Only supported by some variable types and containers.",
+		Optional:    true,
+		Validators: []validator.Int64{
+			int64validator.AtLeast(1),
+			int64validator.AtLeastSumOf(path.MatchRoot("schedule_start_ms")),
+		},
+	},
+})
 
 // Schema defines the schema for the resource.
 func (r *variableResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -67,11 +94,52 @@ func (r *variableResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 }
 
 type resourceVariableModel struct {
-	Name      types.String             `tfsdk:"name"`
-	Type      types.String             `tfsdk:"type"`
-	Id        types.String             `tfsdk:"id"`
-	Notes     types.String             `tfsdk:"notes"`
-	Parameter []ResourceParameterModel `tfsdk:"parameter"`
+	Name            types.String                  `tfsdk:"name"`
+	Type            types.String                  `tfsdk:"type"`
+	Id              types.String                  `tfsdk:"id"`
+	Notes           types.String                  `tfsdk:"notes"`
+	Parameter       []ResourceParameterModel      `tfsdk:"parameter"`
+	ParametersMap   map[string]types.String       `tfsdk:"parameters_map"`
+	Input           types.String                  `tfsdk:"input"`
+	Row             []ResourceLookupTableRowModel `tfsdk:"row"`
+	FormatValue     *ResourceFormatValueModel     `tfsdk:"format_value"`
+	ScheduleStartMs types.Int64                   `tfsdk:"schedule_start_ms"`
+	ScheduleEndMs   types.Int64                   `tfsdk:"schedule_end_ms"`
+	workspaceEntityModel
+	RetryLimit types.Int64 `tfsdk:"retry_limit"`
+}
+
+// ValidateConfig checks that input and row are only used on lookup table variables
+// that do not also set the input or map parameter directly.
+func (r *variableResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var variableType, input types.String
+	var parameter types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &variableType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("input"), &input)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("parameter"), &parameter)...)
+
+	if resp.Diagnostics.HasError() || input.IsNull() {
+		return
+	}
+
+	if !variableType.IsUnknown() && !isLookupTableVariableType(variableType.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("input"), "Unsupported Lookup Table",
+			"input and row are only supported on variables of type "+strings.Join(lookupTableVariableTypes, " or ")+", got "+variableType.ValueString()+".")
+	}
+
+	for _, element := range parameter.Elements() {
+		object, ok := element.(types.Object)
+		if !ok {
+			continue
+		}
+
+		if key, ok := object.Attributes()["key"].(types.String); ok &&
+			(key.ValueString() == lookupTableInputKey || key.ValueString() == lookupTableMapKey) {
+			resp.Diagnostics.AddAttributeError(path.Root("input"), "Conflicting Lookup Table",
+				"input and row cannot be combined with a parameter with key "+key.ValueString()+".")
+		}
+	}
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -85,15 +153,35 @@ func (r *variableResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	dto := toApiVariable(plan, false)
+	client := withRequestLog(ctx, withRetryLimit(r.client, plan.RetryLimit), "variable", sensitiveValues(plan.Parameter)...)
+
+	parameter, err := newReferenceResolver(client).resolveReferences(withParametersMap(plan.Parameter, plan.ParametersMap))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("parameter"), "Error Creating Variable", err.Error())
+		return
+	}
+
+	resolved := plan
+	resolved.Parameter, resolved.ParametersMap = parameter, nil
+
+	dto := toApiVariable(resolved, false)
+	dto.Name = withNamePrefix(client.Options.NamePrefix, dto.Name)
+	dto.Notes = withManagedByNote(managedByNote(client.Options, "variable", time.Now()), dto.Notes)
 
-	variable, err := r.client.CreateVariable(dto)
+	variable, err := client.CreateVariable(dto)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Creating Variable", err.Error())
+		resp.Diagnostics.AddError("Error Creating Variable", redactSensitiveValues(err.Error(), plan.Parameter))
+		return
+	}
+
+	diags = recordCreatedId(ctx, &resp.State, variable.VariableId)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	plan.Id = types.StringValue(variable.VariableId)
+	plan.workspaceEntityModel = workspaceEntityLocation(client, "variables", variable.VariableId)
 
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -110,16 +198,35 @@ func (r *variableResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	variable, err := r.client.Variable(state.Id.ValueString())
+	client := withRequestLog(ctx, withRetryLimit(r.client, state.RetryLimit), "variable", sensitiveValues(state.Parameter)...)
+
+	variable, err := client.Variable(state.Id.ValueString(), variableReadFields)
 	if err == api.ErrNotExist {
 		resp.State.RemoveResource(ctx)
+		warnWorkspaceEmptied(client, "Variable", state.Id.ValueString(), &resp.Diagnostics)
 		return
 	} else if err != nil {
 		resp.Diagnostics.AddError("Error Reading Variable", err.Error())
 		return
 	}
 
-	var resource = toResourceVariable(variable)
+	var resource = toResourceVariable(variable, client)
+	resource.Name = withoutNamePrefix(client.Options.NamePrefix, variable.Name, state.Name)
+	resource.Notes = withoutManagedByNote(remoteManagedByNote(client.Options, "variable", variable.Notes), variable.Notes, state.Notes)
+	if isLookupTableVariableType(variable.Type) &&
+		!hasParameter(state.Parameter, lookupTableInputKey) && !hasParameter(state.Parameter, lookupTableMapKey) {
+		resource.Parameter, resource.Input, resource.Row = decompileLookupTable(resource.Parameter)
+	}
+	if state.ParametersMap != nil {
+		if parametersMap, ok := decompileParametersMap(resource.Parameter); ok {
+			resource.Parameter, resource.ParametersMap = nil, parametersMap
+		}
+	}
+	resource.Parameter = alignParameterOrder(resource.Parameter, state.Parameter)
+	resource.Parameter = keepEmptyValues(resource.Parameter, state.Parameter)
+	resource.Parameter = newReferenceResolver(client).keepReferenceNames(resource.Parameter, state.Parameter)
+	resource.Parameter = keepSensitiveValues(resource.Parameter, state.Parameter)
+	resource.RetryLimit = state.RetryLimit
 
 	diags = resp.State.Set(ctx, &resource)
 	resp.Diagnostics.Append(diags...)
@@ -139,13 +246,29 @@ func (r *variableResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	variable, err := r.client.UpdateVariable(state.Id.ValueString(), toApiVariable(plan, true))
+	client := withRequestLog(ctx, withRetryLimit(r.client, plan.RetryLimit), "variable", sensitiveValues(plan.Parameter)...)
+
+	parameter, err := newReferenceResolver(client).resolveReferences(withParametersMap(plan.Parameter, plan.ParametersMap))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("parameter"), "Error Updating Variable", err.Error())
+		return
+	}
+
+	resolved := plan
+	resolved.Parameter, resolved.ParametersMap = parameter, nil
+
+	dto := toApiVariable(resolved, true)
+	dto.Name = withNamePrefix(client.Options.NamePrefix, dto.Name)
+	dto.Notes = withManagedByNote(managedByNote(client.Options, "variable", time.Now()), dto.Notes)
+
+	variable, err := client.UpdateVariable(state.Id.ValueString(), dto)
 	if err != nil {
-		resp.Diagnostics.AddError("Error Updating Variable", err.Error())
+		resp.Diagnostics.AddError("Error Updating Variable", redactSensitiveValues(err.Error(), plan.Parameter))
 		return
 	}
 
 	plan.Id = types.StringValue(variable.VariableId)
+	plan.workspaceEntityModel = workspaceEntityLocation(client, "variables", variable.VariableId)
 
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -162,7 +285,9 @@ func (r *variableResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
-	err := r.client.DeleteVariable(state.Id.ValueString())
+	client := withRequestLog(ctx, withRetryLimit(r.client, state.RetryLimit), "variable", sensitiveValues(state.Parameter)...)
+
+	err := client.DeleteVariable(state.Id.ValueString())
 	if err == api.ErrNotExist {
 		return
 	} else if err != nil {
@@ -171,8 +296,53 @@ func (r *variableResource) Delete(ctx context.Context, req resource.DeleteReques
 	}
 }
 
+// ImportState imports the variable by its id, by its full GTM path or by its name
+// given as name:<variable name>.
 func (r *variableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	var id string
+	var err error
+
+	if name, ok := strings.CutPrefix(req.ID, nameImportPrefix); ok {
+		var variables []*tagmanager.Variable
+		variables, err = r.client.ListVariables()
+		if err == nil {
+			id, err = variableIdByName(variables, r.client.Options.NamePrefix, name)
+		}
+	} else {
+		id, err = workspaceImportId(r.client, "variables", req.ID)
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("id"), "Error Importing Variable", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// variableIdByName returns the id of the variable named name, with or without the
+// provider's name prefix. When several variables match, e.g. an unprefixed and a
+// prefixed one, the import cannot pick one and they are listed with their ids and types.
+func variableIdByName(variables []*tagmanager.Variable, prefix string, name string) (string, error) {
+	var matches []string
+	var id string
+
+	for _, variable := range variables {
+		if variable.Name == name || variable.Name == withNamePrefix(prefix, name) {
+			id = variable.VariableId
+			matches = append(matches, fmt.Sprintf("%s (%q, type %s)", variable.VariableId, variable.Name, variable.Type))
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no variable named %q exists in the workspace", name)
+	case 1:
+		return id, nil
+	default:
+		return "", fmt.Errorf("%d variables match the name %q: %s. Import the variable by its id instead",
+			len(matches), name, strings.Join(matches, ", "))
+	}
 }
 
 // Equal compares the two models and returns true if they are equal.
@@ -181,7 +351,13 @@ func (m resourceVariableModel) Equal(o resourceVariableModel) bool {
 		!m.Type.Equal(o.Type) ||
 		(!m.Id.IsUnknown() && !m.Id.Equal(o.Id)) ||
 		!m.Notes.Equal(o.Notes) ||
-		len(m.Parameter) != len(o.Parameter) {
+		!m.ScheduleStartMs.Equal(o.ScheduleStartMs) ||
+		!m.ScheduleEndMs.Equal(o.ScheduleEndMs) ||
+		!m.FormatValue.Equal(o.FormatValue) ||
+		!m.Input.Equal(o.Input) ||
+		!equalParametersMap(m.ParametersMap, o.ParametersMap) ||
+		len(m.Parameter) != len(o.Parameter) ||
+		len(m.Row) != len(o.Row) {
 		return false
 	}
 
@@ -191,33 +367,62 @@ func (m resourceVariableModel) Equal(o resourceVariableModel) bool {
 		}
 	}
 
+	for i := range m.Row {
+		if !m.Row[i].Case.Equal(o.Row[i].Case) || !m.Row[i].Value.Equal(o.Row[i].Value) {
+			return false
+		}
+	}
+
 	return true
 }
 
-func toResourceVariable(variable *tagmanager.Variable) resourceVariableModel {
+// variableReadFields are the fields of a variable that toResourceVariable maps, the only
+// ones requested when the variable is read.
+const variableReadFields googleapi.Field = "variableId,name,type,notes,parameter,formatValue,scheduleStartMs,scheduleEndMs"
+
+func toResourceVariable(variable *tagmanager.Variable, client *api.ClientInWorkspace) resourceVariableModel {
 	return resourceVariableModel{
-		Name:      types.StringValue(variable.Name),
-		Type:      types.StringValue(variable.Type),
-		Id:        types.StringValue(variable.VariableId),
-		Notes:     nullableStringValue(variable.Notes),
-		Parameter: toResourceParameter(variable.Parameter),
+		Name:                 types.StringValue(variable.Name),
+		Type:                 types.StringValue(variable.Type),
+		Id:                   types.StringValue(variable.VariableId),
+		Notes:                nullableStringValue(variable.Notes),
+		Parameter:            toResourceParameter(variable.Parameter),
+		FormatValue:          toResourceFormatValue(variable.FormatValue),
+		ScheduleStartMs:      nullableInt64Value(variable.ScheduleStartMs),
+		ScheduleEndMs:        nullableInt64Value(variable.ScheduleEndMs),
+		workspaceEntityModel: workspaceEntityLocation(client, "variables", variable.VariableId),
 	}
 }
+
 func toApiVariable(resource resourceVariableModel, id bool) *tagmanager.Variable {
+	var parameter = resource.Parameter
+	if resource.ParametersMap != nil {
+		parameter = compileParametersMap(resource.ParametersMap)
+	}
+	if len(resource.Row) > 0 {
+		parameter = append(append([]ResourceParameterModel{}, parameter...), compileLookupTable(resource.Input, resource.Row)...)
+	}
+
 	if !id {
 		return &tagmanager.Variable{
-			Name:      resource.Name.ValueString(),
-			Type:      resource.Type.ValueString(),
-			Notes:     resource.Notes.ValueString(),
-			Parameter: toApiParameter(resource.Parameter),
+			Name:            resource.Name.ValueString(),
+			Type:            resource.Type.ValueString(),
+			Notes:           resource.Notes.ValueString(),
+			Parameter:       toApiParameter(parameter),
+			FormatValue:     toApiFormatValue(resource.FormatValue),
+			ScheduleStartMs: resource.ScheduleStartMs.ValueInt64(),
+			ScheduleEndMs:   resource.ScheduleEndMs.ValueInt64(),
 		}
 	}
 
 	return &tagmanager.Variable{
-		Name:       resource.Name.ValueString(),
-		Type:       resource.Type.ValueString(),
-		VariableId: resource.Id.String(),
-		Notes:      resource.Notes.ValueString(),
-		Parameter:  toApiParameter(resource.Parameter),
+		Name:            resource.Name.ValueString(),
+		Type:            resource.Type.ValueString(),
+		VariableId:      resource.Id.String(),
+		Notes:           resource.Notes.ValueString(),
+		Parameter:       toApiParameter(parameter),
+		FormatValue:     toApiFormatValue(resource.FormatValue),
+		ScheduleStartMs: resource.ScheduleStartMs.ValueInt64(),
+		ScheduleEndMs:   resource.ScheduleEndMs.ValueInt64(),
 	}
 }
@@ -0,0 +1,246 @@
+package provider
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that a scheduled variable keeps its schedule on the containers that support it
+func TestAccVariableResource_schedule(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	if os.Getenv("GTM_TEST_VARIABLE_SCHEDULE") == "" {
+		t.Skip("GTM_TEST_VARIABLE_SCHEDULE must be set when the test container supports variable scheduling")
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVariableResourceScheduleConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_variable.scheduled", "schedule_start_ms", "1893456000000"),
+					resource.TestCheckResourceAttr("gtm_variable.scheduled", "schedule_end_ms", "1924992000000"),
+				),
+			},
+			{
+				ResourceName:      "gtm_variable.scheduled",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// Test that the schedule fields survive the round trip through the API model
+func TestVariableSchedule_roundTrip(t *testing.T) {
+	planned := resourceVariableModel{
+		Name:            types.StringValue("scheduled"),
+		Type:            types.StringValue("c"),
+		Id:              types.StringValue("7"),
+		Notes:           types.StringNull(),
+		ScheduleStartMs: types.Int64Value(1893456000000),
+		ScheduleEndMs:   types.Int64Value(1924992000000),
+	}
+
+	variable := toApiVariable(planned, false)
+	variable.VariableId = "7"
+
+	if read := toResourceVariable(variable, testClientInWorkspace()); !read.Equal(planned) {
+		t.Fatalf("expected %+v, got %+v", planned, read)
+	}
+
+	planned.ScheduleStartMs, planned.ScheduleEndMs = types.Int64Null(), types.Int64Null()
+
+	variable = toApiVariable(planned, false)
+	variable.VariableId = "7"
+
+	if read := toResourceVariable(variable, testClientInWorkspace()); !read.ScheduleStartMs.IsNull() || !read.ScheduleEndMs.IsNull() {
+		t.Fatalf("expected an unscheduled variable to read back null, got %+v", read)
+	}
+}
+
+// Test that convert_undefined_to_value keeps a variable reference
+func TestAccVariableResource_formatValue(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVariableResourceFormatValueConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_variable.formatted", "format_value.convert_undefined_to_value", "{{tf-test-default}}"),
+					resource.TestCheckResourceAttr("gtm_variable.formatted", "format_value.case_conversion_type", "lowercase"),
+				),
+			},
+			{
+				ResourceName:      "gtm_variable.formatted",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// Test that the format value survives the round trip through the API model
+func TestVariableFormatValue_roundTrip(t *testing.T) {
+	planned := resourceVariableModel{
+		Name:  types.StringValue("formatted"),
+		Type:  types.StringValue("v"),
+		Id:    types.StringValue("7"),
+		Notes: types.StringNull(),
+		FormatValue: &ResourceFormatValueModel{
+			CaseConversionType:      types.StringNull(),
+			ConvertNullToValue:      types.StringNull(),
+			ConvertUndefinedToValue: types.StringValue("{{Default}}"),
+			ConvertTrueToValue:      types.StringNull(),
+			ConvertFalseToValue:     types.StringValue("no"),
+		},
+	}
+
+	variable := toApiVariable(planned, false)
+	variable.VariableId = "7"
+
+	if variable.FormatValue.ConvertUndefinedToValue.Type != "template" || variable.FormatValue.ConvertNullToValue != nil {
+		t.Fatalf("unexpected format value %+v", variable.FormatValue)
+	}
+
+	if read := toResourceVariable(variable, testClientInWorkspace()); !read.Equal(planned) {
+		t.Fatalf("expected %+v, got %+v", planned.FormatValue, read.FormatValue)
+	}
+}
+
+// Test that a lookup table variable built from input and row reads back its rows
+func TestAccVariableResource_lookupTable(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVariableResourceLookupTableConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_variable.lookup_table", "input", "{{Page Path}}"),
+					resource.TestCheckResourceAttr("gtm_variable.lookup_table", "row.#", "2"),
+					resource.TestCheckResourceAttr("gtm_variable.lookup_table", "row.1.case", "/cart"),
+				),
+			},
+			{
+				ResourceName:      "gtm_variable.lookup_table",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// Test that a name import finds the variable with or without the name prefix and
+// rejects names that match no or several variables
+func TestVariableIdByName(t *testing.T) {
+	variables := []*tagmanager.Variable{
+		{VariableId: "1", Name: "page path", Type: "v"},
+		{VariableId: "2", Name: "tf-page path", Type: "c"},
+		{VariableId: "3", Name: "tf-referrer", Type: "v"},
+	}
+
+	if id, err := variableIdByName(variables, "tf-", "referrer"); err != nil || id != "3" {
+		t.Fatalf("expected variable 3, got %q and %v", id, err)
+	}
+
+	if id, err := variableIdByName(variables, "", "page path"); err != nil || id != "1" {
+		t.Fatalf("expected variable 1, got %q and %v", id, err)
+	}
+
+	if _, err := variableIdByName(variables, "tf-", "missing"); err == nil || !strings.Contains(err.Error(), `no variable named "missing"`) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+
+	_, err := variableIdByName(variables, "tf-", "page path")
+	if err == nil || !strings.Contains(err.Error(), `1 ("page path", type v)`) || !strings.Contains(err.Error(), `2 ("tf-page path", type c)`) {
+		t.Fatalf("expected an ambiguity error listing both variables, got %v", err)
+	}
+}
+
+func testAccVariableResourceScheduleConfig() string {
+	return testAccProviderConfig() + `
+resource "gtm_variable" "scheduled" {
+  name              = "tf-test-scheduled-variable"
+  type              = "c"
+  schedule_start_ms = 1893456000000
+  schedule_end_ms   = 1924992000000
+
+  parameter = [
+    {
+      key   = "value"
+      type  = "template"
+      value = "scheduled"
+    }
+  ]
+}
+`
+}
+
+func testAccVariableResourceFormatValueConfig() string {
+	return testAccProviderConfig() + `
+resource "gtm_variable" "default" {
+  name = "tf-test-default"
+  type = "c"
+
+  parameter = [
+    {
+      key   = "value"
+      type  = "template"
+      value = "fallback"
+    }
+  ]
+}
+
+resource "gtm_variable" "formatted" {
+  name = "tf-test-formatted-variable"
+  type = "v"
+
+  parameter = [
+    {
+      key   = "name"
+      type  = "template"
+      value = "formatted"
+    }
+  ]
+
+  format_value = {
+    case_conversion_type       = "lowercase"
+    convert_undefined_to_value = "{{${gtm_variable.default.name}}}"
+  }
+}
+`
+}
+
+func testAccVariableResourceLookupTableConfig() string {
+	return testAccProviderConfig() + `
+resource "gtm_variable" "lookup_table" {
+  name  = "tf-test-lookup-table"
+  type  = "smm"
+  input = "{{Page Path}}"
+
+  row = [
+    {
+      case  = "/checkout"
+      value = "checkout"
+    },
+    {
+      case  = "/cart"
+      value = "cart"
+    }
+  ]
+}
+`
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ datasource.DataSource              = &variablesDataSource{}
+	_ datasource.DataSourceWithConfigure = &variablesDataSource{}
+)
+
+type variablesDataSource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewVariablesDataSource() datasource.DataSource {
+	return &variablesDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *variablesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(d.client, &resp.Diagnostics)
+}
+
+// Metadata returns the data source type name.
+func (d *variablesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_variables"
+}
+
+// Schema defines the schema for the data source.
+func (d *variablesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the variables of the workspace.",
+		Attributes: map[string]schema.Attribute{
+			"variables": entityListSchema("variable"),
+		},
+	}
+}
+
+type dataSourceVariablesModel struct {
+	Variables []dataSourceEntityModel `tfsdk:"variables"`
+}
+
+func toDataSourceVariables(variables []*tagmanager.Variable) dataSourceVariablesModel {
+	var entities = make([]dataSourceEntityModel, 0, len(variables))
+
+	for _, variable := range variables {
+		entities = append(entities, dataSourceEntityModel{
+			Id:   types.StringValue(variable.VariableId),
+			Name: types.StringValue(variable.Name),
+			Type: types.StringValue(variable.Type),
+		})
+	}
+
+	return dataSourceVariablesModel{Variables: sortEntities(entities)}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *variablesDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	variables, err := d.client.ListVariables()
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Variables", err.Error())
+		return
+	}
+
+	var state = toDataSourceVariables(variables)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,233 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+var (
+	_ resource.Resource                = &versionResource{}
+	_ resource.ResourceWithConfigure   = &versionResource{}
+	_ resource.ResourceWithImportState = &versionResource{}
+)
+
+type versionResource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewVersionResource() resource.Resource {
+	return &versionResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *versionResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(r.client, &resp.Diagnostics)
+}
+
+// Metadata returns the resource type name.
+func (r *versionResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_version"
+}
+
+var versionResourceSchemaAttributes = map[string]schema.Attribute{
+	"name": schema.StringAttribute{
+		Description: "The name of the container version.",
+		Required:    true,
+	},
+	"description": schema.StringAttribute{
+		Description: "The description of the container version.",
+		Optional:    true,
+		Validators:  notesValidators,
+	},
+	"id": schema.StringAttribute{
+		Description: "The ID of the container version.",
+		Computed:    true,
+	},
+	"compiler_error": schema.BoolAttribute{
+		Description: "Whether GTM reported compiler errors when creating the version. Creating a version from a workspace with compiler errors fails.",
+		Computed:    true,
+		PlanModifiers: []planmodifier.Bool{
+			boolplanmodifier.UseStateForUnknown(),
+		},
+	},
+}
+
+// Schema defines the schema for the resource.
+func (r *versionResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Creates a container version from the changes of the workspace. GTM replaces the workspace with a new one once the version is created.",
+		Attributes:  versionResourceSchemaAttributes,
+	}
+}
+
+type resourceVersionModel struct {
+	Name          types.String `tfsdk:"name"`
+	Description   types.String `tfsdk:"description"`
+	Id            types.String `tfsdk:"id"`
+	CompilerError types.Bool   `tfsdk:"compiler_error"`
+}
+
+// versionProblems lists why GTM could not create a version from the workspace.
+func versionProblems(created *tagmanager.CreateContainerVersionResponse) []string {
+	var problems []string
+
+	if created.CompilerError {
+		problems = append(problems, "The workspace has compiler errors, e.g. a tag firing on a deleted trigger or referencing a missing variable.")
+	}
+
+	if created.SyncStatus != nil && created.SyncStatus.MergeConflict {
+		problems = append(problems, "The workspace has merge conflicts with the latest container version.")
+	}
+
+	if created.SyncStatus != nil && created.SyncStatus.SyncError {
+		problems = append(problems, "The workspace could not be synced with the latest container version.")
+	}
+
+	if len(problems) == 0 && created.ContainerVersion == nil {
+		problems = append(problems, "GTM did not return the created version.")
+	}
+
+	return problems
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *versionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan resourceVersionModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	created, err := r.client.CreateVersion(&tagmanager.CreateContainerVersionRequestVersionOptions{
+		Name:  plan.Name.ValueString(),
+		Notes: plan.Description.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Error Creating Version", err.Error())
+		return
+	}
+
+	if problems := versionProblems(created); len(problems) > 0 {
+		resp.Diagnostics.AddError("Error Creating Version",
+			"GTM could not create a version from workspace "+r.client.Options.WorkspaceId+":\n- "+strings.Join(problems, "\n- "))
+		return
+	}
+
+	diags = recordCreatedId(ctx, &resp.State, created.ContainerVersion.ContainerVersionId)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = types.StringValue(created.ContainerVersion.ContainerVersionId)
+	plan.CompilerError = types.BoolValue(created.CompilerError)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *versionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state resourceVersionModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, err := r.client.Version(state.Id.ValueString())
+	if err == api.ErrNotExist || (err == nil && version.Deleted) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Error Reading Version", err.Error())
+		return
+	}
+
+	state.Name = types.StringValue(version.Name)
+	state.Description = nullableStringValue(version.Description)
+	state.Id = types.StringValue(version.ContainerVersionId)
+	if state.CompilerError.IsNull() {
+		state.CompilerError = types.BoolValue(false)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *versionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state resourceVersionModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version, err := r.client.Version(state.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating Version", err.Error())
+		return
+	}
+
+	version.Name = plan.Name.ValueString()
+	version.Description = plan.Description.ValueString()
+
+	version, err = r.client.UpdateVersion(state.Id.ValueString(), version)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Updating Version", err.Error())
+		return
+	}
+
+	plan.Id = types.StringValue(version.ContainerVersionId)
+	plan.CompilerError = state.CompilerError
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *versionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state resourceVersionModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteVersion(state.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error Deleting Version", err.Error())
+		return
+	}
+}
+
+func (r *versionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,27 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that the compiler errors of a create-version response are listed as problems
+func TestVersionProblems(t *testing.T) {
+	problems := versionProblems(&tagmanager.CreateContainerVersionResponse{
+		CompilerError: true,
+		SyncStatus:    &tagmanager.SyncStatus{MergeConflict: true},
+	})
+
+	if len(problems) != 2 || !strings.Contains(problems[0], "compiler errors") || !strings.Contains(problems[1], "merge conflicts") {
+		t.Fatalf("expected the compiler error and merge conflict to be listed, got %v", problems)
+	}
+
+	problems = versionProblems(&tagmanager.CreateContainerVersionResponse{
+		ContainerVersion: &tagmanager.ContainerVersion{ContainerVersionId: "12"},
+	})
+	if len(problems) != 0 {
+		t.Fatalf("expected no problems for a created version, got %v", problems)
+	}
+}
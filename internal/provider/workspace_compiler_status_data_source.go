@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-google-tag-manager/internal/api"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &workspaceCompilerStatusDataSource{}
+	_ datasource.DataSourceWithConfigure = &workspaceCompilerStatusDataSource{}
+)
+
+type workspaceCompilerStatusDataSource struct {
+	client *api.ClientInWorkspace
+}
+
+func NewWorkspaceCompilerStatusDataSource() datasource.DataSource {
+	return &workspaceCompilerStatusDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *workspaceCompilerStatusDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*api.ClientInWorkspace)
+	requireWorkspace(d.client, &resp.Diagnostics)
+}
+
+// Metadata returns the data source type name.
+func (d *workspaceCompilerStatusDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_compiler_status"
+}
+
+// Schema defines the schema for the data source.
+func (d *workspaceCompilerStatusDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Compiles the workspace with a quick preview, without creating a version, e.g. to check in a precondition that a version can be created from it.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the workspace.",
+				Computed:    true,
+			},
+			"compiles": schema.BoolAttribute{
+				Description: "Whether the workspace compiles cleanly, i.e. none of compiler_error, merge_conflict and sync_error is set.",
+				Computed:    true,
+			},
+			"compiler_error": schema.BoolAttribute{
+				Description: "Whether compiling the workspace failed, e.g. because of a tag referencing a missing trigger.",
+				Computed:    true,
+			},
+			"merge_conflict": schema.BoolAttribute{
+				Description: "Whether syncing the workspace to the latest container version ran into a merge conflict.",
+				Computed:    true,
+			},
+			"sync_error": schema.BoolAttribute{
+				Description: "Whether syncing the workspace to the latest container version failed.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type dataSourceWorkspaceCompilerStatusModel struct {
+	Id            types.String `tfsdk:"id"`
+	Compiles      types.Bool   `tfsdk:"compiles"`
+	CompilerError types.Bool   `tfsdk:"compiler_error"`
+	MergeConflict types.Bool   `tfsdk:"merge_conflict"`
+	SyncError     types.Bool   `tfsdk:"sync_error"`
+}
+
+func toDataSourceWorkspaceCompilerStatus(workspaceId string, status *api.WorkspaceCompilerStatus) dataSourceWorkspaceCompilerStatusModel {
+	return dataSourceWorkspaceCompilerStatusModel{
+		Id:            types.StringValue(workspaceId),
+		Compiles:      types.BoolValue(status.Compiles()),
+		CompilerError: types.BoolValue(status.CompilerError),
+		MergeConflict: types.BoolValue(status.MergeConflict),
+		SyncError:     types.BoolValue(status.SyncError),
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *workspaceCompilerStatusDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	status, err := d.client.GetWorkspaceCompilerStatus()
+	if err != nil {
+		resp.Diagnostics.AddError("Error Reading Workspace Compiler Status", err.Error())
+		return
+	}
+
+	var state = toDataSourceWorkspaceCompilerStatus(d.client.Options.WorkspaceId, status)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,24 @@
+package provider
+
+import (
+	"terraform-provider-google-tag-manager/internal/api"
+	"testing"
+)
+
+// Test that a workspace compiles only without compiler error, merge conflict and sync error
+func TestToDataSourceWorkspaceCompilerStatus(t *testing.T) {
+	clean := toDataSourceWorkspaceCompilerStatus("3", &api.WorkspaceCompilerStatus{})
+	if clean.Id.ValueString() != "3" || !clean.Compiles.ValueBool() || clean.CompilerError.ValueBool() {
+		t.Fatalf("expected a clean workspace to compile, got %v", clean)
+	}
+
+	broken := toDataSourceWorkspaceCompilerStatus("3", &api.WorkspaceCompilerStatus{CompilerError: true})
+	if broken.Compiles.ValueBool() || !broken.CompilerError.ValueBool() {
+		t.Fatalf("expected a compiler error to fail the workspace, got %v", broken)
+	}
+
+	conflict := toDataSourceWorkspaceCompilerStatus("3", &api.WorkspaceCompilerStatus{MergeConflict: true})
+	if conflict.Compiles.ValueBool() || !conflict.MergeConflict.ValueBool() {
+		t.Fatalf("expected a merge conflict to fail the workspace, got %v", conflict)
+	}
+}
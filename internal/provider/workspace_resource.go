@@ -2,16 +2,24 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"terraform-provider-google-tag-manager/internal/api"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"google.golang.org/api/tagmanager/v2"
 )
 
 var (
-	_ resource.ResourceWithConfigure = &workspaceResource{}
+	_ resource.ResourceWithConfigure  = &workspaceResource{}
+	_ resource.ResourceWithModifyPlan = &workspaceResource{}
 )
 
 func NewWorkspaceResource() resource.Resource {
@@ -47,25 +55,81 @@ func (r *workspaceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"description": schema.StringAttribute{
 				Description: "The description of the workspace.",
 				Optional:    true,
+				Validators:  notesValidators,
+			},
+			"base_version_id": schema.StringAttribute{
+				Description: "The ID of the container version to base the workspace on. The version is marked as the latest container version while the workspace is created, and the previous latest version is set back afterwards. Defaults to the current latest version.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"id": schema.StringAttribute{
 				Description: "The ID of the workspace.",
 				Computed:    true,
 			},
+			"tag_manager_url": schema.StringAttribute{
+				Description: "The URL of the workspace in the GTM UI.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sync_on_create": schema.BoolAttribute{
+				Description: "Whether to sync the workspace with the latest container version once it is created. Changes that conflict with the workspace are not merged and are reported as warnings.",
+				Optional:    true,
+			},
+			"keep_synced": schema.BoolAttribute{
+				Description: "Whether to sync the workspace with the latest container version on every apply that finds a version newer than the one it was last synced with. Changes that conflict with the workspace are not merged and are reported as warnings.",
+				Optional:    true,
+			},
+			"synced_version_id": schema.StringAttribute{
+				Description: "The ID of the latest container version when the provider last synced the workspace, null when it never did. With keep_synced, a plan that changes it means the workspace is behind the latest version and will be synced.",
+				Computed:    true,
+			},
+			"conflict_resolution": schema.StringAttribute{
+				Description: "How to resolve the merge conflicts reported by sync_on_create and keep_synced: workspace keeps the entities of the workspace, base_version takes the ones of the latest container version. Conflicts are left unresolved when unset.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(conflictResolutionWorkspace, conflictResolutionBaseVersion),
+				},
+			},
 		},
 	}
 }
 
 type workspaceResourceModel struct {
-	Name        types.String `tfsdk:"name"`
-	Description types.String `tfsdk:"description"`
-	Id          types.String `tfsdk:"id"`
+	Name               types.String `tfsdk:"name"`
+	Description        types.String `tfsdk:"description"`
+	BaseVersionId      types.String `tfsdk:"base_version_id"`
+	Id                 types.String `tfsdk:"id"`
+	TagManagerUrl      types.String `tfsdk:"tag_manager_url"`
+	SyncOnCreate       types.Bool   `tfsdk:"sync_on_create"`
+	KeepSynced         types.Bool   `tfsdk:"keep_synced"`
+	SyncedVersionId    types.String `tfsdk:"synced_version_id"`
+	ConflictResolution types.String `tfsdk:"conflict_resolution"`
 }
 
+// overwriteWorkspaceResource copies the workspace returned by GTM into resource. GTM
+// omits an empty description, so an unset description is kept null.
 func overwriteWorkspaceResource(workspace *tagmanager.Workspace, resource *workspaceResourceModel) {
 	resource.Name = types.StringValue(workspace.Name)
-	resource.Description = types.StringValue(workspace.Description)
+	if workspace.Description != "" || !resource.Description.IsNull() {
+		resource.Description = types.StringValue(workspace.Description)
+	}
 	resource.Id = types.StringValue(workspace.WorkspaceId)
+	resource.TagManagerUrl = types.StringValue(workspaceTagManagerUrl(workspace))
+}
+
+// workspaceTagManagerUrl returns the URL of the workspace in the GTM UI. It is derived
+// from the ids of the workspace when GTM does not return it.
+func workspaceTagManagerUrl(workspace *tagmanager.Workspace) string {
+	if workspace.TagManagerUrl != "" {
+		return workspace.TagManagerUrl
+	}
+
+	return fmt.Sprintf("https://tagmanager.google.com/#/container/accounts/%s/containers/%s/workspaces/%s",
+		workspace.AccountId, workspace.ContainerId, workspace.WorkspaceId)
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -77,22 +141,193 @@ func (r *workspaceResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	workspace, err := r.client.CreateWorkspace(&tagmanager.Workspace{
+	dto := &tagmanager.Workspace{
 		Name:        plan.Name.ValueString(),
 		Description: plan.Description.ValueString(),
-	})
+	}
 
-	if err != nil {
-		resp.Diagnostics.AddError("Error Creating Workspace", err.Error())
+	var workspace *tagmanager.Workspace
+	var err error
+	if plan.BaseVersionId.IsNull() {
+		workspace, err = r.client.CreateWorkspace(dto)
+	} else {
+		workspace, err = r.client.CreateWorkspaceFromVersion(plan.BaseVersionId.ValueString(), dto)
+	}
+
+	if err != nil && workspace != nil && errors.Is(err, api.ErrLatestVersionNotRestored) {
+		resp.Diagnostics.AddWarning("Latest Version Not Restored",
+			"The workspace was created from base_version_id, but the previous latest version of the container could not be set back, "+
+				"which changes the version new workspaces are based on. Set it back in GTM: "+err.Error())
+	} else if err != nil {
+		addWorkspaceCreateError(&resp.Diagnostics, "Error Creating Workspace", err)
+		return
+	}
+
+	diags = recordCreatedId(ctx, &resp.State, workspace.WorkspaceId)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	overwriteWorkspaceResource(workspace, &plan)
+	plan.SyncedVersionId = types.StringNull()
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	if plan.SyncOnCreate.ValueBool() || plan.KeepSynced.ValueBool() {
+		plan.SyncedVersionId = r.sync(workspace.WorkspaceId, plan.ConflictResolution.ValueString(), &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+// sync syncs the workspace with the latest container version, resolves the merge
+// conflicts with strategy when it is set and reports the others. It returns the ID of
+// the latest container version the workspace was synced with, which is null when the
+// container has no version yet.
+func (r *workspaceResource) sync(workspaceId string, strategy string, diags *diag.Diagnostics) types.String {
+	var versionId = types.StringNull()
+	if header, err := r.client.LatestVersionHeader(); err == nil {
+		versionId = nullableStringValue(header.ContainerVersionId)
+	} else if err != api.ErrNotExist {
+		diags.AddError("Error Reading Latest Version", err.Error())
+		return versionId
+	}
+
+	sync, err := r.client.SyncWorkspace(workspaceId)
+	if err != nil {
+		diags.AddError("Error Syncing Workspace", err.Error())
+		return versionId
+	}
+
+	if strategy != "" && (sync.SyncStatus == nil || !sync.SyncStatus.SyncError) {
+		sync.MergeConflict = resolveMergeConflicts(sync.MergeConflict, strategy, func(entity *tagmanager.Entity) error {
+			return r.client.Client.ResolveConflict(workspaceId, entity)
+		}, diags)
+	}
+
+	addSyncDiagnostics(diags, sync)
+
+	return versionId
+}
+
+// ModifyPlan keeps synced_version_id unless keep_synced is set and a container version
+// newer than the one the workspace was last synced with exists, in which case the
+// planned change makes the update sync the workspace.
+func (r *workspaceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan, state workspaceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.SyncedVersionId = state.SyncedVersionId
+
+	if plan.KeepSynced.ValueBool() {
+		header, err := r.client.LatestVersionHeader()
+		if err != nil && err != api.ErrNotExist {
+			resp.Diagnostics.AddError("Error Reading Latest Version", err.Error())
+			return
+		}
+
+		if err == nil && workspaceBehind(state.SyncedVersionId, header.ContainerVersionId) {
+			plan.SyncedVersionId = types.StringValue(header.ContainerVersionId)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// workspaceBehind reports whether a workspace last synced with syncedVersionId has to be
+// synced to catch up with the latest container version.
+func workspaceBehind(syncedVersionId types.String, latestVersionId string) bool {
+	return latestVersionId != "" && syncedVersionId.ValueString() != latestVersionId
+}
+
+// Values of conflict_resolution.
+const (
+	conflictResolutionWorkspace   = "workspace"
+	conflictResolutionBaseVersion = "base_version"
+)
+
+// resolveMergeConflicts resolves the merge conflicts of a sync with the entity in the
+// workspace or in the base version depending on strategy, and returns the conflicts
+// that are left unresolved.
+func resolveMergeConflicts(conflicts []*tagmanager.MergeConflict, strategy string, resolve func(entity *tagmanager.Entity) error, diags *diag.Diagnostics) []*tagmanager.MergeConflict {
+	var unresolved []*tagmanager.MergeConflict
+
+	for _, conflict := range conflicts {
+		entity := conflict.EntityInWorkspace
+		if strategy == conflictResolutionBaseVersion {
+			entity = conflict.EntityInBaseVersion
+		}
+
+		if entity == nil {
+			unresolved = append(unresolved, conflict)
+			continue
+		}
+
+		if err := resolve(entity); err != nil {
+			diags.AddError("Error Resolving Merge Conflict", describeEntity(entity)+": "+err.Error())
+			unresolved = append(unresolved, conflict)
+		}
+	}
+
+	return unresolved
+}
+
+// addSyncDiagnostics reports the outcome of a workspace sync. Merge conflicts are left
+// to be resolved in the GTM UI, so they are reported as warnings.
+func addSyncDiagnostics(diags *diag.Diagnostics, sync *tagmanager.SyncWorkspaceResponse) {
+	if sync.SyncStatus != nil && sync.SyncStatus.SyncError {
+		diags.AddError("Error Syncing Workspace", "The workspace could not be synced with the latest container version.")
+		return
+	}
+
+	for _, conflict := range sync.MergeConflict {
+		entity := conflict.EntityInWorkspace
+		if entity == nil {
+			entity = conflict.EntityInBaseVersion
+		}
+
+		diags.AddWarning("Unresolved Merge Conflict",
+			describeEntity(entity)+" was changed both in the workspace and in the latest container version. "+
+				"Resolve the conflict in the Google Tag Manager UI or set conflict_resolution.")
+	}
+}
+
+// describeEntity returns the kind, name and id of a workspace entity.
+func describeEntity(entity *tagmanager.Entity) string {
+	switch {
+	case entity == nil:
+		return "An entity"
+	case entity.Tag != nil:
+		return fmt.Sprintf("Tag %q (%s)", entity.Tag.Name, entity.Tag.TagId)
+	case entity.Trigger != nil:
+		return fmt.Sprintf("Trigger %q (%s)", entity.Trigger.Name, entity.Trigger.TriggerId)
+	case entity.Variable != nil:
+		return fmt.Sprintf("Variable %q (%s)", entity.Variable.Name, entity.Variable.VariableId)
+	case entity.Folder != nil:
+		return fmt.Sprintf("Folder %q (%s)", entity.Folder.Name, entity.Folder.FolderId)
+	case entity.CustomTemplate != nil:
+		return fmt.Sprintf("Custom template %q (%s)", entity.CustomTemplate.Name, entity.CustomTemplate.TemplateId)
+	case entity.BuiltInVariable != nil:
+		return fmt.Sprintf("Built-in variable %q", entity.BuiltInVariable.Type)
+	default:
+		return "An entity"
+	}
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -114,7 +349,7 @@ func (r *workspaceResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	overwriteWorkspaceResource(workspace, &state)
-	diags = req.State.Set(ctx, &state)
+	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -144,11 +379,33 @@ func (r *workspaceResource) Update(ctx context.Context, req resource.UpdateReque
 	}
 
 	overwriteWorkspaceResource(workspace, &plan)
+	if plan.SyncedVersionId.IsUnknown() {
+		plan.SyncedVersionId = state.SyncedVersionId
+	}
+
+	if !plan.KeepSynced.ValueBool() || plan.SyncedVersionId.Equal(state.SyncedVersionId) {
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	// The workspace keeps its last synced version in state until the sync succeeds.
+	var planned = plan.SyncedVersionId
+	plan.SyncedVersionId = state.SyncedVersionId
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	r.sync(state.Id.ValueString(), plan.ConflictResolution.ValueString(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.SyncedVersionId = planned
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
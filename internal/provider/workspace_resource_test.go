@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"google.golang.org/api/tagmanager/v2"
+)
+
+// Test that the merge conflicts left by syncing a stale workspace are reported as warnings
+func TestAddSyncDiagnostics(t *testing.T) {
+	var diags diag.Diagnostics
+	addSyncDiagnostics(&diags, &tagmanager.SyncWorkspaceResponse{
+		SyncStatus: &tagmanager.SyncStatus{MergeConflict: true},
+		MergeConflict: []*tagmanager.MergeConflict{
+			{
+				EntityInWorkspace:   &tagmanager.Entity{Tag: &tagmanager.Tag{TagId: "4", Name: "purchase"}},
+				EntityInBaseVersion: &tagmanager.Entity{Tag: &tagmanager.Tag{TagId: "4", Name: "purchase"}},
+			},
+			{
+				EntityInBaseVersion: &tagmanager.Entity{Trigger: &tagmanager.Trigger{TriggerId: "7", Name: "checkout"}},
+			},
+		},
+	})
+
+	if diags.HasError() || diags.WarningsCount() != 2 {
+		t.Fatalf("expected two warnings, got %v", diags)
+	}
+
+	if !strings.Contains(diags[0].Detail(), `Tag "purchase" (4)`) || !strings.Contains(diags[1].Detail(), `Trigger "checkout" (7)`) {
+		t.Fatalf("expected the conflicting entities to be named, got %v", diags)
+	}
+
+	diags = nil
+	addSyncDiagnostics(&diags, &tagmanager.SyncWorkspaceResponse{})
+	if len(diags) != 0 {
+		t.Fatalf("expected a clean sync to report nothing, got %v", diags)
+	}
+
+	addSyncDiagnostics(&diags, &tagmanager.SyncWorkspaceResponse{SyncStatus: &tagmanager.SyncStatus{SyncError: true}})
+	if !diags.HasError() {
+		t.Fatal("expected a sync error to be reported as an error")
+	}
+}
+
+// Test that the description returned by GTM replaces the one in state, and that the
+// empty description GTM returns for an unset one is kept null
+func TestOverwriteWorkspaceResource(t *testing.T) {
+	resource := workspaceResourceModel{Name: types.StringValue("ws"), Description: types.StringValue("before")}
+	overwriteWorkspaceResource(&tagmanager.Workspace{WorkspaceId: "3", Name: "ws", Description: "after"}, &resource)
+	if resource.Description.ValueString() != "after" || resource.Id.ValueString() != "3" {
+		t.Fatalf("expected the description to be updated, got %v", resource)
+	}
+
+	resource = workspaceResourceModel{Name: types.StringValue("ws"), Description: types.StringNull()}
+	overwriteWorkspaceResource(&tagmanager.Workspace{WorkspaceId: "3", Name: "ws"}, &resource)
+	if !resource.Description.IsNull() {
+		t.Fatalf("expected an unset description to stay null, got %v", resource.Description)
+	}
+
+	resource = workspaceResourceModel{Name: types.StringValue("ws"), Description: types.StringValue("removed")}
+	overwriteWorkspaceResource(&tagmanager.Workspace{WorkspaceId: "3", Name: "ws"}, &resource)
+	if resource.Description.IsNull() || resource.Description.ValueString() != "" {
+		t.Fatalf("expected a description cleared in GTM to read back empty, got %v", resource.Description)
+	}
+}
+
+// Test that the GTM UI URL of a workspace is derived from its ids when GTM omits it
+func TestWorkspaceTagManagerUrl(t *testing.T) {
+	resource := workspaceResourceModel{Name: types.StringValue("ws")}
+	overwriteWorkspaceResource(&tagmanager.Workspace{AccountId: "1", ContainerId: "2", WorkspaceId: "3", Name: "ws"}, &resource)
+	if url := resource.TagManagerUrl.ValueString(); url != "https://tagmanager.google.com/#/container/accounts/1/containers/2/workspaces/3" {
+		t.Fatalf("expected the url to be derived from the ids, got %s", url)
+	}
+
+	returned := "https://tagmanager.google.com/#/container/accounts/1/containers/2/workspaces/3?apiLink=workspace"
+	if url := workspaceTagManagerUrl(&tagmanager.Workspace{AccountId: "1", ContainerId: "2", WorkspaceId: "3", TagManagerUrl: returned}); url != returned {
+		t.Fatalf("expected the url returned by GTM to be kept, got %s", url)
+	}
+}
+
+// Test that merge conflicts are resolved with the entity picked by conflict_resolution and
+// that the ones that cannot be resolved are left to be reported
+func TestResolveMergeConflicts(t *testing.T) {
+	conflicts := []*tagmanager.MergeConflict{
+		{
+			EntityInWorkspace:   &tagmanager.Entity{Tag: &tagmanager.Tag{TagId: "4", Name: "purchase", Notes: "workspace"}},
+			EntityInBaseVersion: &tagmanager.Entity{Tag: &tagmanager.Tag{TagId: "4", Name: "purchase", Notes: "base"}},
+		},
+		{
+			EntityInWorkspace: &tagmanager.Entity{Trigger: &tagmanager.Trigger{TriggerId: "7", Name: "checkout"}},
+		},
+	}
+
+	var resolved []*tagmanager.Entity
+	resolve := func(entity *tagmanager.Entity) error {
+		resolved = append(resolved, entity)
+		return nil
+	}
+
+	var diags diag.Diagnostics
+	unresolved := resolveMergeConflicts(conflicts, conflictResolutionWorkspace, resolve, &diags)
+	if diags.HasError() || len(unresolved) != 0 || len(resolved) != 2 || resolved[0].Tag.Notes != "workspace" {
+		t.Fatalf("expected both conflicts to be resolved with the workspace entities, got %v, %v and %v", resolved, unresolved, diags)
+	}
+
+	resolved = nil
+	unresolved = resolveMergeConflicts(conflicts, conflictResolutionBaseVersion, resolve, &diags)
+	if len(resolved) != 1 || resolved[0].Tag.Notes != "base" || len(unresolved) != 1 || unresolved[0] != conflicts[1] {
+		t.Fatalf("expected the conflict without a base version entity to be left unresolved, got %v and %v", resolved, unresolved)
+	}
+
+	unresolved = resolveMergeConflicts(conflicts[:1], conflictResolutionWorkspace, func(*tagmanager.Entity) error {
+		return errors.New("fingerprint mismatch")
+	}, &diags)
+	if !diags.HasError() || len(unresolved) != 1 {
+		t.Fatalf("expected a failed resolution to be reported and left unresolved, got %v and %v", unresolved, diags)
+	}
+}
+
+// Test that a workspace is behind when a container version newer than the one it was
+// last synced with exists
+func TestWorkspaceBehind(t *testing.T) {
+	if !workspaceBehind(types.StringNull(), "5") {
+		t.Fatal("expected a workspace that was never synced to be behind")
+	}
+
+	if !workspaceBehind(types.StringValue("5"), "6") {
+		t.Fatal("expected a workspace synced with an older version to be behind")
+	}
+
+	if workspaceBehind(types.StringValue("6"), "6") {
+		t.Fatal("expected a workspace synced with the latest version to be current")
+	}
+
+	if workspaceBehind(types.StringNull(), "") {
+		t.Fatal("expected a container without versions to need no sync")
+	}
+}
+
+// Test that keep_synced syncs a workspace that fell behind a newly created version
+func TestAccWorkspaceResource_keepSynced(t *testing.T) {
+	testAccPreCheck(t)
+	ctx := Context(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: ProtoV6ProviderFactories(ctx, ProviderNameEcho),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWorkspaceKeepSyncedConfig(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gtm_workspace.synced", "keep_synced", "true"),
+					resource.TestMatchResourceAttr("gtm_workspace.synced", "tag_manager_url", regexp.MustCompile(`^https://tagmanager\.google\.com/#/container/accounts/\d+/containers/\d+/workspaces/\d+`)),
+					resource.TestCheckResourceAttrPair("gtm_workspace.synced", "synced_version_id", "data.gtm_latest_version.test", "id"),
+				),
+			},
+			{
+				// The new version leaves the workspace behind, so the plan after the
+				// apply wants to sync it
+				Config:             testAccWorkspaceKeepSyncedConfig(testAccWorkspaceKeepSyncedVersion),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccWorkspaceKeepSyncedConfig(testAccWorkspaceKeepSyncedVersion),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gtm_workspace.synced", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttrPair("gtm_workspace.synced", "synced_version_id", "gtm_version.bump", "id"),
+			},
+		},
+	})
+}
+
+const testAccWorkspaceKeepSyncedVersion = `
+resource "gtm_version" "bump" {
+  name = "tf-test-keep-synced"
+}
+`
+
+func testAccWorkspaceKeepSyncedConfig(extra string) string {
+	return testAccProviderConfig() + `
+data "gtm_latest_version" "test" {}
+
+resource "gtm_workspace" "synced" {
+  name        = "tf-test-keep-synced"
+  keep_synced = true
+}
+` + extra
+}